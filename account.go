@@ -0,0 +1,85 @@
+package ses
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+type GetAccountSendingEnabledResult struct {
+	Enabled bool
+}
+
+type GetAccountSendingEnabledResponse struct {
+	GetAccountSendingEnabledResult GetAccountSendingEnabledResult
+}
+
+// GetAccountSendingEnabled reports whether email sending is enabled for the account in the
+// current region.
+func (c *Config) GetAccountSendingEnabled() (bool, error) {
+	return c.GetAccountSendingEnabledContext(context.Background())
+}
+
+// GetAccountSendingEnabledContext is like GetAccountSendingEnabled but uses ctx for the request
+// and any retries.
+func (c *Config) GetAccountSendingEnabledContext(ctx context.Context) (bool, error) {
+	data := make(url.Values)
+	data.Add("Action", "GetAccountSendingEnabled")
+
+	res := GetAccountSendingEnabledResponse{}
+	err := c.call(ctx, "GET", data, &res)
+	return res.GetAccountSendingEnabledResult.Enabled, err
+}
+
+// UpdateAccountSendingEnabled enables or disables email sending for the account in the current
+// region. Disabling it pauses all outbound mail, for example when a bad campaign is detected.
+func (c *Config) UpdateAccountSendingEnabled(enabled bool) error {
+	return c.UpdateAccountSendingEnabledContext(context.Background(), enabled)
+}
+
+// UpdateAccountSendingEnabledContext is like UpdateAccountSendingEnabled but uses ctx for the
+// request and any retries.
+func (c *Config) UpdateAccountSendingEnabledContext(ctx context.Context, enabled bool) error {
+	data := make(url.Values)
+	data.Add("Action", "UpdateAccountSendingEnabled")
+	data.Add("Enabled", strconv.FormatBool(enabled))
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+// Account describes the SES account in the current region, as returned by the SESv2 GetAccount
+// API.
+type Account struct {
+	SendingEnabled          bool
+	ProductionAccessEnabled bool
+
+	// EnforcementStatus is "HEALTHY", "PROBATION" or "SHUTDOWN".
+	EnforcementStatus string
+
+	DedicatedIpAutoWarmupEnabled bool
+
+	SendQuota struct {
+		Max24HourSend   float64
+		MaxSendRate     float64
+		SentLast24Hours float64
+	}
+
+	SuppressionAttributes struct {
+		// SuppressedReasons are the reasons (SuppressionReasonBounce and
+		// SuppressionReasonComplaint) for which SES automatically adds addresses to the
+		// account suppression list.
+		SuppressedReasons []string
+	}
+}
+
+// GetAccount returns the account's sending status, quota and suppression settings.
+func (c *Config) GetAccount() (Account, error) {
+	return c.GetAccountContext(context.Background())
+}
+
+// GetAccountContext is like GetAccount but uses ctx for the request and any retries.
+func (c *Config) GetAccountContext(ctx context.Context) (Account, error) {
+	res := Account{}
+	err := c.callV2(ctx, "GetAccount", "GET", "/account", nil, nil, &res)
+	return res, err
+}
@@ -0,0 +1,46 @@
+package ses
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetAccountSendingEnabled(t *testing.T) {
+	c, form := testServer(t, `<GetAccountSendingEnabledResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <GetAccountSendingEnabledResult>
+    <Enabled>true</Enabled>
+  </GetAccountSendingEnabledResult>
+</GetAccountSendingEnabledResponse>`)
+	enabled, err := c.GetAccountSendingEnabled()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !enabled {
+		t.Error("got disabled, want enabled")
+	}
+	checkForm(t, *form, map[string]string{"Action": "GetAccountSendingEnabled"})
+}
+
+func TestUpdateAccountSendingEnabled(t *testing.T) {
+	c, form := testServer(t, `<UpdateAccountSendingEnabledResponse/>`)
+	if err := c.UpdateAccountSendingEnabled(false); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"Action": "UpdateAccountSendingEnabled", "Enabled": "false"})
+}
+
+func TestGetAccount(t *testing.T) {
+	c := testV2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/v2/email/account" {
+			t.Errorf("got %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"DedicatedIpAutoWarmupEnabled":true,"EnforcementStatus":"HEALTHY","ProductionAccessEnabled":true,"SendQuota":{"Max24HourSend":50000.0,"MaxSendRate":14.0,"SentLast24Hours":12.0},"SendingEnabled":true,"SuppressionAttributes":{"SuppressedReasons":["BOUNCE","COMPLAINT"]}}`))
+	})
+	a, err := c.GetAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.SendingEnabled || !a.ProductionAccessEnabled || a.EnforcementStatus != "HEALTHY" || a.SendQuota.MaxSendRate != 14 || len(a.SuppressionAttributes.SuppressedReasons) != 2 {
+		t.Errorf("got %+v", a)
+	}
+}
@@ -0,0 +1,130 @@
+// Package admin provides an HTTP handler for inspecting an SES account while on call: its
+// sending quota, the depth of an outbound queue, and the account suppression list, with an
+// action to remove addresses from the suppression list. Mount it under a prefix with http.StripPrefix:
+//
+//	http.Handle("/email-admin/", http.StripPrefix("/email-admin", &admin.Handler{SES: &ses.EnvConfig}))
+//
+// The handler does no authentication of its own, so it must only be mounted behind
+// authentication that admits operators.
+package admin
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+	"github.com/sourcegraph/go-ses/queue"
+)
+
+// A Handler serves the admin pages for the account of SES.
+type Handler struct {
+	SES *ses.Config
+
+	// Queue, if non-nil, is the outbound queue whose length is shown.
+	Queue *queue.Queue
+
+	// PageSize is the number of suppression list entries shown per page. If 0, 100 is used.
+	PageSize int
+}
+
+type page struct {
+	Quota        ses.GetSendQuotaResult
+	QuotaErr     error
+	HasQueue     bool
+	Queued       int
+	QueueErr     error
+	Suppressed   []ses.SuppressedDestination
+	SuppressErr  error
+	NextToken    string
+	Reason       string
+	Unsuppressed string
+}
+
+var pageTemplate = template.Must(template.New("admin").Funcs(template.FuncMap{
+	"percent": func(a, b float64) float64 {
+		if b == 0 {
+			return 0
+		}
+		return 100 * a / b
+	},
+	"time": func(t time.Time) string { return t.Format("2006-01-02 15:04 MST") },
+}).Parse(`<!DOCTYPE html>
+<html><head><title>Email admin</title></head>
+<body>
+<h1>Email admin</h1>
+{{with .Unsuppressed}}<p>Removed {{.}} from the suppression list.</p>{{end}}
+<h2>Quota</h2>
+{{if .QuotaErr}}<p>Error: {{.QuotaErr}}</p>{{else}}{{with .Quota}}
+<p>{{.SentLast24Hours}} of {{.Max24HourSend}} sent in the last 24 hours ({{printf "%.1f" (percent .SentLast24Hours .Max24HourSend)}}%), at up to {{.MaxSendRate}} per second.</p>
+{{end}}{{end}}
+{{if .HasQueue}}<h2>Queue</h2>
+{{if .QueueErr}}<p>Error: {{.QueueErr}}</p>{{else}}<p>{{.Queued}} messages queued.</p>{{end}}
+{{end}}<h2>Suppression list</h2>
+<p><a href="?">All</a> | <a href="?reason=BOUNCE">Bounces</a> | <a href="?reason=COMPLAINT">Complaints</a></p>
+{{if .SuppressErr}}<p>Error: {{.SuppressErr}}</p>{{else}}
+<table>
+<tr><th>Address</th><th>Reason</th><th>Updated</th><th></th></tr>
+{{range .Suppressed}}<tr><td>{{.EmailAddress}}</td><td>{{.Reason}}</td><td>{{time .LastUpdateTime}}</td>
+<td><form method="post" action="unsuppress"><input type="hidden" name="email" value="{{.EmailAddress}}"><button>Unsuppress</button></form></td></tr>
+{{else}}<tr><td colspan="4">No suppressed addresses.</td></tr>{{end}}
+</table>
+{{with .NextToken}}<p><a href="?reason={{$.Reason}}&amp;next={{.}}">Next page</a></p>{{end}}
+{{end}}
+</body></html>
+`))
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "", "/":
+		h.serveIndex(w, r)
+	case "/unsuppress":
+		h.serveUnsuppress(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) serveIndex(w http.ResponseWriter, r *http.Request) {
+	p := page{Reason: r.FormValue("reason"), Unsuppressed: r.FormValue("unsuppressed")}
+	p.Quota, p.QuotaErr = h.SES.GetSendQuotaContext(r.Context())
+	if h.Queue != nil {
+		p.HasQueue = true
+		p.Queued, p.QueueErr = h.Queue.Len()
+	}
+
+	var filter ses.SuppressedDestinationFilter
+	if p.Reason != "" {
+		filter.Reasons = []string{p.Reason}
+	}
+	pageSize := h.PageSize
+	if pageSize == 0 {
+		pageSize = 100
+	}
+	res, err := h.SES.ListSuppressedDestinationsContext(r.Context(), filter, pageSize, r.FormValue("next"))
+	p.Suppressed, p.NextToken, p.SuppressErr = res.SuppressedDestinationSummaries, res.NextToken, err
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	pageTemplate.Execute(w, p)
+}
+
+func (h *Handler) serveUnsuppress(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// Refuse cross-site form posts.
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if u, err := url.Parse(origin); err != nil || u.Host != r.Host {
+			http.Error(w, "cross-origin request refused", http.StatusForbidden)
+			return
+		}
+	}
+	email := r.FormValue("email")
+	if err := h.SES.DeleteSuppressedDestinationContext(r.Context(), email); err != nil {
+		http.Error(w, "unsuppressing "+email+": "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	http.Redirect(w, r, "./?unsuppressed="+url.QueryEscape(email), http.StatusSeeOther)
+}
@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+	"github.com/sourcegraph/go-ses/queue"
+)
+
+func TestHandler(t *testing.T) {
+	var deleted string
+	sesSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/email/suppression/addresses" && r.Method == "GET":
+			w.Write([]byte(`{"SuppressedDestinationSummaries":[{"EmailAddress":"bounced@example.com","Reason":"BOUNCE","LastUpdateTime":1577836800}],"NextToken":"p2"}`))
+		case strings.HasPrefix(r.URL.Path, "/v2/email/suppression/addresses/") && r.Method == "DELETE":
+			deleted = strings.TrimPrefix(r.URL.Path, "/v2/email/suppression/addresses/")
+			w.Write([]byte(`{}`))
+		default:
+			r.ParseForm()
+			if r.Form.Get("Action") == "GetSendQuota" {
+				w.Write([]byte(`<GetSendQuotaResponse><GetSendQuotaResult><SentLast24Hours>50</SentLast24Hours><Max24HourSend>200</Max24HourSend><MaxSendRate>1</MaxSendRate></GetSendQuotaResult></GetSendQuotaResponse>`))
+				return
+			}
+			http.NotFound(w, r)
+		}
+	}))
+	defer sesSrv.Close()
+
+	mux := http.NewServeMux()
+	c := &ses.Config{Endpoint: sesSrv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET"}
+	q := &queue.Queue{SES: c, Store: &queue.MemoryStore{}}
+	for i := 0; i < 3; i++ {
+		if _, err := q.Enqueue(queue.Message{From: "a@example.com", To: "b@example.com", Subject: "s", Text: "b"}, time.Time{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mux.Handle("/email-admin/", http.StripPrefix("/email-admin", &Handler{SES: c, Queue: q}))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/email-admin/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	for _, want := range []string{"50 of 200 sent", "25.0%", "3 messages queued", "bounced@example.com", "next=p2"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("page does not contain %q:\n%s", want, body)
+		}
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err = client.PostForm(srv.URL+"/email-admin/unsuppress", url.Values{"email": {"bounced@example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSeeOther || deleted != "bounced@example.com" {
+		t.Errorf("unsuppress: got status %d, deleted %q", resp.StatusCode, deleted)
+	}
+
+	req, _ := http.NewRequest("POST", srv.URL+"/email-admin/unsuppress", strings.NewReader("email=x%40example.com"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Origin", "https://evil.example.com")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("cross-origin unsuppress: got status %d", resp.StatusCode)
+	}
+}
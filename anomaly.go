@@ -0,0 +1,129 @@
+package ses
+
+import (
+	"context"
+	"time"
+)
+
+// Kinds of Anomaly.
+const (
+	AnomalyBounceSpike    = "bounce_spike"
+	AnomalyComplaintSurge = "complaint_surge"
+	AnomalyVolumeCollapse = "volume_collapse"
+)
+
+// Anomaly is a sudden change in a window of sending statistics, compared with the windows
+// before it. Value and Baseline are the bounce rate, complaint rate or delivery attempts of the
+// window and the average of the windows before it.
+type Anomaly struct {
+	Kind     string
+	Window   SendRates
+	Value    float64
+	Baseline float64
+}
+
+// AnomalyDetector detects bounce spikes, complaint surges and volume collapses in the
+// statistics returned by GetSendStatistics, giving early warning of reputation problems and of
+// integrations that have stopped sending. Zero fields take the defaults given.
+type AnomalyDetector struct {
+	// Window is the length of the windows compared. Default: 1 hour.
+	Window time.Duration
+
+	// Baseline is the number of windows before the latest one that it is compared with.
+	// Default: 24.
+	Baseline int
+
+	// SpikeFactor is how many times its baseline a bounce or complaint rate must be to be a
+	// spike, and MinBounceRate and MinComplaintRate are the rates below which no spike is
+	// reported. Defaults: 3, 2% and 0.05%.
+	SpikeFactor      float64
+	MinBounceRate    float64
+	MinComplaintRate float64
+
+	// CollapseFactor is the fraction of its baseline below which the number of delivery
+	// attempts is a collapse, and MinVolume is the baseline number of attempts per window
+	// below which no collapse is reported. Defaults: 0.2 and 100.
+	CollapseFactor float64
+	MinVolume      int
+
+	// OnAnomaly, if non-nil, is called by Run with each anomaly found.
+	OnAnomaly func(Anomaly)
+}
+
+// Detect returns the anomalies of the latest complete window of points (the one ending at or
+// before now).
+func (d *AnomalyDetector) Detect(points []SendDataPoint, now time.Time) []Anomaly {
+	window := d.window()
+	end := now.UTC().Truncate(window)
+	latest := SumStatistics(points, end.Add(-window), end)
+
+	n := d.Baseline
+	if n == 0 {
+		n = 24
+	}
+	base := SumStatistics(points, end.Add(-time.Duration(n+1)*window), end.Add(-window))
+
+	var anomalies []Anomaly
+	factor := valueOr(d.SpikeFactor, 3)
+	if r, b := latest.BounceRate(), base.BounceRate(); r >= valueOr(d.MinBounceRate, 0.02) && r > factor*b {
+		anomalies = append(anomalies, Anomaly{AnomalyBounceSpike, latest, r, b})
+	}
+	if r, b := latest.ComplaintRate(), base.ComplaintRate(); r >= valueOr(d.MinComplaintRate, 0.0005) && r > factor*b {
+		anomalies = append(anomalies, Anomaly{AnomalyComplaintSurge, latest, r, b})
+	}
+	minVolume := d.MinVolume
+	if minVolume == 0 {
+		minVolume = 100
+	}
+	avg := float64(base.DeliveryAttempts) / float64(n)
+	if v := float64(latest.DeliveryAttempts); avg >= float64(minVolume) && v < valueOr(d.CollapseFactor, 0.2)*avg {
+		anomalies = append(anomalies, Anomaly{AnomalyVolumeCollapse, latest, v, avg})
+	}
+	return anomalies
+}
+
+// Run polls c.GetSendStatisticsContext every interval until ctx is done, and passes the
+// anomalies of each newly completed window to d.OnAnomaly. It also logs them as warnings to
+// c.Logger. Failed polls are logged and retried at the next interval. It returns ctx's error.
+func (d *AnomalyDetector) Run(ctx context.Context, c *Config, interval time.Duration) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	var last time.Time
+	for {
+		now := time.Now()
+		if end := now.UTC().Truncate(d.window()); end.After(last) {
+			points, err := c.GetSendStatisticsContext(ctx)
+			if err != nil {
+				c.log(LogError, "polling send statistics failed", "error", err)
+			} else {
+				last = end
+				for _, a := range d.Detect(points, now) {
+					c.log(LogWarn, "send statistics anomaly", "kind", a.Kind, "window", a.Window.Start, "value", a.Value, "baseline", a.Baseline)
+					if d.OnAnomaly != nil {
+						d.OnAnomaly(a)
+					}
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func (d *AnomalyDetector) window() time.Duration {
+	if d.Window == 0 {
+		return time.Hour
+	}
+	return d.Window
+}
+
+// valueOr returns v, or def if v is 0.
+func valueOr(v, def float64) float64 {
+	if v == 0 {
+		return def
+	}
+	return v
+}
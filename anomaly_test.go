@@ -0,0 +1,75 @@
+package ses
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// hourlyPoints returns data points for the 24 hours before end, with the given counts in the
+// last hour and 1000 attempts, 10 bounces and no complaints in each earlier hour.
+func hourlyPoints(end time.Time, attempts, bounces, complaints int) []SendDataPoint {
+	var points []SendDataPoint
+	for h := 24; h >= 2; h-- {
+		points = append(points, SendDataPoint{DeliveryAttempts: 1000, Bounces: 10, Timestamp: end.Add(-time.Duration(h) * time.Hour)})
+	}
+	return append(points, SendDataPoint{DeliveryAttempts: attempts, Bounces: bounces, Complaints: complaints, Timestamp: end.Add(-45 * time.Minute)})
+}
+
+func TestAnomalyDetector(t *testing.T) {
+	end := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := end.Add(10 * time.Minute)
+	d := AnomalyDetector{Baseline: 23}
+	tests := []struct {
+		name   string
+		points []SendDataPoint
+		kinds  []string
+	}{
+		{"normal", hourlyPoints(end, 1000, 12, 0), nil},
+		{"bounce spike", hourlyPoints(end, 1000, 80, 0), []string{AnomalyBounceSpike}},
+		{"complaint surge", hourlyPoints(end, 1000, 10, 3), []string{AnomalyComplaintSurge}},
+		{"volume collapse", hourlyPoints(end, 50, 0, 0), []string{AnomalyVolumeCollapse}},
+	}
+	for _, tt := range tests {
+		var kinds []string
+		for _, a := range d.Detect(tt.points, now) {
+			kinds = append(kinds, a.Kind)
+		}
+		if fmt.Sprint(kinds) != fmt.Sprint(tt.kinds) {
+			t.Errorf("%s: got %v, want %v", tt.name, kinds, tt.kinds)
+		}
+	}
+
+	a := d.Detect(hourlyPoints(end, 1000, 80, 0), now)[0]
+	if a.Value != 0.08 || a.Baseline != 0.01 || !a.Window.Start.Equal(end.Add(-time.Hour)) {
+		t.Errorf("got %+v", a)
+	}
+}
+
+func TestAnomalyDetectorRun(t *testing.T) {
+	end := time.Now().UTC().Truncate(time.Hour)
+	var body string
+	for _, p := range hourlyPoints(end, 1000, 80, 0) {
+		body += fmt.Sprintf("<member><DeliveryAttempts>%d</DeliveryAttempts><Bounces>%d</Bounces><Complaints>0</Complaints><Rejects>0</Rejects><Timestamp>%s</Timestamp></member>",
+			p.DeliveryAttempts, p.Bounces, p.Timestamp.Format(time.RFC3339))
+	}
+	var polls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		fmt.Fprintf(w, "<GetSendStatisticsResponse><GetSendStatisticsResult><SendDataPoints>%s</SendDataPoints></GetSendStatisticsResult></GetSendStatisticsResponse>", body)
+	}))
+	defer srv.Close()
+	c := &Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET"}
+
+	var anomalies []Anomaly
+	d := AnomalyDetector{Baseline: 23, OnAnomaly: func(a Anomaly) { anomalies = append(anomalies, a) }}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	d.Run(ctx, c, 10*time.Millisecond)
+	if polls != 1 || len(anomalies) != 1 || anomalies[0].Kind != AnomalyBounceSpike {
+		t.Errorf("got %d polls and anomalies %+v, want one poll and a bounce spike", polls, anomalies)
+	}
+}
@@ -0,0 +1,23 @@
+package ses
+
+import "context"
+
+// SenderAPI is the interface of the methods of Config that send email and report on sending.
+// Code that sends email can accept a SenderAPI instead of a *Config, so that tests can pass a
+// fake implementation (or a Config pointed at a sestest.Server).
+type SenderAPI interface {
+	SendEmail(from, to, subject, body string, opts ...SendOption) (string, error)
+	SendEmailContext(ctx context.Context, from, to, subject, body string, opts ...SendOption) (string, error)
+	SendEmailHTML(from, to, subject, bodyText, bodyHTML string, opts ...SendOption) (string, error)
+	SendEmailHTMLContext(ctx context.Context, from, to, subject, bodyText, bodyHTML string, opts ...SendOption) (string, error)
+	SendRawEmail(raw []byte, opts ...SendOption) (string, error)
+	SendRawEmailContext(ctx context.Context, raw []byte, opts ...SendOption) (string, error)
+	SendRawEmailEnvelope(ctx context.Context, from string, to []string, raw []byte, opts ...SendOption) (string, error)
+
+	GetSendQuota() (GetSendQuotaResult, error)
+	GetSendQuotaContext(ctx context.Context) (GetSendQuotaResult, error)
+	GetSendStatistics() ([]SendDataPoint, error)
+	GetSendStatisticsContext(ctx context.Context) ([]SendDataPoint, error)
+}
+
+var _ SenderAPI = (*Config)(nil)
@@ -0,0 +1,171 @@
+package ses
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrMessageExpired is the BatchResult error of a message that was not sent before its Expires
+// time.
+var ErrMessageExpired = errors.New("ses: message expired before it was sent")
+
+// A Message is an email to send with a BatchSender.
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Text    string
+
+	// HTML, if non-empty, is sent as the HTML body alongside Text (see SendEmailHTML).
+	HTML string
+
+	// Raw, if non-nil, is the complete message to send with SendRawEmail. The other fields
+	// are ignored.
+	Raw []byte
+
+	Options []SendOption
+
+	// Expires, if non-zero, is the time after which the message is no longer worth sending,
+	// for example because it contains a one-time code. A BatchSender drops a message that has
+	// not been sent by then (including while it is being rate limited or retried) and reports
+	// ErrMessageExpired.
+	Expires time.Time
+}
+
+// send sends m using c and returns the SES response.
+func (m *Message) send(ctx context.Context, c *Config) (string, error) {
+	switch {
+	case m.Raw != nil:
+		return c.SendRawEmailContext(ctx, m.Raw, m.Options...)
+	case m.HTML != "":
+		return c.SendEmailHTMLContext(ctx, m.From, m.To, m.Subject, m.Text, m.HTML, m.Options...)
+	default:
+		return c.SendEmailContext(ctx, m.From, m.To, m.Subject, m.Text, m.Options...)
+	}
+}
+
+// sendBefore sends m using c, giving up with ErrMessageExpired if m expires first.
+func (m *Message) sendBefore(ctx context.Context, c *Config) (string, error) {
+	if m.Expires.IsZero() {
+		return m.send(ctx, c)
+	}
+	if !time.Now().Before(m.Expires) {
+		return "", ErrMessageExpired
+	}
+	mctx, cancel := context.WithDeadline(ctx, m.Expires)
+	defer cancel()
+	res, err := m.send(mctx, c)
+	if err != nil && ctx.Err() == nil && mctx.Err() != nil {
+		return "", ErrMessageExpired
+	}
+	return res, err
+}
+
+// BatchResult is the outcome of sending one message of a batch.
+type BatchResult struct {
+	// Index is the position of the message in the batch.
+	Index int
+
+	// MessageID is the SES message ID of the sent message. It is empty if Err is non-nil.
+	MessageID string
+
+	Err error
+}
+
+// A BatchSender sends many messages concurrently. The rate of sends is limited by the
+// Config's RateLimiter, if any, and transient failures are retried according to its
+// RetryPolicy, or DefaultRetryPolicy if it has none.
+type BatchSender struct {
+	Config *Config
+
+	// Workers is the number of messages sent concurrently. If less than 1, 1 is used.
+	Workers int
+}
+
+// Send sends msgs and returns their results in the same order. If ctx is done, the messages
+// that were not yet sent fail with ctx's error.
+func (b *BatchSender) Send(ctx context.Context, msgs []Message) []BatchResult {
+	in := make(chan Message)
+	go func() {
+		defer close(in)
+		for _, m := range msgs {
+			in <- m
+		}
+	}()
+
+	results := make([]BatchResult, len(msgs))
+	for r := range b.SendChan(ctx, in) {
+		results[r.Index] = r
+	}
+	return results
+}
+
+// SendChan sends the messages received from msgs until it is closed, and returns a channel of
+// their results. Results are delivered as sends complete, which is not necessarily in the
+// order the messages were received; use BatchResult.Index to match them up. The results
+// channel is closed after the last result.
+func (b *BatchSender) SendChan(ctx context.Context, msgs <-chan Message) <-chan BatchResult {
+	c := *b.Config
+	if c.RetryPolicy == nil {
+		c.RetryPolicy = &DefaultRetryPolicy
+	}
+	workers := b.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	type job struct {
+		index int
+		msg   Message
+	}
+	jobs := make(chan job)
+	go func() {
+		defer close(jobs)
+		i := 0
+		for m := range msgs {
+			jobs <- job{i, m}
+			i++
+		}
+	}()
+
+	results := make(chan BatchResult)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				r := BatchResult{Index: j.index}
+				if err := ctx.Err(); err != nil {
+					r.Err = err
+				} else {
+					var res string
+					res, r.Err = j.msg.sendBefore(ctx, &c)
+					if r.Err == nil {
+						r.MessageID = messageID(res)
+					}
+				}
+				results <- r
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// messageID returns the MessageId in the response to a send call, or "" if there is none.
+func messageID(res string) string {
+	var v struct {
+		Result struct {
+			MessageID string `xml:"MessageId"`
+		} `xml:",any"`
+	}
+	xml.Unmarshal([]byte(res), &v)
+	return v.Result.MessageID
+}
@@ -0,0 +1,123 @@
+package ses
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatchSender(t *testing.T) {
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	var active, maxActive int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		to := r.Form.Get("Destination.ToAddresses.member.1")
+		mu.Lock()
+		attempts[to]++
+		n := attempts[to]
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			active--
+			mu.Unlock()
+		}()
+		time.Sleep(5 * time.Millisecond)
+
+		switch {
+		case to == "throttled@example.com" && n == 1:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(throttlingResponse))
+		case to == "bad@example.com":
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			fmt.Fprintf(w, "<SendEmailResponse><SendEmailResult><MessageId>id-%s</MessageId></SendEmailResult></SendEmailResponse>", to)
+		}
+	}))
+	defer srv.Close()
+
+	var msgs []Message
+	for i := 0; i < 8; i++ {
+		msgs = append(msgs, Message{From: "a@example.com", To: fmt.Sprintf("%d@example.com", i), Subject: "s", Text: "b"})
+	}
+	msgs = append(msgs, Message{From: "a@example.com", To: "throttled@example.com", Subject: "s", Text: "b"}, Message{From: "a@example.com", To: "bad@example.com", Subject: "s", Text: "b"})
+
+	b := BatchSender{
+		Config:  &Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET", RetryPolicy: &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}},
+		Workers: 3,
+	}
+	results := b.Send(context.Background(), msgs)
+	if len(results) != len(msgs) {
+		t.Fatalf("got %d results, want %d", len(results), len(msgs))
+	}
+	for i, r := range results[:9] {
+		if r.Index != i || r.Err != nil || r.MessageID != "id-"+msgs[i].To {
+			t.Errorf("result %d: got %+v", i, r)
+		}
+	}
+	if r := results[9]; r.Err == nil || r.MessageID != "" {
+		t.Errorf("got %+v, want error for bad recipient", r)
+	}
+	if maxActive > 3 {
+		t.Errorf("got %d concurrent sends, want at most 3", maxActive)
+	}
+}
+
+func TestBatchSenderCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b := BatchSender{Config: &Config{Endpoint: "http://localhost:0", AccessKeyID: "AKID"}}
+	for _, r := range b.Send(ctx, make([]Message, 3)) {
+		if r.Err != context.Canceled {
+			t.Errorf("got %+v, want %v", r, context.Canceled)
+		}
+	}
+}
+
+func TestBatchSenderExpires(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		to := r.Form.Get("Destination.ToAddresses.member.1")
+		if to == "throttled@example.com" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(throttlingResponse))
+			return
+		}
+		mu.Lock()
+		sent = append(sent, to)
+		mu.Unlock()
+		fmt.Fprint(w, "<SendEmailResponse><SendEmailResult><MessageId>id</MessageId></SendEmailResult></SendEmailResponse>")
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	msgs := []Message{
+		{From: "a@example.com", To: "stale@example.com", Subject: "s", Text: "b", Expires: now.Add(-time.Second)},
+		{From: "a@example.com", To: "fresh@example.com", Subject: "s", Text: "b", Expires: now.Add(time.Hour)},
+		{From: "a@example.com", To: "throttled@example.com", Subject: "s", Text: "b", Expires: now.Add(50 * time.Millisecond)},
+		{From: "a@example.com", To: "forever@example.com", Subject: "s", Text: "b"},
+	}
+	b := BatchSender{
+		Config: &Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET", RetryPolicy: &RetryPolicy{MaxAttempts: 10, BaseDelay: 20 * time.Millisecond}},
+	}
+	results := b.Send(context.Background(), msgs)
+
+	for i, want := range []error{ErrMessageExpired, nil, ErrMessageExpired, nil} {
+		if results[i].Err != want {
+			t.Errorf("message %d: got error %v, want %v", i, results[i].Err, want)
+		}
+	}
+	if len(sent) != 2 || sent[0] != "fresh@example.com" || sent[1] != "forever@example.com" {
+		t.Errorf("got sent %v", sent)
+	}
+}
@@ -0,0 +1,128 @@
+// Command ses-sendmail is a minimal sendmail replacement that sends the message read from
+// standard input through Amazon SES, like "sendmail -t". The envelope recipients are taken
+// from the message's To, Cc and Bcc headers and the command-line arguments. The envelope sender
+// is given by -f (or -r), or else taken from the message's Sender or From header. If the
+// message has no From header, one is added from -f and the full name given by -F.
+//
+// The other sendmail options that programs such as PHP's mail(), cron and mutt pass are
+// accepted and ignored: -t, -i, -v, -m, -n, -U, -G, the -o* family, -bm, and -B, -C, -L, -N,
+// -O, -R, -V, -X and -h with their arguments. Other modes (such as -bp) are not supported.
+//
+// Credentials, region and endpoint are read from the environment, as for ses.EnvConfig.
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/mail"
+	"os"
+	"strings"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+const usage = "usage: ses-sendmail [-t] [-i] [-f sender] [-F name] [-o option] [recipient ...] < message"
+
+// options are the command-line options that ses-sendmail uses.
+type options struct {
+	from       string // -f or -r
+	fullName   string // -F
+	recipients []string
+}
+
+func main() {
+	opts, err := parseArgs(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ses-sendmail: %s\n%s\n", err, usage)
+		os.Exit(2)
+	}
+	raw, err := ioutil.ReadAll(os.Stdin)
+	if err == nil {
+		raw, err = addFrom(raw, opts.fullName, opts.from)
+	}
+	if err == nil {
+		c := ses.EnvConfig
+		_, err = c.SendmailFrom(context.Background(), opts.from, bytes.NewReader(raw), opts.recipients...)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ses-sendmail: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseArgs parses sendmail command-line arguments. Option values may be attached ("-fsender")
+// or given as the next argument ("-f sender"). The first argument that is not an option, or
+// follows "--", starts the recipients.
+func parseArgs(args []string) (options, error) {
+	var opts options
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			opts.recipients = append(opts.recipients, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(a, "-") || a == "-" {
+			opts.recipients = append(opts.recipients, args[i:]...)
+			break
+		}
+		flag, val := a[1:2], a[2:]
+		// value returns the option's value, consuming the next argument if it isn't attached.
+		value := func() (string, error) {
+			if val != "" {
+				return val, nil
+			}
+			if i+1 == len(args) {
+				return "", fmt.Errorf("option -%s requires an argument", flag)
+			}
+			i++
+			return args[i], nil
+		}
+		var err error
+		switch flag {
+		case "t", "i", "v", "m", "n", "U", "G":
+			if val != "" {
+				return opts, fmt.Errorf("unknown option %s", a)
+			}
+		case "o":
+			// -oi, -odb, -oem and the like; a bare -o takes the option as the next argument.
+			if val == "" {
+				_, err = value()
+			}
+		case "b":
+			if val != "m" {
+				return opts, fmt.Errorf("unsupported mode %s", a)
+			}
+		case "f", "r":
+			opts.from, err = value()
+		case "F":
+			opts.fullName, err = value()
+		case "B", "C", "L", "N", "O", "R", "V", "X", "h":
+			_, err = value()
+		default:
+			return opts, fmt.Errorf("unknown option %s", a)
+		}
+		if err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+// addFrom returns raw with a From header for name and addr added if it has none and addr is
+// non-empty.
+func addFrom(raw []byte, name, addr string) ([]byte, error) {
+	if addr == "" {
+		return raw, nil
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	if msg.Header.Get("From") != "" {
+		return raw, nil
+	}
+	from := (&mail.Address{Name: name, Address: addr}).String()
+	return append([]byte("From: "+from+"\r\n"), raw...), nil
+}
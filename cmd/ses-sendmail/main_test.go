@@ -0,0 +1,52 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want options
+	}{
+		{[]string{"-oi", "-t"}, options{}},
+		{[]string{"-t", "-i", "-odb", "-oem", "b@example.com"}, options{recipients: []string{"b@example.com"}}},
+		{[]string{"-fbounces@example.com", "-FCron Daemon", "-bm", "b@example.com", "c@example.com"}, options{from: "bounces@example.com", fullName: "Cron Daemon", recipients: []string{"b@example.com", "c@example.com"}}},
+		{[]string{"-f", "a@example.com", "-F", "Alice", "-N", "never", "--", "-b@example.com"}, options{from: "a@example.com", fullName: "Alice", recipients: []string{"-b@example.com"}}},
+		{[]string{"-r", "a@example.com", "-O", "DeliveryMode=b", "-o", "i"}, options{from: "a@example.com"}},
+	}
+	for _, test := range tests {
+		got, err := parseArgs(test.args)
+		if err != nil {
+			t.Errorf("%q: %v", test.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %+v, want %+v", test.args, got, test.want)
+		}
+	}
+
+	for _, args := range [][]string{{"-bp"}, {"-q"}, {"-tx"}, {"-f"}} {
+		if _, err := parseArgs(args); err == nil {
+			t.Errorf("%q: want error", args)
+		}
+	}
+}
+
+func TestAddFrom(t *testing.T) {
+	raw := []byte("To: b@example.com\r\nSubject: Cron\r\n\r\nbody")
+	got, err := addFrom(raw, "Cron Daemon", "root@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `From: "Cron Daemon" <root@example.com>` + "\r\n" + string(raw); string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	withFrom := []byte("From: a@example.com\r\n" + string(raw))
+	if got, _ := addFrom(withFrom, "Cron Daemon", "root@example.com"); !strings.HasPrefix(string(got), "From: a@example.com\r\n") || len(got) != len(withFrom) {
+		t.Errorf("existing From header was changed: %q", got)
+	}
+}
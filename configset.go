@@ -0,0 +1,274 @@
+package ses
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Event types that can be published to an event destination.
+const (
+	EventTypeSend             = "send"
+	EventTypeReject           = "reject"
+	EventTypeBounce           = "bounce"
+	EventTypeComplaint        = "complaint"
+	EventTypeDelivery         = "delivery"
+	EventTypeOpen             = "open"
+	EventTypeClick            = "click"
+	EventTypeRenderingFailure = "renderingFailure"
+)
+
+// EventDestination specifies where the sending events of a configuration set are published.
+// Exactly one of CloudWatchDestination, KinesisFirehoseDestination and SNSDestination must be
+// set.
+type EventDestination struct {
+	Name               string
+	Enabled            bool
+	MatchingEventTypes []string `xml:"MatchingEventTypes>member"`
+
+	CloudWatchDestination      *CloudWatchDestination
+	KinesisFirehoseDestination *KinesisFirehoseDestination
+	SNSDestination             *SNSDestination
+}
+
+type CloudWatchDestination struct {
+	DimensionConfigurations []CloudWatchDimensionConfiguration `xml:"DimensionConfigurations>member"`
+}
+
+type CloudWatchDimensionConfiguration struct {
+	DimensionName string
+
+	// DimensionValueSource is "messageTag", "emailHeader" or "linkTag".
+	DimensionValueSource  string
+	DefaultDimensionValue string
+}
+
+type KinesisFirehoseDestination struct {
+	IAMRoleARN        string
+	DeliveryStreamARN string
+}
+
+type SNSDestination struct {
+	TopicARN string
+}
+
+// addTo adds the parameters for d to data, prefixed by prefix.
+func (d EventDestination) addTo(data url.Values, prefix string) {
+	data.Add(prefix+".Name", d.Name)
+	data.Add(prefix+".Enabled", strconv.FormatBool(d.Enabled))
+	addMembers(data, prefix+".MatchingEventTypes", d.MatchingEventTypes)
+	if cw := d.CloudWatchDestination; cw != nil {
+		for i, dc := range cw.DimensionConfigurations {
+			p := fmt.Sprintf("%s.CloudWatchDestination.DimensionConfigurations.member.%d", prefix, i+1)
+			data.Add(p+".DimensionName", dc.DimensionName)
+			data.Add(p+".DimensionValueSource", dc.DimensionValueSource)
+			data.Add(p+".DefaultDimensionValue", dc.DefaultDimensionValue)
+		}
+	}
+	if kf := d.KinesisFirehoseDestination; kf != nil {
+		data.Add(prefix+".KinesisFirehoseDestination.IAMRoleARN", kf.IAMRoleARN)
+		data.Add(prefix+".KinesisFirehoseDestination.DeliveryStreamARN", kf.DeliveryStreamARN)
+	}
+	if sns := d.SNSDestination; sns != nil {
+		data.Add(prefix+".SNSDestination.TopicARN", sns.TopicARN)
+	}
+}
+
+// CreateConfigurationSet creates a configuration set.
+func (c *Config) CreateConfigurationSet(name string) error {
+	return c.CreateConfigurationSetContext(context.Background(), name)
+}
+
+// CreateConfigurationSetContext is like CreateConfigurationSet but uses ctx for the request and
+// any retries.
+func (c *Config) CreateConfigurationSetContext(ctx context.Context, name string) error {
+	data := make(url.Values)
+	data.Add("Action", "CreateConfigurationSet")
+	data.Add("ConfigurationSet.Name", name)
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+// DeleteConfigurationSet deletes a configuration set and its event destinations.
+func (c *Config) DeleteConfigurationSet(name string) error {
+	return c.DeleteConfigurationSetContext(context.Background(), name)
+}
+
+// DeleteConfigurationSetContext is like DeleteConfigurationSet but uses ctx for the request and
+// any retries.
+func (c *Config) DeleteConfigurationSetContext(ctx context.Context, name string) error {
+	data := make(url.Values)
+	data.Add("Action", "DeleteConfigurationSet")
+	data.Add("ConfigurationSetName", name)
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+type ConfigurationSet struct {
+	Name string
+}
+
+type ListConfigurationSetsResult struct {
+	ConfigurationSets []ConfigurationSet `xml:"ConfigurationSets>member"`
+
+	// NextToken is passed to ListConfigurationSets to fetch the next page. It is empty on the
+	// last page.
+	NextToken string
+}
+
+type ListConfigurationSetsResponse struct {
+	ListConfigurationSetsResult ListConfigurationSetsResult
+}
+
+// ListConfigurationSets returns a page of the configuration sets for the account. maxItems
+// limits the page size (0 means the SES default), and nextToken is the NextToken from the
+// previous page, or empty for the first page.
+func (c *Config) ListConfigurationSets(maxItems int, nextToken string) (ListConfigurationSetsResult, error) {
+	return c.ListConfigurationSetsContext(context.Background(), maxItems, nextToken)
+}
+
+// ListConfigurationSetsContext is like ListConfigurationSets but uses ctx for the request and
+// any retries.
+func (c *Config) ListConfigurationSetsContext(ctx context.Context, maxItems int, nextToken string) (ListConfigurationSetsResult, error) {
+	data := make(url.Values)
+	data.Add("Action", "ListConfigurationSets")
+	if maxItems > 0 {
+		data.Add("MaxItems", strconv.Itoa(maxItems))
+	}
+	if nextToken != "" {
+		data.Add("NextToken", nextToken)
+	}
+
+	res := ListConfigurationSetsResponse{}
+	err := c.call(ctx, "GET", data, &res)
+	return res.ListConfigurationSetsResult, err
+}
+
+type DescribeConfigurationSetResult struct {
+	ConfigurationSet  ConfigurationSet
+	EventDestinations []EventDestination `xml:"EventDestinations>member"`
+	ReputationOptions ReputationOptions
+}
+
+// ReputationOptions are the reputation settings of a configuration set.
+type ReputationOptions struct {
+	// SendingEnabled reports whether messages can be sent using the configuration set.
+	SendingEnabled bool
+
+	// ReputationMetricsEnabled reports whether the bounce and complaint rates of messages sent
+	// using the configuration set are published to CloudWatch.
+	ReputationMetricsEnabled bool
+
+	// LastFreshStart is when the configuration set's reputation metrics were last reset, or
+	// zero if they never were.
+	LastFreshStart time.Time
+}
+
+type DescribeConfigurationSetResponse struct {
+	DescribeConfigurationSetResult DescribeConfigurationSetResult
+}
+
+// DescribeConfigurationSet returns a configuration set, its event destinations and its
+// reputation options.
+func (c *Config) DescribeConfigurationSet(name string) (DescribeConfigurationSetResult, error) {
+	return c.DescribeConfigurationSetContext(context.Background(), name)
+}
+
+// DescribeConfigurationSetContext is like DescribeConfigurationSet but uses ctx for the request
+// and any retries.
+func (c *Config) DescribeConfigurationSetContext(ctx context.Context, name string) (DescribeConfigurationSetResult, error) {
+	data := make(url.Values)
+	data.Add("Action", "DescribeConfigurationSet")
+	data.Add("ConfigurationSetName", name)
+	addMembers(data, "ConfigurationSetAttributeNames", []string{"eventDestinations", "reputationOptions"})
+
+	res := DescribeConfigurationSetResponse{}
+	err := c.call(ctx, "GET", data, &res)
+	return res.DescribeConfigurationSetResult, err
+}
+
+// CreateConfigurationSetEventDestination adds an event destination to a configuration set.
+func (c *Config) CreateConfigurationSetEventDestination(configurationSet string, dest EventDestination) error {
+	return c.CreateConfigurationSetEventDestinationContext(context.Background(), configurationSet, dest)
+}
+
+// CreateConfigurationSetEventDestinationContext is like CreateConfigurationSetEventDestination
+// but uses ctx for the request and any retries.
+func (c *Config) CreateConfigurationSetEventDestinationContext(ctx context.Context, configurationSet string, dest EventDestination) error {
+	data := make(url.Values)
+	data.Add("Action", "CreateConfigurationSetEventDestination")
+	data.Add("ConfigurationSetName", configurationSet)
+	dest.addTo(data, "EventDestination")
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+// UpdateConfigurationSetEventDestination replaces the event destination of a configuration set
+// that has the same name as dest.
+func (c *Config) UpdateConfigurationSetEventDestination(configurationSet string, dest EventDestination) error {
+	return c.UpdateConfigurationSetEventDestinationContext(context.Background(), configurationSet, dest)
+}
+
+// UpdateConfigurationSetEventDestinationContext is like UpdateConfigurationSetEventDestination
+// but uses ctx for the request and any retries.
+func (c *Config) UpdateConfigurationSetEventDestinationContext(ctx context.Context, configurationSet string, dest EventDestination) error {
+	data := make(url.Values)
+	data.Add("Action", "UpdateConfigurationSetEventDestination")
+	data.Add("ConfigurationSetName", configurationSet)
+	dest.addTo(data, "EventDestination")
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+// DeleteConfigurationSetEventDestination removes the named event destination from a
+// configuration set.
+func (c *Config) DeleteConfigurationSetEventDestination(configurationSet, name string) error {
+	return c.DeleteConfigurationSetEventDestinationContext(context.Background(), configurationSet, name)
+}
+
+// DeleteConfigurationSetEventDestinationContext is like DeleteConfigurationSetEventDestination
+// but uses ctx for the request and any retries.
+func (c *Config) DeleteConfigurationSetEventDestinationContext(ctx context.Context, configurationSet, name string) error {
+	data := make(url.Values)
+	data.Add("Action", "DeleteConfigurationSetEventDestination")
+	data.Add("ConfigurationSetName", configurationSet)
+	data.Add("EventDestinationName", name)
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+// UpdateConfigurationSetSendingEnabled enables or disables email sending for messages sent
+// using the named configuration set.
+func (c *Config) UpdateConfigurationSetSendingEnabled(name string, enabled bool) error {
+	return c.UpdateConfigurationSetSendingEnabledContext(context.Background(), name, enabled)
+}
+
+// UpdateConfigurationSetSendingEnabledContext is like UpdateConfigurationSetSendingEnabled but
+// uses ctx for the request and any retries.
+func (c *Config) UpdateConfigurationSetSendingEnabledContext(ctx context.Context, name string, enabled bool) error {
+	data := make(url.Values)
+	data.Add("Action", "UpdateConfigurationSetSendingEnabled")
+	data.Add("ConfigurationSetName", name)
+	data.Add("Enabled", strconv.FormatBool(enabled))
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+// UpdateConfigurationSetReputationMetricsEnabled enables or disables the publishing of
+// reputation metrics for messages sent using the named configuration set.
+func (c *Config) UpdateConfigurationSetReputationMetricsEnabled(name string, enabled bool) error {
+	return c.UpdateConfigurationSetReputationMetricsEnabledContext(context.Background(), name, enabled)
+}
+
+// UpdateConfigurationSetReputationMetricsEnabledContext is like
+// UpdateConfigurationSetReputationMetricsEnabled but uses ctx for the request and any retries.
+func (c *Config) UpdateConfigurationSetReputationMetricsEnabledContext(ctx context.Context, name string, enabled bool) error {
+	data := make(url.Values)
+	data.Add("Action", "UpdateConfigurationSetReputationMetricsEnabled")
+	data.Add("ConfigurationSetName", name)
+	data.Add("Enabled", strconv.FormatBool(enabled))
+
+	return c.call(ctx, "POST", data, nil)
+}
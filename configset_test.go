@@ -0,0 +1,120 @@
+package ses
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCreateConfigurationSetEventDestination(t *testing.T) {
+	c, form := testServer(t, `<CreateConfigurationSetEventDestinationResponse/>`)
+	err := c.CreateConfigurationSetEventDestination("marketing", EventDestination{
+		Name:               "cloudwatch",
+		Enabled:            true,
+		MatchingEventTypes: []string{EventTypeBounce, EventTypeComplaint},
+		CloudWatchDestination: &CloudWatchDestination{
+			DimensionConfigurations: []CloudWatchDimensionConfiguration{
+				{DimensionName: "campaign", DimensionValueSource: "messageTag", DefaultDimensionValue: "none"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{
+		"Action":                   "CreateConfigurationSetEventDestination",
+		"ConfigurationSetName":     "marketing",
+		"EventDestination.Name":    "cloudwatch",
+		"EventDestination.Enabled": "true",
+		"EventDestination.MatchingEventTypes.member.1":                                                  "bounce",
+		"EventDestination.MatchingEventTypes.member.2":                                                  "complaint",
+		"EventDestination.CloudWatchDestination.DimensionConfigurations.member.1.DimensionName":         "campaign",
+		"EventDestination.CloudWatchDestination.DimensionConfigurations.member.1.DimensionValueSource":  "messageTag",
+		"EventDestination.CloudWatchDestination.DimensionConfigurations.member.1.DefaultDimensionValue": "none",
+	})
+}
+
+func TestDescribeConfigurationSet(t *testing.T) {
+	c, form := testServer(t, `<DescribeConfigurationSetResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <DescribeConfigurationSetResult>
+    <ConfigurationSet>
+      <Name>marketing</Name>
+    </ConfigurationSet>
+    <EventDestinations>
+      <member>
+        <Name>sns</Name>
+        <Enabled>true</Enabled>
+        <MatchingEventTypes>
+          <member>delivery</member>
+        </MatchingEventTypes>
+        <SNSDestination>
+          <TopicARN>arn:aws:sns:us-east-1:123456789012:ses-events</TopicARN>
+        </SNSDestination>
+      </member>
+    </EventDestinations>
+    <ReputationOptions>
+      <SendingEnabled>true</SendingEnabled>
+      <ReputationMetricsEnabled>true</ReputationMetricsEnabled>
+      <LastFreshStart>2024-03-01T12:00:00Z</LastFreshStart>
+    </ReputationOptions>
+  </DescribeConfigurationSetResult>
+</DescribeConfigurationSetResponse>`)
+
+	res, err := c.DescribeConfigurationSet("marketing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DescribeConfigurationSetResult{
+		ConfigurationSet: ConfigurationSet{Name: "marketing"},
+		EventDestinations: []EventDestination{{
+			Name:               "sns",
+			Enabled:            true,
+			MatchingEventTypes: []string{EventTypeDelivery},
+			SNSDestination:     &SNSDestination{TopicARN: "arn:aws:sns:us-east-1:123456789012:ses-events"},
+		}},
+		ReputationOptions: ReputationOptions{
+			SendingEnabled:           true,
+			ReputationMetricsEnabled: true,
+			LastFreshStart:           time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+	checkForm(t, *form, map[string]string{"ConfigurationSetName": "marketing", "ConfigurationSetAttributeNames.member.1": "eventDestinations", "ConfigurationSetAttributeNames.member.2": "reputationOptions"})
+}
+
+func TestListConfigurationSets(t *testing.T) {
+	c, _ := testServer(t, `<ListConfigurationSetsResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <ListConfigurationSetsResult>
+    <ConfigurationSets>
+      <member><Name>a</Name></member>
+      <member><Name>b</Name></member>
+    </ConfigurationSets>
+  </ListConfigurationSetsResult>
+</ListConfigurationSetsResponse>`)
+
+	res, err := c.ListConfigurationSets(0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []ConfigurationSet{{Name: "a"}, {Name: "b"}}; !reflect.DeepEqual(res.ConfigurationSets, want) || res.NextToken != "" {
+		t.Errorf("got %+v, want sets %+v and no NextToken", res, want)
+	}
+}
+
+func TestUpdateConfigurationSetSendingEnabled(t *testing.T) {
+	c, form := testServer(t, `<UpdateConfigurationSetSendingEnabledResponse/>`)
+	if err := c.UpdateConfigurationSetSendingEnabled("marketing", false); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"Action": "UpdateConfigurationSetSendingEnabled", "ConfigurationSetName": "marketing", "Enabled": "false"})
+}
+
+func TestUpdateConfigurationSetReputationMetricsEnabled(t *testing.T) {
+	c, form := testServer(t, `<UpdateConfigurationSetReputationMetricsEnabledResponse/>`)
+	if err := c.UpdateConfigurationSetReputationMetricsEnabled("marketing", true); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"Action": "UpdateConfigurationSetReputationMetricsEnabled", "ConfigurationSetName": "marketing", "Enabled": "true"})
+}
@@ -0,0 +1,95 @@
+package ses
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Contact subscription statuses.
+const (
+	SubscriptionStatusOptIn  = "OPT_IN"
+	SubscriptionStatusOptOut = "OPT_OUT"
+)
+
+// Contact is an address in a SESv2 contact list.
+type Contact struct {
+	EmailAddress string
+
+	// TopicPreferences are the contact's explicit subscription statuses, and
+	// TopicDefaultPreferences are the list's defaults for the other topics.
+	TopicPreferences        []TopicPreference
+	TopicDefaultPreferences []TopicPreference
+
+	// UnsubscribeAll is true if the contact has unsubscribed from all topics.
+	UnsubscribeAll bool
+
+	LastUpdatedTimestamp time.Time
+}
+
+func (ct *Contact) UnmarshalJSON(b []byte) error {
+	type plain Contact
+	v := struct {
+		*plain
+		LastUpdatedTimestamp v2Time
+	}{plain: (*plain)(ct)}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	ct.LastUpdatedTimestamp = time.Time(v.LastUpdatedTimestamp)
+	return nil
+}
+
+type TopicPreference struct {
+	TopicName string
+
+	// SubscriptionStatus is SubscriptionStatusOptIn or SubscriptionStatusOptOut.
+	SubscriptionStatus string
+}
+
+// ContactFilter selects the contacts returned by ListContacts. The zero value selects all
+// contacts.
+type ContactFilter struct {
+	// FilteredStatus, if non-empty, selects contacts with this subscription status (to
+	// TopicFilter's topic, if set).
+	FilteredStatus string       `json:",omitempty"`
+	TopicFilter    *TopicFilter `json:",omitempty"`
+}
+
+type TopicFilter struct {
+	TopicName string
+
+	// UseDefaultIfPreferenceUnavailable matches contacts without an explicit preference for
+	// the topic by the topic's default subscription status.
+	UseDefaultIfPreferenceUnavailable bool
+}
+
+type ListContactsResult struct {
+	Contacts []Contact
+
+	// NextToken is passed to ListContacts to fetch the next page. It is empty on the last page.
+	NextToken string
+}
+
+// ListContacts returns a page of the contacts in the named contact list that are selected by
+// filter. pageSize limits the page size (0 means the SES default), and nextToken is the
+// NextToken from the previous page, or empty for the first page.
+func (c *Config) ListContacts(listName string, filter ContactFilter, pageSize int, nextToken string) (ListContactsResult, error) {
+	return c.ListContactsContext(context.Background(), listName, filter, pageSize, nextToken)
+}
+
+// ListContactsContext is like ListContacts but uses ctx for the request and any retries.
+func (c *Config) ListContactsContext(ctx context.Context, listName string, filter ContactFilter, pageSize int, nextToken string) (ListContactsResult, error) {
+	in := struct {
+		Filter    *ContactFilter `json:",omitempty"`
+		PageSize  int            `json:",omitempty"`
+		NextToken string         `json:",omitempty"`
+	}{nil, pageSize, nextToken}
+	if filter != (ContactFilter{}) {
+		in.Filter = &filter
+	}
+
+	res := ListContactsResult{}
+	err := c.callV2(ctx, "ListContacts", "POST", "/contact-lists/"+awsEscape(listName)+"/contacts/list", nil, in, &res)
+	return res, err
+}
@@ -0,0 +1,34 @@
+package ses
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestListContacts(t *testing.T) {
+	c := testV2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method != "POST" || r.URL.Path != "/v2/email/contact-lists/newsletter/contacts/list" {
+			t.Errorf("got %s %s", r.Method, r.URL.Path)
+		}
+		if want := `{"Filter":{"FilteredStatus":"OPT_IN","TopicFilter":{"TopicName":"weekly","UseDefaultIfPreferenceUnavailable":true}},"PageSize":50}`; string(body) != want {
+			t.Errorf("got body %s, want %s", body, want)
+		}
+		w.Write([]byte(`{"Contacts":[{"EmailAddress":"a@example.com","TopicPreferences":[{"TopicName":"weekly","SubscriptionStatus":"OPT_IN"}],"UnsubscribeAll":false,"LastUpdatedTimestamp":1577836800}],"NextToken":"tok"}`))
+	})
+	res, err := c.ListContacts("newsletter", ContactFilter{
+		FilteredStatus: SubscriptionStatusOptIn,
+		TopicFilter:    &TopicFilter{TopicName: "weekly", UseDefaultIfPreferenceUnavailable: true},
+	}, 50, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Contacts) != 1 || res.NextToken != "tok" {
+		t.Fatalf("got %+v", res)
+	}
+	ct := res.Contacts[0]
+	if ct.EmailAddress != "a@example.com" || len(ct.TopicPreferences) != 1 || ct.TopicPreferences[0].SubscriptionStatus != SubscriptionStatusOptIn || ct.LastUpdatedTimestamp.Year() != 2020 {
+		t.Errorf("got %+v", ct)
+	}
+}
@@ -0,0 +1,89 @@
+package ses
+
+import "time"
+
+// Pricing holds the SES prices used to estimate costs, in US dollars. Prices differ by region
+// and change over time, so callers should fill in the prices published for their account.
+type Pricing struct {
+	// PerThousandMessages is the price of 1000 outbound messages.
+	PerThousandMessages float64
+
+	// PerGB is the price of each GB (2^30 bytes) of outbound data, such as attachments.
+	PerGB float64
+
+	// PerDedicatedIPMonth is the monthly price of a dedicated IP address.
+	PerDedicatedIPMonth float64
+
+	// FreeMessagesPerMonth is the number of messages per month that are not charged.
+	FreeMessagesPerMonth int64
+}
+
+// DefaultPricing is the published standard SES pricing when it was written: $0.10 per 1000
+// messages, $0.12 per GB of attachments and $24.95 per dedicated IP per month, with no free
+// tier.
+var DefaultPricing = Pricing{
+	PerThousandMessages: 0.10,
+	PerGB:               0.12,
+	PerDedicatedIPMonth: 24.95,
+}
+
+// Usage is the sending volume over a period, to estimate the cost of.
+type Usage struct {
+	Messages int64
+	Bytes    int64
+
+	// DedicatedIPs is the number of dedicated IP addresses leased for the period, and Months
+	// is the length of the period (which also scales FreeMessagesPerMonth).
+	DedicatedIPs int
+	Months       float64
+}
+
+// UsageFromStatistics returns the usage recorded by GetSendStatistics data points (the
+// delivery attempts of up to the last two weeks). The period runs from the start of the
+// earliest data point to the end of the latest, each covering 15 minutes, so an account with
+// less history isn't assumed to have sent over two weeks. Bytes is not recorded by SES and is
+// left 0.
+func UsageFromStatistics(points []SendDataPoint) Usage {
+	var u Usage
+	var first, last time.Time
+	for i, p := range points {
+		u.Messages += int64(p.DeliveryAttempts)
+		if i == 0 || p.Timestamp.Before(first) {
+			first = p.Timestamp
+		}
+		if i == 0 || p.Timestamp.After(last) {
+			last = p.Timestamp
+		}
+	}
+	if len(points) > 0 {
+		span := last.Add(statisticsInterval).Sub(first)
+		u.Months = span.Hours() / (30 * 24)
+	}
+	return u
+}
+
+// statisticsInterval is the period covered by each GetSendStatistics data point.
+const statisticsInterval = 15 * time.Minute
+
+// CostEstimate is the estimated cost of a Usage, in US dollars.
+type CostEstimate struct {
+	Messages     float64
+	Data         float64
+	DedicatedIPs float64
+	Total        float64
+}
+
+// Estimate returns the estimated cost of u at the prices p.
+func (p Pricing) Estimate(u Usage) CostEstimate {
+	charged := float64(u.Messages) - float64(p.FreeMessagesPerMonth)*u.Months
+	if charged < 0 {
+		charged = 0
+	}
+	e := CostEstimate{
+		Messages:     charged / 1000 * p.PerThousandMessages,
+		Data:         float64(u.Bytes) / (1 << 30) * p.PerGB,
+		DedicatedIPs: float64(u.DedicatedIPs) * u.Months * p.PerDedicatedIPMonth,
+	}
+	e.Total = e.Messages + e.Data + e.DedicatedIPs
+	return e
+}
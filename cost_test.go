@@ -0,0 +1,40 @@
+package ses
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEstimate(t *testing.T) {
+	e := DefaultPricing.Estimate(Usage{Messages: 1000000, Bytes: 10 << 30, DedicatedIPs: 2, Months: 1})
+	want := CostEstimate{Messages: 100, Data: 1.2, DedicatedIPs: 49.9, Total: 151.1}
+	for _, v := range [][2]float64{{e.Messages, want.Messages}, {e.Data, want.Data}, {e.DedicatedIPs, want.DedicatedIPs}, {e.Total, want.Total}} {
+		if math.Abs(v[0]-v[1]) > 1e-9 {
+			t.Errorf("got %+v, want %+v", e, want)
+			break
+		}
+	}
+
+	free := Pricing{PerThousandMessages: 0.10, FreeMessagesPerMonth: 62000}
+	if e := free.Estimate(Usage{Messages: 50000, Months: 1}); e.Total != 0 {
+		t.Errorf("within the free tier: got %+v", e)
+	}
+	if e := free.Estimate(Usage{Messages: 72000, Months: 1}); math.Abs(e.Total-1) > 1e-9 {
+		t.Errorf("got total %g, want 1", e.Total)
+	}
+}
+
+func TestUsageFromStatistics(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	u := UsageFromStatistics([]SendDataPoint{
+		{DeliveryAttempts: 10, Timestamp: start.Add(3*24*time.Hour - 15*time.Minute)},
+		{DeliveryAttempts: 5, Timestamp: start},
+	})
+	if u.Messages != 15 || u.Months != 0.1 {
+		t.Errorf("got %+v, want 15 messages over 3 days (0.1 months)", u)
+	}
+	if u := UsageFromStatistics(nil); u.Months != 0 {
+		t.Errorf("no data points: got %+v", u)
+	}
+}
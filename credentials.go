@@ -0,0 +1,439 @@
+package ses
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credentials are the AWS credentials used to sign requests to Amazon SES.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// SecurityToken is the session token for temporary credentials.
+	SecurityToken string
+
+	// Expires is when temporary credentials expire. It is zero for credentials that don't
+	// expire.
+	Expires time.Time
+}
+
+// A CredentialsProvider retrieves credentials for signing requests. Implementations must be safe
+// for concurrent use.
+type CredentialsProvider interface {
+	Retrieve(ctx context.Context) (Credentials, error)
+}
+
+// StaticProvider provides a fixed set of credentials.
+type StaticProvider Credentials
+
+func (p StaticProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	if p.AccessKeyID == "" || p.SecretAccessKey == "" {
+		return Credentials{}, errors.New("ses: static credentials are empty")
+	}
+	return Credentials(p), nil
+}
+
+// EnvProvider provides credentials from the environment variables $AWS_ACCESS_KEY_ID,
+// $AWS_SECRET_ACCESS_KEY (or $AWS_SECRET_KEY) and, optionally, $AWS_SESSION_TOKEN (or
+// $AWS_SECURITY_TOKEN).
+type EnvProvider struct{}
+
+func (EnvProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	creds := Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: firstEnv("AWS_SECRET_ACCESS_KEY", "AWS_SECRET_KEY"),
+		SecurityToken:   firstEnv("AWS_SESSION_TOKEN", "AWS_SECURITY_TOKEN"),
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return Credentials{}, errors.New("ses: AWS_ACCESS_KEY_ID or AWS_SECRET_ACCESS_KEY not set in environment")
+	}
+	return creds, nil
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// SharedCredentialsProvider provides credentials from a profile in an AWS shared credentials
+// file.
+type SharedCredentialsProvider struct {
+	// Filename is the path of the credentials file. If empty, $AWS_SHARED_CREDENTIALS_FILE or
+	// else ~/.aws/credentials is used.
+	Filename string
+
+	// Profile is the name of the profile to use. If empty, $AWS_PROFILE or else "default" is
+	// used.
+	Profile string
+}
+
+func (p SharedCredentialsProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	filename := p.Filename
+	if filename == "" {
+		filename = os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	}
+	if filename == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return Credentials{}, err
+		}
+		filename = filepath.Join(home, ".aws", "credentials")
+	}
+	profile := p.Profile
+	if profile == "" {
+		profile = firstEnv("AWS_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+
+	profiles, err := readSharedCredentials(filename)
+	if err != nil {
+		return Credentials{}, err
+	}
+	creds := profiles[profile]
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return Credentials{}, fmt.Errorf("ses: no credentials for profile %q in %s", profile, filename)
+	}
+	return creds, nil
+}
+
+// sharedCredentialsFile is a parsed shared credentials file.
+type sharedCredentialsFile struct {
+	modTime  time.Time
+	size     int64
+	profiles map[string]Credentials
+}
+
+// sharedCredentialsFiles caches the parsed shared credentials files by filename, so that they
+// are only parsed again when they change.
+var sharedCredentialsFiles = struct {
+	sync.Mutex
+	m map[string]*sharedCredentialsFile
+}{m: make(map[string]*sharedCredentialsFile)}
+
+// readSharedCredentials returns the credentials of each profile in the shared credentials file
+// filename.
+func readSharedCredentials(filename string) (map[string]Credentials, error) {
+	fi, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+	sharedCredentialsFiles.Lock()
+	defer sharedCredentialsFiles.Unlock()
+	if f := sharedCredentialsFiles.m[filename]; f != nil && f.modTime.Equal(fi.ModTime()) && f.size == fi.Size() {
+		return f.profiles, nil
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	profiles := make(map[string]Credentials)
+	var section string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' && line[len(line)-1] == ']' {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		i := strings.Index(line, "=")
+		if i < 0 {
+			continue
+		}
+		creds := profiles[section]
+		key, value := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		switch key {
+		case "aws_access_key_id":
+			creds.AccessKeyID = value
+		case "aws_secret_access_key":
+			creds.SecretAccessKey = value
+		case "aws_session_token", "aws_security_token":
+			creds.SecurityToken = value
+		}
+		profiles[section] = creds
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	sharedCredentialsFiles.m[filename] = &sharedCredentialsFile{modTime: fi.ModTime(), size: fi.Size(), profiles: profiles}
+	return profiles, nil
+}
+
+// expiryWindow is how long before their expiration temporary credentials are refreshed.
+const expiryWindow = 5 * time.Minute
+
+// credentialsCache holds temporary credentials until they are about to expire.
+type credentialsCache struct {
+	mu    sync.Mutex
+	creds Credentials
+}
+
+func (c *credentialsCache) get(ctx context.Context, fetch func(context.Context) (Credentials, error)) (Credentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.creds.AccessKeyID != "" && (c.creds.Expires.IsZero() || time.Until(c.creds.Expires) > expiryWindow) {
+		return c.creds, nil
+	}
+	creds, err := fetch(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+	c.creds = creds
+	return creds, nil
+}
+
+// metadataCredentials is the JSON credentials document served by the EC2 and ECS metadata
+// services.
+type metadataCredentials struct {
+	Code            string
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string
+	Token           string
+	Expiration      time.Time
+}
+
+func (m metadataCredentials) credentials() (Credentials, error) {
+	if m.Code != "" && m.Code != "Success" {
+		return Credentials{}, fmt.Errorf("ses: metadata service returned code %q", m.Code)
+	}
+	if m.AccessKeyID == "" || m.SecretAccessKey == "" {
+		return Credentials{}, errors.New("ses: metadata service returned empty credentials")
+	}
+	return Credentials{
+		AccessKeyID:     m.AccessKeyID,
+		SecretAccessKey: m.SecretAccessKey,
+		SecurityToken:   m.Token,
+		Expires:         m.Expiration,
+	}, nil
+}
+
+// metadataClient is used for requests to the EC2 and ECS metadata services, which respond
+// quickly or not at all.
+var metadataClient = &http.Client{Timeout: 5 * time.Second}
+
+func metadataGet(ctx context.Context, url string, header http.Header) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := metadataClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ses: metadata request %s: status %d", url, resp.StatusCode)
+	}
+	return body, nil
+}
+
+// ECSProvider provides the credentials of the ECS task role (or any other container
+// credentials endpoint) named by $AWS_CONTAINER_CREDENTIALS_RELATIVE_URI or
+// $AWS_CONTAINER_CREDENTIALS_FULL_URI. Credentials are refreshed shortly before they expire.
+type ECSProvider struct {
+	cache credentialsCache
+}
+
+func (p *ECSProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	return p.cache.get(ctx, p.fetch)
+}
+
+func (p *ECSProvider) fetch(ctx context.Context) (Credentials, error) {
+	url := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI")
+	if rel := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); rel != "" {
+		url = "http://169.254.170.2" + rel
+	}
+	if url == "" {
+		return Credentials{}, errors.New("ses: not running in a container with a credentials endpoint")
+	}
+	header := http.Header{}
+	if token := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN"); token != "" {
+		header.Set("Authorization", token)
+	}
+
+	body, err := metadataGet(ctx, url, header)
+	if err != nil {
+		return Credentials{}, err
+	}
+	var m metadataCredentials
+	if err := json.Unmarshal(body, &m); err != nil {
+		return Credentials{}, err
+	}
+	return m.credentials()
+}
+
+// EC2RoleProvider provides the credentials of the IAM role attached to the EC2 instance, using
+// the instance metadata service (IMDSv2, falling back to IMDSv1). Credentials are refreshed
+// shortly before they expire.
+type EC2RoleProvider struct {
+	// Endpoint is the base URL of the instance metadata service. If empty,
+	// "http://169.254.169.254" is used.
+	Endpoint string
+
+	cache credentialsCache
+}
+
+func (p *EC2RoleProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	return p.cache.get(ctx, p.fetch)
+}
+
+func (p *EC2RoleProvider) fetch(ctx context.Context) (Credentials, error) {
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = "http://169.254.169.254"
+	}
+
+	header := http.Header{}
+	if token, err := p.token(ctx, endpoint); err == nil {
+		header.Set("X-Aws-Ec2-Metadata-Token", token)
+	}
+
+	const path = "/latest/meta-data/iam/security-credentials/"
+	roles, err := metadataGet(ctx, endpoint+path, header)
+	if err != nil {
+		return Credentials{}, err
+	}
+	role := strings.TrimSpace(strings.SplitN(string(roles), "\n", 2)[0])
+	if role == "" {
+		return Credentials{}, errors.New("ses: no IAM role attached to EC2 instance")
+	}
+
+	body, err := metadataGet(ctx, endpoint+path+role, header)
+	if err != nil {
+		return Credentials{}, err
+	}
+	var m metadataCredentials
+	if err := json.Unmarshal(body, &m); err != nil {
+		return Credentials{}, err
+	}
+	return m.credentials()
+}
+
+// token requests an IMDSv2 session token.
+func (p *EC2RoleProvider) token(ctx context.Context, endpoint string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "PUT", endpoint+"/latest/api/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Aws-Ec2-Metadata-Token-Ttl-Seconds", "21600")
+	resp, err := metadataClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ses: IMDSv2 token request: status %d", resp.StatusCode)
+	}
+	return string(body), nil
+}
+
+// ChainProvider provides credentials from the first of its providers that succeeds.
+type ChainProvider []CredentialsProvider
+
+func (p ChainProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	var errs []string
+	for _, provider := range p {
+		creds, err := provider.Retrieve(ctx)
+		if err == nil {
+			return creds, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	return Credentials{}, fmt.Errorf("ses: no credentials found: %s", strings.Join(errs, "; "))
+}
+
+// CachedChainProvider is like ChainProvider, but remembers which of its providers succeeded and
+// retrieves credentials from it alone until it fails. When all of the providers fail, the error
+// is returned without trying them again for a backoff period, which starts at a second and
+// doubles with each failure up to 5 minutes, so that an unavailable metadata service doesn't
+// delay every request.
+type CachedChainProvider struct {
+	Providers ChainProvider
+
+	mu       sync.Mutex
+	provider CredentialsProvider
+	err      error
+	retry    time.Time
+	backoff  time.Duration
+}
+
+func (p *CachedChainProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	p.mu.Lock()
+	provider := p.provider
+	p.mu.Unlock()
+	if provider != nil {
+		if creds, err := provider.Retrieve(ctx); err == nil {
+			return creds, nil
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.provider != nil && p.provider != provider {
+		// Another call resolved the chain while this one was retrieving.
+		return p.provider.Retrieve(ctx)
+	}
+	if p.err != nil && time.Now().Before(p.retry) {
+		return Credentials{}, p.err
+	}
+	var errs []string
+	for _, provider := range p.Providers {
+		creds, err := provider.Retrieve(ctx)
+		if err == nil {
+			p.provider, p.err, p.backoff = provider, nil, 0
+			return creds, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	if p.backoff *= 2; p.backoff == 0 {
+		p.backoff = time.Second
+	} else if p.backoff > 5*time.Minute {
+		p.backoff = 5 * time.Minute
+	}
+	p.provider = nil
+	p.err = fmt.Errorf("ses: no credentials found: %s", strings.Join(errs, "; "))
+	p.retry = time.Now().Add(p.backoff)
+	return Credentials{}, p.err
+}
+
+// DefaultCredentials looks for credentials in the environment, the shared credentials file, the
+// ECS container credentials endpoint and the EC2 instance metadata service, in that order. It is
+// used by a Config with neither Credentials nor AccessKeyID set.
+var DefaultCredentials CredentialsProvider = &CachedChainProvider{Providers: ChainProvider{
+	EnvProvider{},
+	SharedCredentialsProvider{},
+	&ECSProvider{},
+	&EC2RoleProvider{},
+}}
@@ -0,0 +1,235 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_SECRET_KEY", "SECRET")
+	t.Setenv("AWS_SESSION_TOKEN", "TOKEN")
+
+	creds, err := EnvProvider{}.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET", SecurityToken: "TOKEN"}); creds != want {
+		t.Errorf("got %+v, want %+v", creds, want)
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	if _, err := (EnvProvider{}).Retrieve(context.Background()); err == nil {
+		t.Error("got nil error with empty environment")
+	}
+}
+
+func TestSharedCredentialsProvider(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "credentials")
+	err := ioutil.WriteFile(filename, []byte(`
+# comment
+[default]
+aws_access_key_id = AKID1
+aws_secret_access_key = SECRET1
+
+[other]
+aws_access_key_id=AKID2
+aws_secret_access_key=SECRET2
+aws_session_token=TOKEN2
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_PROFILE", "")
+
+	tests := map[string]Credentials{
+		"":        {AccessKeyID: "AKID1", SecretAccessKey: "SECRET1"},
+		"default": {AccessKeyID: "AKID1", SecretAccessKey: "SECRET1"},
+		"other":   {AccessKeyID: "AKID2", SecretAccessKey: "SECRET2", SecurityToken: "TOKEN2"},
+	}
+	for profile, want := range tests {
+		creds, err := SharedCredentialsProvider{Filename: filename, Profile: profile}.Retrieve(context.Background())
+		if err != nil {
+			t.Errorf("profile %q: %s", profile, err)
+			continue
+		}
+		if creds != want {
+			t.Errorf("profile %q: got %+v, want %+v", profile, creds, want)
+		}
+	}
+
+	if _, err := (SharedCredentialsProvider{Filename: filename, Profile: "missing"}).Retrieve(context.Background()); err == nil {
+		t.Error("got nil error for missing profile")
+	}
+
+	// A changed file is parsed again.
+	if err := ioutil.WriteFile(filename, []byte("[default]\naws_access_key_id = AKID3\naws_secret_access_key = SECRET3\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(filename, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+	creds, err := SharedCredentialsProvider{Filename: filename}.Retrieve(context.Background())
+	if err != nil || creds.AccessKeyID != "AKID3" {
+		t.Errorf("after change: got %+v, %v", creds, err)
+	}
+}
+
+func TestEC2RoleProvider(t *testing.T) {
+	var fetches int
+	expires := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest/api/token":
+			w.Write([]byte("imds-token"))
+			return
+		}
+		if r.Header.Get("X-Aws-Ec2-Metadata-Token") != "imds-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/latest/meta-data/iam/security-credentials/":
+			w.Write([]byte("my-role\n"))
+		case "/latest/meta-data/iam/security-credentials/my-role":
+			fetches++
+			fmt.Fprintf(w, `{"Code":"Success","AccessKeyId":"AKID","SecretAccessKey":"SECRET","Token":"TOKEN","Expiration":%q}`, expires.Format(time.RFC3339))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	p := &EC2RoleProvider{Endpoint: srv.URL}
+	for i := 0; i < 2; i++ {
+		creds, err := p.Retrieve(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := (Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET", SecurityToken: "TOKEN", Expires: expires}); creds != want {
+			t.Errorf("got %+v, want %+v", creds, want)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("got %d fetches, want 1 (credentials should be cached)", fetches)
+	}
+
+	// Credentials that are about to expire are refreshed.
+	expires = time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+	p = &EC2RoleProvider{Endpoint: srv.URL}
+	fetches = 0
+	for i := 0; i < 2; i++ {
+		if _, err := p.Retrieve(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fetches != 2 {
+		t.Errorf("got %d fetches, want 2 (expiring credentials should be refreshed)", fetches)
+	}
+}
+
+func TestECSProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "auth" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"AccessKeyId":"AKID","SecretAccessKey":"SECRET","Token":"TOKEN","Expiration":"2100-01-01T00:00:00Z"}`))
+	}))
+	defer srv.Close()
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", srv.URL+"/creds")
+	t.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN", "auth")
+
+	creds, err := (&ECSProvider{}).Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.AccessKeyID != "AKID" || creds.SecurityToken != "TOKEN" || creds.Expires.Year() != 2100 {
+		t.Errorf("got %+v", creds)
+	}
+}
+
+func TestChainProvider(t *testing.T) {
+	p := ChainProvider{StaticProvider{}, StaticProvider{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}}
+	creds, err := p.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.AccessKeyID != "AKID" {
+		t.Errorf("got %+v, want credentials from second provider", creds)
+	}
+
+	if _, err := (ChainProvider{StaticProvider{}}).Retrieve(context.Background()); err == nil {
+		t.Error("got nil error when no provider succeeds")
+	}
+}
+
+// countingProvider counts its calls, and fails while err is set.
+type countingProvider struct {
+	calls int
+	err   error
+}
+
+func (p *countingProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	p.calls++
+	if p.err != nil {
+		return Credentials{}, p.err
+	}
+	return Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}, nil
+}
+
+func TestCachedChainProvider(t *testing.T) {
+	first := &countingProvider{err: errors.New("no credentials")}
+	second := &countingProvider{}
+	p := &CachedChainProvider{Providers: ChainProvider{first, second}}
+	for i := 0; i < 3; i++ {
+		if _, err := p.Retrieve(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if first.calls != 1 || second.calls != 3 {
+		t.Errorf("got %d and %d calls, want the failing provider tried once", first.calls, second.calls)
+	}
+
+	// When every provider fails, they aren't tried again until the backoff has passed.
+	second.err = errors.New("expired")
+	for i := 0; i < 3; i++ {
+		if _, err := p.Retrieve(context.Background()); err == nil {
+			t.Fatal("got nil error when no provider succeeds")
+		}
+	}
+	if first.calls != 2 || second.calls != 5 {
+		t.Errorf("got %d and %d calls, want each provider tried once more", first.calls, second.calls)
+	}
+	second.err = nil
+	p.retry = time.Now()
+	if _, err := p.Retrieve(context.Background()); err != nil {
+		t.Errorf("after the backoff: %v", err)
+	}
+}
+
+func TestConfigCredentials(t *testing.T) {
+	var accessKeyID string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		accessKeyID = r.Form.Get("AWSAccessKeyId")
+		w.Write([]byte("<SendEmailResponse/>"))
+	}))
+	defer srv.Close()
+
+	c := Config{Endpoint: srv.URL, Credentials: StaticProvider{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}}
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if accessKeyID != "AKID" {
+		t.Errorf("got AWSAccessKeyId %q, want %q", accessKeyID, "AKID")
+	}
+}
@@ -0,0 +1,154 @@
+package ses
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// CustomVerificationEmailTemplate is a template for the verification email sent by
+// SendCustomVerificationEmail, used in place of the default SES verification email.
+type CustomVerificationEmailTemplate struct {
+	TemplateName     string
+	FromEmailAddress string
+	TemplateSubject  string
+
+	// TemplateContent is the HTML body of the email. It is not returned by
+	// ListCustomVerificationEmailTemplates.
+	TemplateContent string
+
+	// SuccessRedirectionURL and FailureRedirectionURL are where the recipient is sent after
+	// following the verification link.
+	SuccessRedirectionURL string
+	FailureRedirectionURL string
+}
+
+// addTo adds the non-empty fields of t to data.
+func (t CustomVerificationEmailTemplate) addTo(data url.Values) {
+	for k, v := range map[string]string{
+		"TemplateName":          t.TemplateName,
+		"FromEmailAddress":      t.FromEmailAddress,
+		"TemplateSubject":       t.TemplateSubject,
+		"TemplateContent":       t.TemplateContent,
+		"SuccessRedirectionURL": t.SuccessRedirectionURL,
+		"FailureRedirectionURL": t.FailureRedirectionURL,
+	} {
+		if v != "" {
+			data.Add(k, v)
+		}
+	}
+}
+
+// CreateCustomVerificationEmailTemplate creates a custom verification email template. All
+// fields of t are required.
+func (c *Config) CreateCustomVerificationEmailTemplate(t CustomVerificationEmailTemplate) error {
+	return c.CreateCustomVerificationEmailTemplateContext(context.Background(), t)
+}
+
+// CreateCustomVerificationEmailTemplateContext is like CreateCustomVerificationEmailTemplate
+// but uses ctx for the request and any retries.
+func (c *Config) CreateCustomVerificationEmailTemplateContext(ctx context.Context, t CustomVerificationEmailTemplate) error {
+	data := make(url.Values)
+	data.Add("Action", "CreateCustomVerificationEmailTemplate")
+	t.addTo(data)
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+// UpdateCustomVerificationEmailTemplate updates the custom verification email template named
+// t.TemplateName. Empty fields of t are left unchanged.
+func (c *Config) UpdateCustomVerificationEmailTemplate(t CustomVerificationEmailTemplate) error {
+	return c.UpdateCustomVerificationEmailTemplateContext(context.Background(), t)
+}
+
+// UpdateCustomVerificationEmailTemplateContext is like UpdateCustomVerificationEmailTemplate
+// but uses ctx for the request and any retries.
+func (c *Config) UpdateCustomVerificationEmailTemplateContext(ctx context.Context, t CustomVerificationEmailTemplate) error {
+	data := make(url.Values)
+	data.Add("Action", "UpdateCustomVerificationEmailTemplate")
+	t.addTo(data)
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+// DeleteCustomVerificationEmailTemplate deletes the named custom verification email template.
+func (c *Config) DeleteCustomVerificationEmailTemplate(name string) error {
+	return c.DeleteCustomVerificationEmailTemplateContext(context.Background(), name)
+}
+
+// DeleteCustomVerificationEmailTemplateContext is like DeleteCustomVerificationEmailTemplate
+// but uses ctx for the request and any retries.
+func (c *Config) DeleteCustomVerificationEmailTemplateContext(ctx context.Context, name string) error {
+	data := make(url.Values)
+	data.Add("Action", "DeleteCustomVerificationEmailTemplate")
+	data.Add("TemplateName", name)
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+type ListCustomVerificationEmailTemplatesResult struct {
+	CustomVerificationEmailTemplates []CustomVerificationEmailTemplate `xml:"CustomVerificationEmailTemplates>member"`
+
+	// NextToken is passed to ListCustomVerificationEmailTemplates to fetch the next page. It is
+	// empty on the last page.
+	NextToken string
+}
+
+type ListCustomVerificationEmailTemplatesResponse struct {
+	ListCustomVerificationEmailTemplatesResult ListCustomVerificationEmailTemplatesResult
+}
+
+// ListCustomVerificationEmailTemplates returns a page of the custom verification email templates
+// for the account. maxResults limits the page size (0 means the SES default), and nextToken is
+// the NextToken from the previous page, or empty for the first page.
+func (c *Config) ListCustomVerificationEmailTemplates(maxResults int, nextToken string) (ListCustomVerificationEmailTemplatesResult, error) {
+	return c.ListCustomVerificationEmailTemplatesContext(context.Background(), maxResults, nextToken)
+}
+
+// ListCustomVerificationEmailTemplatesContext is like ListCustomVerificationEmailTemplates but
+// uses ctx for the request and any retries.
+func (c *Config) ListCustomVerificationEmailTemplatesContext(ctx context.Context, maxResults int, nextToken string) (ListCustomVerificationEmailTemplatesResult, error) {
+	data := make(url.Values)
+	data.Add("Action", "ListCustomVerificationEmailTemplates")
+	if maxResults > 0 {
+		data.Add("MaxResults", strconv.Itoa(maxResults))
+	}
+	if nextToken != "" {
+		data.Add("NextToken", nextToken)
+	}
+
+	res := ListCustomVerificationEmailTemplatesResponse{}
+	err := c.call(ctx, "GET", data, &res)
+	return res.ListCustomVerificationEmailTemplatesResult, err
+}
+
+type SendCustomVerificationEmailResult struct {
+	MessageID string `xml:"MessageId"`
+}
+
+type SendCustomVerificationEmailResponse struct {
+	SendCustomVerificationEmailResult SendCustomVerificationEmailResult
+}
+
+// SendCustomVerificationEmail adds email to the account's identities and sends it a
+// verification email using the named custom verification email template. configurationSet, if
+// non-empty, is the configuration set to send the email with. It returns the SES message ID.
+func (c *Config) SendCustomVerificationEmail(email, templateName, configurationSet string) (string, error) {
+	return c.SendCustomVerificationEmailContext(context.Background(), email, templateName, configurationSet)
+}
+
+// SendCustomVerificationEmailContext is like SendCustomVerificationEmail but uses ctx for the
+// request and any retries.
+func (c *Config) SendCustomVerificationEmailContext(ctx context.Context, email, templateName, configurationSet string) (string, error) {
+	data := make(url.Values)
+	data.Add("Action", "SendCustomVerificationEmail")
+	data.Add("EmailAddress", email)
+	data.Add("TemplateName", templateName)
+	if configurationSet != "" {
+		data.Add("ConfigurationSetName", configurationSet)
+	}
+
+	res := SendCustomVerificationEmailResponse{}
+	err := c.call(ctx, "POST", data, &res)
+	return res.SendCustomVerificationEmailResult.MessageID, err
+}
@@ -0,0 +1,70 @@
+package ses
+
+import "testing"
+
+func TestUpdateCustomVerificationEmailTemplate(t *testing.T) {
+	c, form := testServer(t, `<UpdateCustomVerificationEmailTemplateResponse/>`)
+	err := c.UpdateCustomVerificationEmailTemplate(CustomVerificationEmailTemplate{
+		TemplateName:    "tenant-a",
+		TemplateSubject: "Confirm your address",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{
+		"Action":           "UpdateCustomVerificationEmailTemplate",
+		"TemplateName":     "tenant-a",
+		"TemplateSubject":  "Confirm your address",
+		"TemplateContent":  "",
+		"FromEmailAddress": "",
+	})
+}
+
+func TestListCustomVerificationEmailTemplates(t *testing.T) {
+	c, form := testServer(t, `<ListCustomVerificationEmailTemplatesResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <ListCustomVerificationEmailTemplatesResult>
+    <CustomVerificationEmailTemplates>
+      <member>
+        <TemplateName>tenant-a</TemplateName>
+        <FromEmailAddress>noreply@tenant-a.example.com</FromEmailAddress>
+        <TemplateSubject>Confirm your address</TemplateSubject>
+        <SuccessRedirectionURL>https://tenant-a.example.com/ok</SuccessRedirectionURL>
+        <FailureRedirectionURL>https://tenant-a.example.com/fail</FailureRedirectionURL>
+      </member>
+    </CustomVerificationEmailTemplates>
+    <NextToken>abc</NextToken>
+  </ListCustomVerificationEmailTemplatesResult>
+</ListCustomVerificationEmailTemplatesResponse>`)
+	res, err := c.ListCustomVerificationEmailTemplates(10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"Action": "ListCustomVerificationEmailTemplates", "MaxResults": "10"})
+	if len(res.CustomVerificationEmailTemplates) != 1 || res.NextToken != "abc" {
+		t.Fatalf("got %+v", res)
+	}
+	if got := res.CustomVerificationEmailTemplates[0]; got.TemplateName != "tenant-a" || got.SuccessRedirectionURL != "https://tenant-a.example.com/ok" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestSendCustomVerificationEmail(t *testing.T) {
+	c, form := testServer(t, `<SendCustomVerificationEmailResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <SendCustomVerificationEmailResult>
+    <MessageId>0100-abc</MessageId>
+  </SendCustomVerificationEmailResult>
+</SendCustomVerificationEmailResponse>`)
+	id, err := c.SendCustomVerificationEmail("user@example.com", "tenant-a", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "0100-abc" {
+		t.Errorf("got message ID %q", id)
+	}
+	checkForm(t, *form, map[string]string{
+		"Action":               "SendCustomVerificationEmail",
+		"EmailAddress":         "user@example.com",
+		"TemplateName":         "tenant-a",
+		"ConfigurationSetName": "",
+	})
+}
@@ -0,0 +1,72 @@
+package ses
+
+import (
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrDuplicate is returned by send calls that a DuplicateGuard skipped because an identical
+// message was sent recently.
+var ErrDuplicate = errors.New("ses: duplicate message skipped")
+
+// DuplicateGuard skips send calls that repeat an identical message (the same sender,
+// recipients, content, configuration set and tags) within a time window, protecting recipients
+// from upstream bugs that trigger the same notification over and over. It is safe for
+// concurrent use, and may be shared by several Configs.
+//
+// The envelope sender chosen by a ReturnPathPool is not part of the comparison, so repeats are
+// caught even if they are sent from different return paths. Messages streamed with
+// SendRawEmailReader are not checked, since that would mean holding them in memory.
+type DuplicateGuard struct {
+	window time.Duration
+
+	mu        sync.Mutex
+	sent      map[[sha256.Size]byte]time.Time
+	lastPrune time.Time
+}
+
+// NewDuplicateGuard returns a DuplicateGuard that skips messages identical to one sent in the
+// last window.
+func NewDuplicateGuard(window time.Duration) *DuplicateGuard {
+	return &DuplicateGuard{window: window, sent: make(map[[sha256.Size]byte]time.Time)}
+}
+
+// SetWindow changes the window in which identical messages are skipped. A window of 0 disables
+// the guard.
+func (g *DuplicateGuard) SetWindow(window time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.window = window
+}
+
+// reserve records the send request described by msg, and reports false if an identical
+// request was recorded in the window. The returned function forgets the request again, and is
+// called when the send fails so that it may be retried.
+func (g *DuplicateGuard) reserve(msg []byte) (ok bool, forget func()) {
+	key := sha256.Sum256(msg)
+	now := time.Now()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if now.Sub(g.lastPrune) > g.window {
+		for k, t := range g.sent {
+			if now.Sub(t) >= g.window {
+				delete(g.sent, k)
+			}
+		}
+		g.lastPrune = now
+	}
+	if t, seen := g.sent[key]; seen && now.Sub(t) < g.window {
+		return false, nil
+	}
+	g.sent[key] = now
+	return true, func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.sent[key] == now {
+			delete(g.sent, key)
+		}
+	}
+}
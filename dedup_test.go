@@ -0,0 +1,94 @@
+package ses
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDuplicateGuard(t *testing.T) {
+	fail := false
+	sends := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		sends++
+		w.Write([]byte(`<SendEmailResponse/>`))
+	}))
+	defer srv.Close()
+	c := &Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET", DuplicateGuard: NewDuplicateGuard(50 * time.Millisecond)}
+
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "Your code", "123456"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "Your code", "123456"); err != ErrDuplicate {
+		t.Fatalf("got %v, want ErrDuplicate", err)
+	}
+	if _, err := c.SendEmail("a@example.com", "c@example.com", "Your code", "123456"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "Your code", "123456", WithTag("attempt", "2")); err != nil {
+		t.Fatal(err)
+	}
+	if sends != 3 {
+		t.Errorf("got %d sends, want 3", sends)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "Your code", "123456"); err != nil {
+		t.Errorf("after the window: %v", err)
+	}
+
+	// A failed send is not recorded, so it can be retried.
+	fail = true
+	if _, err := c.SendEmail("a@example.com", "d@example.com", "s", "b"); err == nil {
+		t.Fatal("want error")
+	}
+	fail = false
+	if _, err := c.SendEmail("a@example.com", "d@example.com", "s", "b"); err != nil {
+		t.Errorf("retry after failure: %v", err)
+	}
+}
+
+func TestDuplicateGuardReturnPathPool(t *testing.T) {
+	c, _ := testServer(t, `<SendRawEmailResponse/>`)
+	c.DuplicateGuard = NewDuplicateGuard(time.Minute)
+	c.ReturnPathPool = &ReturnPathPool{Senders: map[string][]string{"": {"bounces@a.example.com", "bounces@b.example.com"}}}
+	raw := []byte("From: a@example.com\r\nTo: b@example.com\r\nSubject: s\r\n\r\nb")
+	if _, err := c.SendRawEmail(raw); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SendRawEmail(raw); err != ErrDuplicate {
+		t.Errorf("got %v from a repeat with the next return path, want ErrDuplicate", err)
+	}
+}
+
+func TestDuplicateGuardV2(t *testing.T) {
+	sends := 0
+	c := testV2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		sends++
+		w.Write([]byte(`{"MessageId":"0100-abc"}`))
+	})
+	c.DuplicateGuard = NewDuplicateGuard(time.Minute)
+	in := SendEmailInput{
+		FromEmailAddress: "a@example.com",
+		Destination:      &Destination{ToAddresses: []string{"b@example.com"}},
+		Content:          EmailContent{Simple: &SimpleEmail{Subject: "Your code", Text: "123456"}},
+	}
+	if _, err := c.SendEmailV2(context.Background(), in); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SendEmailV2(context.Background(), in); err != ErrDuplicate {
+		t.Errorf("got %v, want ErrDuplicate", err)
+	}
+	if _, err := c.SendEmailV2(context.Background(), in, WithTag("attempt", "2")); err != nil {
+		t.Fatal(err)
+	}
+	if sends != 2 {
+		t.Errorf("got %d sends, want 2", sends)
+	}
+}
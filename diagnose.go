@@ -0,0 +1,111 @@
+package ses
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+)
+
+// ConnectionReport describes the connection used for a request to SES, for debugging network
+// and proxy problems.
+type ConnectionReport struct {
+	Endpoint string
+
+	// Proxy is the URL of the HTTP proxy used, from $HTTPS_PROXY and the like, if any.
+	Proxy string
+
+	// LocalAddr is the local address of the connection. Behind NAT, SES sees a different
+	// (public) address.
+	LocalAddr  string
+	RemoteAddr string
+
+	// TLSVersion (such as "TLS 1.3"), CipherSuite and ServerName describe the TLS session, and
+	// PeerCertificates are the subjects of the certificates presented by the server. They are
+	// empty for a plain HTTP endpoint.
+	TLSVersion       string
+	CipherSuite      string
+	ServerName       string
+	PeerCertificates []string
+}
+
+func (r ConnectionReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "endpoint %s\n", r.Endpoint)
+	if r.Proxy != "" {
+		fmt.Fprintf(&b, "proxy %s\n", r.Proxy)
+	}
+	fmt.Fprintf(&b, "local %s -> remote %s\n", r.LocalAddr, r.RemoteAddr)
+	if r.TLSVersion != "" {
+		fmt.Fprintf(&b, "%s %s, server name %s\n", r.TLSVersion, r.CipherSuite, r.ServerName)
+		for _, cert := range r.PeerCertificates {
+			fmt.Fprintf(&b, "certificate %s\n", cert)
+		}
+	}
+	return b.String()
+}
+
+// DiagnoseConnection makes a GetSendQuota request and reports the connection it used. The
+// report is returned even if the request fails, as long as a connection was made, so that, for
+// example, a signature error still shows which network path was taken.
+func (c *Config) DiagnoseConnection(ctx context.Context) (ConnectionReport, error) {
+	var r ConnectionReport
+	endpoint, err := c.endpoint()
+	if err != nil {
+		return r, err
+	}
+	r.Endpoint = endpoint
+	if req, err := http.NewRequest("GET", endpoint, nil); err == nil {
+		if u, err := http.ProxyFromEnvironment(req); err == nil && u != nil {
+			r.Proxy = u.Redacted()
+		}
+	}
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			r.LocalAddr = info.Conn.LocalAddr().String()
+			r.RemoteAddr = info.Conn.RemoteAddr().String()
+			if conn, ok := info.Conn.(*tls.Conn); ok {
+				r.setTLS(conn.ConnectionState())
+			}
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			if err == nil {
+				r.setTLS(state)
+			}
+		},
+	}
+	_, err = c.GetSendQuotaContext(httptrace.WithClientTrace(ctx, trace))
+	return r, err
+}
+
+func (r *ConnectionReport) setTLS(state tls.ConnectionState) {
+	r.TLSVersion = tlsVersionName(state.Version)
+	r.CipherSuite = tls.CipherSuiteName(state.CipherSuite)
+	r.ServerName = state.ServerName
+	r.PeerCertificates = nil
+	for _, cert := range state.PeerCertificates {
+		r.PeerCertificates = append(r.PeerCertificates, certName(cert))
+	}
+}
+
+func certName(cert *x509.Certificate) string {
+	return cert.Subject.String() + " (issuer " + cert.Issuer.String() + ")"
+}
+
+func tlsVersionName(v uint16) string {
+	switch v {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	}
+	return fmt.Sprintf("0x%04x", v)
+}
@@ -0,0 +1,33 @@
+package ses
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDiagnoseConnection(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<GetSendQuotaResponse/>"))
+	}))
+	defer srv.Close()
+	defer func(t http.RoundTripper) { http.DefaultTransport = t }(http.DefaultTransport)
+	http.DefaultTransport = srv.Client().Transport
+
+	c := Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET"}
+	r, err := c.DiagnoseConnection(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Endpoint != srv.URL || r.RemoteAddr != srv.Listener.Addr().String() || r.LocalAddr == "" {
+		t.Errorf("got report %+v", r)
+	}
+	if !strings.HasPrefix(r.TLSVersion, "TLS 1.") || r.CipherSuite == "" || len(r.PeerCertificates) == 0 {
+		t.Errorf("got TLS report %+v", r)
+	}
+	if s := r.String(); !strings.Contains(s, r.TLSVersion) || !strings.Contains(s, r.RemoteAddr) {
+		t.Errorf("got String %q", s)
+	}
+}
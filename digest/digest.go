@@ -0,0 +1,119 @@
+// Package digest combines the notifications sent to a recipient over a time window into a
+// single digest email.
+package digest
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+// An Event is a notification waiting to be included in a digest.
+type Event struct {
+	Time time.Time
+
+	// Data is the notification, for use by the digest template.
+	Data interface{}
+}
+
+// TemplateData is the data that a Digester's Template is executed with.
+type TemplateData struct {
+	Recipient string
+	Events    []Event
+}
+
+// A Digester accumulates events per recipient and, Window after the first event for a
+// recipient, emails them all in one digest. It is safe for concurrent use.
+type Digester struct {
+	SES ses.SenderAPI
+
+	From    string
+	Subject string
+
+	// Template renders the text body of the digest from a TemplateData, for example:
+	//
+	//  {{range .Events}}* {{.Data}}
+	//  {{end}}
+	Template *template.Template
+
+	// Window is how long events for a recipient are accumulated before the digest is sent.
+	Window time.Duration
+
+	// OnError, if non-nil, is called when sending a digest that was due at the end of a window
+	// fails. The events are discarded.
+	OnError func(recipient string, events []Event, err error)
+
+	mu      sync.Mutex
+	pending map[string]*pending
+}
+
+type pending struct {
+	events []Event
+	timer  *time.Timer
+}
+
+// Add adds an event with the given data to the next digest for recipient.
+func (d *Digester) Add(recipient string, data interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.pending == nil {
+		d.pending = make(map[string]*pending)
+	}
+	p := d.pending[recipient]
+	if p == nil {
+		p = &pending{}
+		p.timer = time.AfterFunc(d.Window, func() {
+			events := d.take(recipient, p)
+			if len(events) == 0 {
+				return
+			}
+			if err := d.send(context.Background(), recipient, events); err != nil && d.OnError != nil {
+				d.OnError(recipient, events, err)
+			}
+		})
+		d.pending[recipient] = p
+	}
+	p.events = append(p.events, Event{Time: time.Now(), Data: data})
+}
+
+// take removes and returns the events of p if it is still the pending digest for recipient.
+func (d *Digester) take(recipient string, p *pending) []Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.pending[recipient] != p {
+		return nil
+	}
+	delete(d.pending, recipient)
+	return p.events
+}
+
+// Flush sends all pending digests immediately, without waiting for their windows to end, and
+// returns the first error encountered. It is typically called before shutting down.
+func (d *Digester) Flush(ctx context.Context) error {
+	d.mu.Lock()
+	all := d.pending
+	d.pending = nil
+	d.mu.Unlock()
+
+	var firstErr error
+	for recipient, p := range all {
+		p.timer.Stop()
+		if err := d.send(ctx, recipient, p.events); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (d *Digester) send(ctx context.Context, recipient string, events []Event) error {
+	var body bytes.Buffer
+	if err := d.Template.Execute(&body, TemplateData{Recipient: recipient, Events: events}); err != nil {
+		return err
+	}
+	_, err := d.SES.SendEmailContext(ctx, d.From, recipient, d.Subject, body.String())
+	return err
+}
@@ -0,0 +1,69 @@
+package digest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+func testDigester(t *testing.T, window time.Duration) (*Digester, func() map[string]string) {
+	var mu sync.Mutex
+	bodies := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		mu.Lock()
+		bodies[r.Form.Get("Destination.ToAddresses.member.1")] += r.Form.Get("Message.Body.Text.Data")
+		mu.Unlock()
+		w.Write([]byte("<SendEmailResponse/>"))
+	}))
+	t.Cleanup(srv.Close)
+	d := &Digester{
+		SES:      &ses.Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET"},
+		From:     "noreply@example.com",
+		Subject:  "Your digest",
+		Template: template.Must(template.New("").Parse("{{range .Events}}[{{.Data}}]{{end}}")),
+		Window:   window,
+	}
+	return d, func() map[string]string {
+		mu.Lock()
+		defer mu.Unlock()
+		m := map[string]string{}
+		for k, v := range bodies {
+			m[k] = v
+		}
+		return m
+	}
+}
+
+func TestDigesterWindow(t *testing.T) {
+	d, bodies := testDigester(t, 20*time.Millisecond)
+	d.Add("a@example.com", "one")
+	d.Add("b@example.com", "x")
+	d.Add("a@example.com", "two")
+	if got := bodies(); len(got) != 0 {
+		t.Fatalf("got digests %v before window ended", got)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	got := bodies()
+	if got["a@example.com"] != "[one][two]" || got["b@example.com"] != "[x]" {
+		t.Errorf("got digests %v", got)
+	}
+}
+
+func TestDigesterFlush(t *testing.T) {
+	d, bodies := testDigester(t, time.Hour)
+	d.Add("a@example.com", "one")
+	if err := d.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := bodies(); got["a@example.com"] != "[one]" {
+		t.Errorf("got digests %v", got)
+	}
+}
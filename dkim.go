@@ -0,0 +1,96 @@
+package ses
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+type VerifyDomainDkimResult struct {
+	DkimTokens []string `xml:"DkimTokens>member"`
+}
+
+type VerifyDomainDkimResponse struct {
+	VerifyDomainDkimResult VerifyDomainDkimResult
+}
+
+// VerifyDomainDkim starts Easy DKIM setup for domain and returns the DKIM tokens. Each token
+// must be published as a CNAME record from <token>._domainkey.<domain> to
+// <token>.dkim.amazonses.com.
+func (c *Config) VerifyDomainDkim(domain string) ([]string, error) {
+	return c.VerifyDomainDkimContext(context.Background(), domain)
+}
+
+// VerifyDomainDkimContext is like VerifyDomainDkim but uses ctx for the request and any
+// retries.
+func (c *Config) VerifyDomainDkimContext(ctx context.Context, domain string) ([]string, error) {
+	data := make(url.Values)
+	data.Add("Action", "VerifyDomainDkim")
+	data.Add("Domain", domain)
+
+	res := VerifyDomainDkimResponse{}
+	err := c.call(ctx, "POST", data, &res)
+	return res.VerifyDomainDkimResult.DkimTokens, err
+}
+
+// SetIdentityDkimEnabled enables or disables Easy DKIM signing of email sent from identity.
+func (c *Config) SetIdentityDkimEnabled(identity string, enabled bool) error {
+	return c.SetIdentityDkimEnabledContext(context.Background(), identity, enabled)
+}
+
+// SetIdentityDkimEnabledContext is like SetIdentityDkimEnabled but uses ctx for the request and
+// any retries.
+func (c *Config) SetIdentityDkimEnabledContext(ctx context.Context, identity string, enabled bool) error {
+	data := make(url.Values)
+	data.Add("Action", "SetIdentityDkimEnabled")
+	data.Add("Identity", identity)
+	data.Add("DkimEnabled", strconv.FormatBool(enabled))
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+type IdentityDkimAttributes struct {
+	DkimEnabled bool
+
+	// DkimVerificationStatus is one of the VerificationStatus constants.
+	DkimVerificationStatus string
+
+	// DkimTokens is set for domain identities only.
+	DkimTokens []string `xml:"DkimTokens>member"`
+}
+
+type GetIdentityDkimAttributesResult struct {
+	DkimAttributes []struct {
+		Key   string                 `xml:"key"`
+		Value IdentityDkimAttributes `xml:"value"`
+	} `xml:"DkimAttributes>entry"`
+}
+
+type GetIdentityDkimAttributesResponse struct {
+	GetIdentityDkimAttributesResult GetIdentityDkimAttributesResult
+}
+
+// GetIdentityDkimAttributes returns the Easy DKIM attributes of each of the given identities,
+// keyed by identity.
+func (c *Config) GetIdentityDkimAttributes(identities ...string) (map[string]IdentityDkimAttributes, error) {
+	return c.GetIdentityDkimAttributesContext(context.Background(), identities...)
+}
+
+// GetIdentityDkimAttributesContext is like GetIdentityDkimAttributes but uses ctx for the
+// request and any retries.
+func (c *Config) GetIdentityDkimAttributesContext(ctx context.Context, identities ...string) (map[string]IdentityDkimAttributes, error) {
+	data := make(url.Values)
+	data.Add("Action", "GetIdentityDkimAttributes")
+	addMembers(data, "Identities", identities)
+
+	res := GetIdentityDkimAttributesResponse{}
+	if err := c.call(ctx, "GET", data, &res); err != nil {
+		return nil, err
+	}
+
+	attrs := make(map[string]IdentityDkimAttributes)
+	for _, e := range res.GetIdentityDkimAttributesResult.DkimAttributes {
+		attrs[e.Key] = e.Value
+	}
+	return attrs, nil
+}
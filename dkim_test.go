@@ -0,0 +1,67 @@
+package ses
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVerifyDomainDkim(t *testing.T) {
+	c, form := testServer(t, `<VerifyDomainDkimResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <VerifyDomainDkimResult>
+    <DkimTokens>
+      <member>vvjuipp74whm76gqoni7qmwwn4w4qusjiainivf6sf</member>
+      <member>3frqe7jn4obpuxjpwpolz6ipb3k5nvt2nhjpik2oy</member>
+      <member>wrqplteh7oodxnad7hsl4mixg2uavzneazxv5sxi2</member>
+    </DkimTokens>
+  </VerifyDomainDkimResult>
+</VerifyDomainDkimResponse>`)
+
+	tokens, err := c.VerifyDomainDkim("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"vvjuipp74whm76gqoni7qmwwn4w4qusjiainivf6sf", "3frqe7jn4obpuxjpwpolz6ipb3k5nvt2nhjpik2oy", "wrqplteh7oodxnad7hsl4mixg2uavzneazxv5sxi2"}
+	if !reflect.DeepEqual(tokens, want) {
+		t.Errorf("got tokens %v, want %v", tokens, want)
+	}
+	checkForm(t, *form, map[string]string{"Action": "VerifyDomainDkim", "Domain": "example.com"})
+}
+
+func TestSetIdentityDkimEnabled(t *testing.T) {
+	c, form := testServer(t, `<SetIdentityDkimEnabledResponse/>`)
+	if err := c.SetIdentityDkimEnabled("example.com", false); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"Action": "SetIdentityDkimEnabled", "Identity": "example.com", "DkimEnabled": "false"})
+}
+
+func TestGetIdentityDkimAttributes(t *testing.T) {
+	c, _ := testServer(t, `<GetIdentityDkimAttributesResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <GetIdentityDkimAttributesResult>
+    <DkimAttributes>
+      <entry>
+        <key>example.com</key>
+        <value>
+          <DkimEnabled>true</DkimEnabled>
+          <DkimVerificationStatus>Success</DkimVerificationStatus>
+          <DkimTokens>
+            <member>token1</member>
+            <member>token2</member>
+          </DkimTokens>
+        </value>
+      </entry>
+    </DkimAttributes>
+  </GetIdentityDkimAttributesResult>
+</GetIdentityDkimAttributesResponse>`)
+
+	attrs, err := c.GetIdentityDkimAttributes("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]IdentityDkimAttributes{
+		"example.com": {DkimEnabled: true, DkimVerificationStatus: VerificationStatusSuccess, DkimTokens: []string{"token1", "token2"}},
+	}
+	if !reflect.DeepEqual(attrs, want) {
+		t.Errorf("got %+v, want %+v", attrs, want)
+	}
+}
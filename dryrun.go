@@ -0,0 +1,52 @@
+package ses
+
+import (
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"net/url"
+)
+
+// DryRunMessage is a send call that was not made because Config.DryRun is set.
+type DryRunMessage struct {
+	// MessageID is the synthetic message ID returned by the send call.
+	MessageID string
+
+	// Action is the send call's SES action, "SendEmail", "SendRawEmail" or "SendEmailV2".
+	Action string
+
+	// Params holds the request parameters of SendEmail and SendRawEmail calls, and Input the
+	// message of SendEmailV2 calls.
+	Params url.Values
+	Input  *SendEmailInput
+}
+
+// dryRun records a short-circuited send with c.OnDryRun and returns its synthetic message ID.
+func (c *Config) dryRun(m DryRunMessage) string {
+	m.MessageID = "dryrun-" + newRequestID()
+	c.log(LogInfo, "ses dry run", "action", m.Action, "message_id", m.MessageID)
+	if c.OnDryRun != nil {
+		c.OnDryRun(m)
+	}
+	return m.MessageID
+}
+
+// dryRunParams returns data with the RawMessage.Data parameter set from raw, if it is non-nil.
+func dryRunParams(data url.Values, raw func() (io.Reader, error)) (url.Values, error) {
+	if raw == nil {
+		return data, nil
+	}
+	r, err := raw()
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(r, MaxRawMessageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > MaxRawMessageSize {
+		return nil, messageTooLarge(int64(len(b)))
+	}
+	data.Set("RawMessage.Data", base64.StdEncoding.EncodeToString(b))
+	return data, nil
+}
@@ -0,0 +1,39 @@
+package ses
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	c, form := testServer(t, `<SendEmailResponse/>`)
+	var msgs []DryRunMessage
+	c.DryRun = true
+	c.OnDryRun = func(m DryRunMessage) { msgs = append(msgs, m) }
+
+	res, err := c.SendEmail("a@example.com", SimulatorBounce, "s", "b", WithTag("campaign", "welcome"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *form != nil {
+		t.Error("request was made in dry run")
+	}
+	if id := messageID(res); !strings.HasPrefix(id, "dryrun-") {
+		t.Errorf("got message ID %q", id)
+	}
+	if len(msgs) != 1 || msgs[0].Action != "SendEmail" || msgs[0].MessageID != messageID(res) {
+		t.Fatalf("got %+v", msgs)
+	}
+	checkForm(t, msgs[0].Params, map[string]string{"Destination.ToAddresses.member.1": SimulatorBounce, "Tags.member.1.Value": "welcome"})
+
+	if _, err := c.SendRawEmailReader(context.Background(), bytes.NewReader([]byte("raw"))); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, msgs[1].Params, map[string]string{"Action": "SendRawEmail", "RawMessage.Data": "cmF3"})
+
+	if _, err := c.SendEmail("a@example.com", "not an address", "s", "b"); err == nil {
+		t.Error("invalid message in dry run: want validation error")
+	}
+}
@@ -0,0 +1,151 @@
+package ses
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// EffectiveConfig is a snapshot of the settings a Config uses, with defaults resolved and
+// secrets redacted, for debugging. Its JSON form uses the keys of the configuration file read
+// by ParseConfig where they correspond.
+type EffectiveConfig struct {
+	Region   string `json:"region"`
+	Endpoint string `json:"endpoint"`
+
+	// EndpointError is set if the endpoint can't be resolved, such as for an unknown region.
+	EndpointError string `json:"endpointError,omitempty"`
+
+	Credentials EffectiveCredentials `json:"credentials"`
+
+	Retry *EffectiveRetryPolicy `json:"retry,omitempty"`
+
+	RateLimit *EffectiveRateLimit `json:"rateLimit,omitempty"`
+
+	DuplicateWindow string `json:"duplicateWindow,omitempty"`
+	DryRun          bool   `json:"dryRun"`
+	SkipValidation  bool   `json:"skipValidation"`
+	LogRequests     bool   `json:"logRequests"`
+
+	// Hooks lists the hooks that are set, with their types, such as "Gate (*ses.FileGate)".
+	Hooks []string `json:"hooks"`
+}
+
+type EffectiveCredentials struct {
+	// Source is "static" for AccessKeyID and SecretAccessKey, or the type of the
+	// CredentialsProvider used.
+	Source string `json:"source"`
+
+	// AccessKeyID is the redacted access key ID of static credentials.
+	AccessKeyID      string `json:"accessKeyId,omitempty"`
+	HasSecurityToken bool   `json:"hasSecurityToken,omitempty"`
+}
+
+type EffectiveRetryPolicy struct {
+	MaxAttempts int     `json:"maxAttempts"`
+	BaseDelay   string  `json:"baseDelay"`
+	MaxDelay    string  `json:"maxDelay"`
+	Jitter      float64 `json:"jitter"`
+}
+
+type EffectiveRateLimit struct {
+	// Rate is the current rate in sends per second, or 0 if it has not been discovered yet.
+	Rate  float64 `json:"rate"`
+	Burst float64 `json:"burst"`
+}
+
+// String returns e as indented JSON.
+func (e EffectiveConfig) String() string {
+	b, _ := json.MarshalIndent(e, "", "  ")
+	return string(b)
+}
+
+// DumpConfig returns the effective configuration of c. It makes no requests, so credentials
+// from a provider are described only by the provider's type.
+func (c *Config) DumpConfig() EffectiveConfig {
+	e := EffectiveConfig{
+		Region:         c.region(),
+		DryRun:         c.DryRun,
+		SkipValidation: c.SkipValidation,
+		LogRequests:    c.LogRequests,
+		Hooks:          []string{},
+	}
+	if endpoint, err := c.endpoint(); err != nil {
+		e.EndpointError = err.Error()
+	} else {
+		e.Endpoint = endpoint
+	}
+
+	switch {
+	case c.Credentials != nil:
+		e.Credentials.Source = fmt.Sprintf("%T", c.Credentials)
+	case c.AccessKeyID != "":
+		e.Credentials = EffectiveCredentials{
+			Source:           "static",
+			AccessKeyID:      redact(c.AccessKeyID),
+			HasSecurityToken: c.SecurityToken != "",
+		}
+	default:
+		e.Credentials.Source = fmt.Sprintf("default (%T)", DefaultCredentials)
+	}
+
+	if p := c.RetryPolicy; p != nil {
+		e.Retry = &EffectiveRetryPolicy{
+			MaxAttempts: p.MaxAttempts,
+			BaseDelay:   p.BaseDelay.String(),
+			MaxDelay:    p.MaxDelay.String(),
+			Jitter:      p.Jitter,
+		}
+	}
+	if l := c.RateLimiter; l != nil {
+		l.mu.Lock()
+		if !l.disabled {
+			e.RateLimit = &EffectiveRateLimit{Rate: l.rate, Burst: l.burst}
+		}
+		l.mu.Unlock()
+	}
+	if g := c.DuplicateGuard; g != nil {
+		g.mu.Lock()
+		if g.window > 0 {
+			e.DuplicateWindow = g.window.String()
+		}
+		g.mu.Unlock()
+	}
+
+	hook := func(name string, v interface{}) {
+		e.Hooks = append(e.Hooks, fmt.Sprintf("%s (%T)", name, v))
+	}
+	if c.Gate != nil {
+		hook("Gate", c.Gate)
+	}
+	if c.ReturnPathPool != nil {
+		hook("ReturnPathPool", c.ReturnPathPool)
+	}
+	if c.IdempotencyStore != nil {
+		hook("IdempotencyStore", c.IdempotencyStore)
+	}
+	if c.OnDryRun != nil {
+		hook("OnDryRun", c.OnDryRun)
+	}
+	if c.Logger != nil {
+		hook("Logger", c.Logger)
+	}
+	if c.Metrics != nil {
+		hook("Metrics", c.Metrics)
+	}
+	if n := len(c.SubjectLinters); n > 0 {
+		e.Hooks = append(e.Hooks, fmt.Sprintf("SubjectLinters (%d)", n))
+	}
+	if n := len(c.Middleware); n > 0 {
+		e.Hooks = append(e.Hooks, fmt.Sprintf("Middleware (%d)", n))
+	}
+	return e
+}
+
+// redact returns s with all but its first and last 4 characters masked, or entirely masked if
+// it is short.
+func redact(s string) string {
+	if len(s) <= 12 {
+		return "****"
+	}
+	return s[:4] + "****" + s[len(s)-4:]
+}
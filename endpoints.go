@@ -0,0 +1,67 @@
+package ses
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultRegion is the region used by a Config with neither Region nor Endpoint set.
+const DefaultRegion = "us-east-1"
+
+// regions lists the AWS regions in which Amazon SES is available.
+var regions = map[string]bool{
+	"us-east-1":      true,
+	"us-east-2":      true,
+	"us-west-1":      true,
+	"us-west-2":      true,
+	"ca-central-1":   true,
+	"sa-east-1":      true,
+	"eu-west-1":      true,
+	"eu-west-2":      true,
+	"eu-west-3":      true,
+	"eu-central-1":   true,
+	"eu-north-1":     true,
+	"eu-south-1":     true,
+	"ap-south-1":     true,
+	"ap-northeast-1": true,
+	"ap-northeast-2": true,
+	"ap-northeast-3": true,
+	"ap-southeast-1": true,
+	"ap-southeast-2": true,
+	"ap-southeast-3": true,
+	"me-south-1":     true,
+	"af-south-1":     true,
+	"il-central-1":   true,
+	"us-gov-west-1":  true,
+	"us-gov-east-1":  true,
+	"cn-northwest-1": true,
+}
+
+// RegionEndpoint returns the HTTPS endpoint of the Amazon SES API in region, or an error if SES
+// isn't available in region.
+func RegionEndpoint(region string) (string, error) {
+	if !regions[region] {
+		return "", fmt.Errorf("ses: no known endpoint for region %q", region)
+	}
+	if strings.HasPrefix(region, "cn-") {
+		return "https://email." + region + ".amazonaws.com.cn", nil
+	}
+	return "https://email." + region + ".amazonaws.com", nil
+}
+
+// endpoint returns the endpoint to send requests to: c.Endpoint if set, or else the endpoint
+// of c.Region (or DefaultRegion).
+func (c *Config) endpoint() (string, error) {
+	if c.Endpoint != "" {
+		return c.Endpoint, nil
+	}
+	return RegionEndpoint(c.region())
+}
+
+// region returns c.Region, or DefaultRegion if it is not set.
+func (c *Config) region() string {
+	if c.Region == "" {
+		return DefaultRegion
+	}
+	return c.Region
+}
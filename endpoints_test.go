@@ -0,0 +1,47 @@
+package ses
+
+import "testing"
+
+func TestRegionEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"us-east-1":      "https://email.us-east-1.amazonaws.com",
+		"eu-west-1":      "https://email.eu-west-1.amazonaws.com",
+		"ap-northeast-1": "https://email.ap-northeast-1.amazonaws.com",
+		"cn-northwest-1": "https://email.cn-northwest-1.amazonaws.com.cn",
+	}
+	for region, want := range tests {
+		endpoint, err := RegionEndpoint(region)
+		if err != nil {
+			t.Errorf("%s: %s", region, err)
+			continue
+		}
+		if endpoint != want {
+			t.Errorf("%s: got %q, want %q", region, endpoint, want)
+		}
+	}
+
+	if _, err := RegionEndpoint("xx-nowhere-1"); err == nil {
+		t.Error("got nil error for unknown region")
+	}
+}
+
+func TestConfigEndpoint(t *testing.T) {
+	tests := []struct {
+		config Config
+		want   string
+	}{
+		{Config{}, "https://email.us-east-1.amazonaws.com"},
+		{Config{Region: "eu-west-1"}, "https://email.eu-west-1.amazonaws.com"},
+		{Config{Region: "eu-west-1", Endpoint: "http://localhost:4566"}, "http://localhost:4566"},
+	}
+	for _, test := range tests {
+		endpoint, err := test.config.endpoint()
+		if err != nil {
+			t.Errorf("%+v: %s", test.config, err)
+			continue
+		}
+		if endpoint != test.want {
+			t.Errorf("%+v: got %q, want %q", test.config, endpoint, test.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package ses
+
+import (
+	"errors"
+	"strings"
+)
+
+// Errors identifying the kind of a failed call, for use with errors.Is. API errors match the
+// kind implied by their code and message, and *ValidationError matches ErrValidation. Oversized
+// messages fail with ErrMessageTooLarge, and sends refused by Config.Gate or because sending is
+// paused for the account or configuration set match ErrSendingDisabled.
+var (
+	// ErrThrottled matches requests rejected because the maximum sending rate or API request
+	// rate was exceeded. They may succeed if retried later.
+	ErrThrottled = errors.New("ses: throttled")
+
+	// ErrQuotaExceeded matches sends rejected because the account's 24-hour sending quota is
+	// used up.
+	ErrQuotaExceeded = errors.New("ses: sending quota exceeded")
+
+	// ErrIdentityNotVerified matches sends rejected because the sender (or, in the sandbox, a
+	// recipient) is not a verified identity.
+	ErrIdentityNotVerified = errors.New("ses: identity not verified")
+
+	// ErrRecipientSuppressed matches sends rejected because a recipient is on a suppression
+	// list.
+	ErrRecipientSuppressed = errors.New("ses: recipient suppressed")
+
+	// ErrValidation matches messages and parameters rejected as invalid, whether by this
+	// package before sending or by SES.
+	ErrValidation = errors.New("ses: invalid request")
+)
+
+// validationCodes are the SES error codes for invalid parameters.
+var validationCodes = map[string]bool{
+	"InvalidParameterValue":       true,
+	"InvalidParameterCombination": true,
+	"MissingParameter":            true,
+	"ValidationError":             true,
+	"BadRequestException":         true,
+}
+
+// Is reports whether e is of the kind target, one of the sentinel errors of this package.
+func (e *APIError) Is(target error) bool {
+	msg := strings.ToLower(e.Message)
+	switch target {
+	case ErrThrottled:
+		return (e.Code == "Throttling" || e.Code == "TooManyRequestsException" || e.StatusCode == 429) && !e.Is(ErrQuotaExceeded)
+	case ErrQuotaExceeded:
+		return (e.Code == "Throttling" || e.Code == "LimitExceededException") && strings.Contains(msg, "quota")
+	case ErrIdentityNotVerified:
+		return e.Code == "MessageRejected" && strings.Contains(msg, "not verified")
+	case ErrRecipientSuppressed:
+		return e.Code == "MessageRejected" && (strings.Contains(msg, "blacklisted") || strings.Contains(msg, "suppression list"))
+	case ErrValidation:
+		return validationCodes[e.Code]
+	case ErrSendingDisabled:
+		return e.Code == "AccountSendingPausedException" || e.Code == "ConfigurationSetSendingPausedException" || e.Code == "SendingPausedException"
+	}
+	return false
+}
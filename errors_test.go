@@ -0,0 +1,54 @@
+package ses
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestErrorKinds(t *testing.T) {
+	kinds := []error{ErrThrottled, ErrQuotaExceeded, ErrIdentityNotVerified, ErrRecipientSuppressed, ErrValidation, ErrSendingDisabled}
+	for _, test := range []struct {
+		err  error
+		want error
+	}{
+		{&APIError{StatusCode: 400, Code: "Throttling", Message: "Maximum sending rate exceeded."}, ErrThrottled},
+		{&APIError{StatusCode: 429, Code: "TooManyRequestsException"}, ErrThrottled},
+		{&APIError{StatusCode: 400, Code: "Throttling", Message: "Daily message quota exceeded."}, ErrQuotaExceeded},
+		{&APIError{StatusCode: 400, Code: "MessageRejected", Message: "Email address is not verified. The following identities failed the check in region US-EAST-1: a@example.com"}, ErrIdentityNotVerified},
+		{&APIError{StatusCode: 400, Code: "MessageRejected", Message: "Address blacklisted."}, ErrRecipientSuppressed},
+		{&APIError{StatusCode: 400, Code: "InvalidParameterValue", Message: "Missing final '@domain'"}, ErrValidation},
+		{&APIError{StatusCode: 400, Code: "AccountSendingPausedException"}, ErrSendingDisabled},
+		{&ValidationError{"to", "nobody", "not a valid email address"}, ErrValidation},
+		{&RetryError{Attempts: 4, Err: &APIError{StatusCode: 429}}, ErrThrottled},
+		{fmt.Errorf("sending: %w", &APIError{StatusCode: 500, Code: "InternalFailure"}), nil},
+	} {
+		for _, kind := range kinds {
+			if got := errors.Is(test.err, kind); got != (kind == test.want) {
+				t.Errorf("errors.Is(%v, %v) = %v", test.err, kind, got)
+			}
+		}
+	}
+}
+
+func TestQuotaExceededNotRetried(t *testing.T) {
+	n := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`<ErrorResponse><Error><Type>Sender</Type><Code>Throttling</Code><Message>Daily message quota exceeded.</Message></Error></ErrorResponse>`))
+	}))
+	defer srv.Close()
+
+	c := &Config{
+		Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET",
+		RetryPolicy: &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+	}
+	_, err := c.SendEmail("a@example.com", "b@example.com", "s", "b")
+	if !errors.Is(err, ErrQuotaExceeded) || n != 1 {
+		t.Errorf("got %v after %d attempts, want ErrQuotaExceeded after 1", err, n)
+	}
+}
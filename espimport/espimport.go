@@ -0,0 +1,162 @@
+// Package espimport imports the suppression lists exported by other email service providers
+// (SendGrid and Mailgun, as CSV or JSON) into the SES account suppression list, easing
+// migrations onto SES.
+//
+// Each export holds one kind of suppression, so the parsers take the SES reason to record:
+// ses.SuppressionReasonBounce for bounces, blocks and invalid addresses, and
+// ses.SuppressionReasonComplaint for spam reports. Unsubscribes have no SES equivalent in the
+// account suppression list.
+package espimport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+// Suppression is an address suppressed by another provider.
+type Suppression struct {
+	Email  string
+	Reason string
+
+	// Created is when the provider suppressed the address, or zero if the export didn't say.
+	Created time.Time
+}
+
+// SendGridCSV parses a CSV export of a SendGrid suppression list (bounces, blocks, invalid
+// emails or spam reports), which has a header row with "email" and "created" columns.
+func SendGridCSV(r io.Reader, reason string) ([]Suppression, error) {
+	return parseCSV(r, reason, "email", "created")
+}
+
+// SendGridJSON parses the JSON array returned by the SendGrid suppression API, whose elements
+// have "email" and "created" (seconds since the Unix epoch) fields.
+func SendGridJSON(r io.Reader, reason string) ([]Suppression, error) {
+	var items []struct {
+		Email   string      `json:"email"`
+		Created json.Number `json:"created"`
+	}
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, err
+	}
+	var s []Suppression
+	for _, it := range items {
+		s = append(s, Suppression{it.Email, reason, parseTime(it.Created.String())})
+	}
+	return s, nil
+}
+
+// MailgunCSV parses a CSV export of a Mailgun suppression list (bounces or complaints), which
+// has a header row with "address" and "created_at" columns.
+func MailgunCSV(r io.Reader, reason string) ([]Suppression, error) {
+	return parseCSV(r, reason, "address", "created_at")
+}
+
+// MailgunJSON parses a page of the Mailgun bounces or complaints API, an object whose "items"
+// have "address" and "created_at" fields.
+func MailgunJSON(r io.Reader, reason string) ([]Suppression, error) {
+	var page struct {
+		Items []struct {
+			Address   string `json:"address"`
+			CreatedAt string `json:"created_at"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(r).Decode(&page); err != nil {
+		return nil, err
+	}
+	var s []Suppression
+	for _, it := range page.Items {
+		s = append(s, Suppression{it.Address, reason, parseTime(it.CreatedAt)})
+	}
+	return s, nil
+}
+
+// parseCSV parses CSV with a header row, taking addresses from the column emailCol and times
+// from the optional column timeCol.
+func parseCSV(r io.Reader, reason, emailCol, timeCol string) ([]Suppression, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	emailIdx, timeIdx := -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))) {
+		case emailCol:
+			emailIdx = i
+		case timeCol:
+			timeIdx = i
+		}
+	}
+	if emailIdx < 0 {
+		return nil, fmt.Errorf("espimport: no %q column in CSV header %q", emailCol, header)
+	}
+
+	var s []Suppression
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return s, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if emailIdx >= len(rec) || strings.TrimSpace(rec[emailIdx]) == "" {
+			continue
+		}
+		sup := Suppression{Email: strings.TrimSpace(rec[emailIdx]), Reason: reason}
+		if timeIdx >= 0 && timeIdx < len(rec) {
+			sup.Created = parseTime(rec[timeIdx])
+		}
+		s = append(s, sup)
+	}
+}
+
+// timeLayouts are the time formats found in provider exports.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"Mon, 02 Jan 2006 15:04:05 MST",
+	time.RFC1123Z,
+}
+
+// parseTime parses s as seconds since the Unix epoch or in one of timeLayouts, returning the
+// zero time if it is in none of them.
+func parseTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(secs, 0).UTC()
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+// Import adds the suppressions to the SES account suppression list of c, skipping duplicate
+// addresses. It returns the number of addresses added, and stops at the first error.
+func Import(c *ses.Config, suppressions []Suppression) (int, error) {
+	seen := make(map[string]bool)
+	n := 0
+	for _, s := range suppressions {
+		key := strings.ToLower(s.Email)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if err := c.PutSuppressedDestination(s.Email, s.Reason); err != nil {
+			return n, fmt.Errorf("espimport: %s: %w", s.Email, err)
+		}
+		n++
+	}
+	return n, nil
+}
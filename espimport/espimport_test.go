@@ -0,0 +1,89 @@
+package espimport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+func TestSendGridCSV(t *testing.T) {
+	s, err := SendGridCSV(strings.NewReader("\ufeffemail,created,reason,status\na@example.com,2020-01-02 03:04:05,550 mailbox unavailable,5.1.1\n,,,\nb@example.com,1577934245,,\n"), ses.SuppressionReasonBounce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Suppression{
+		{"a@example.com", ses.SuppressionReasonBounce, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"b@example.com", ses.SuppressionReasonBounce, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("got %+v, want %+v", s, want)
+	}
+
+	if _, err := SendGridCSV(strings.NewReader("address\na@example.com\n"), ses.SuppressionReasonBounce); err == nil {
+		t.Error("got no error for a CSV without an email column")
+	}
+}
+
+func TestSendGridJSON(t *testing.T) {
+	s, err := SendGridJSON(strings.NewReader(`[{"created":1577934245,"email":"a@example.com","reason":"spam"}]`), ses.SuppressionReasonComplaint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Suppression{{"a@example.com", ses.SuppressionReasonComplaint, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)}}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("got %+v, want %+v", s, want)
+	}
+}
+
+func TestMailgun(t *testing.T) {
+	s, err := MailgunCSV(strings.NewReader("address,code,error,created_at\na@example.com,550,No such mailbox,\"Thu, 02 Jan 2020 03:04:05 UTC\"\n"), ses.SuppressionReasonBounce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Suppression{{"a@example.com", ses.SuppressionReasonBounce, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)}}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("CSV: got %+v, want %+v", s, want)
+	}
+
+	s, err = MailgunJSON(strings.NewReader(`{"items":[{"address":"a@example.com","created_at":"Thu, 02 Jan 2020 03:04:05 UTC"}],"paging":{}}`), ses.SuppressionReasonBounce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("JSON: got %+v, want %+v", s, want)
+	}
+}
+
+func TestImport(t *testing.T) {
+	var puts []map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in map[string]string
+		json.NewDecoder(r.Body).Decode(&in)
+		puts = append(puts, in)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := &ses.Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET"}
+	n, err := Import(c, []Suppression{
+		{Email: "a@example.com", Reason: ses.SuppressionReasonBounce},
+		{Email: "A@example.com", Reason: ses.SuppressionReasonBounce},
+		{Email: "b@example.com", Reason: ses.SuppressionReasonComplaint},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []map[string]string{
+		{"EmailAddress": "a@example.com", "Reason": "BOUNCE"},
+		{"EmailAddress": "b@example.com", "Reason": "COMPLAINT"},
+	}
+	if n != 2 || !reflect.DeepEqual(puts, want) {
+		t.Errorf("got %d, %v; want 2, %v", n, puts, want)
+	}
+}
@@ -0,0 +1,3 @@
+#!/bin/sh
+# Run by Localstack when it is ready: verifies the sender used by refservice.
+awslocal ses verify-email-identity --email-address sender@example.com
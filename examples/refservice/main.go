@@ -0,0 +1,94 @@
+// Command refservice is a reference email service built from the packages of go-ses. It
+// accepts send requests over HTTP, renders them from message templates, and queues them for
+// delivery through Amazon SES; it receives bounce and complaint notifications from SNS and adds
+// the recipients to the suppression list; and it serves the admin pages and request metrics.
+//
+// The endpoints are:
+//
+//	POST /send             queue a templated message (see sendRequest)
+//	POST /notifications    SNS subscription for SES bounce and complaint notifications
+//	     /admin/           sending quota, queue depth and suppression list (see package admin)
+//	GET  /debug/vars       expvar metrics, including per-action SES request metrics
+//	GET  /healthz          reports the queue length
+//
+// /notifications only accepts messages signed by Amazon SNS, so it must be subscribed to the
+// bounce and complaint topics of a real SES account; it is not exercised by the Localstack
+// setup below, which covers sending, the admin pages and metrics.
+//
+// Credentials, region and endpoint are read from the environment, as for ses.EnvConfig. To run
+// it against Localstack:
+//
+//	docker-compose up
+//	curl -H 'Authorization: Bearer secret' -d '{"template": "welcome", "to": "b@example.com", "data": {"name": "Bo"}}' localhost:8080/send
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sourcegraph/go-ses"
+	"github.com/sourcegraph/go-ses/queue"
+)
+
+func main() {
+	var (
+		addr      = flag.String("addr", ":8080", "HTTP listen address")
+		from      = flag.String("from", os.Getenv("REFSERVICE_FROM"), "sender address of all messages")
+		token     = flag.String("token", os.Getenv("REFSERVICE_TOKEN"), "bearer token required by /send and /admin/ (none if empty)")
+		configSet = flag.String("configuration-set", "", "configuration set to send with")
+		queueDir  = flag.String("queue-dir", "", "directory to persist the queue in (in memory if empty)")
+		templates = flag.String("templates", "", "directory of additional NAME.json message templates")
+	)
+	flag.Parse()
+	if *from == "" {
+		log.Fatal("refservice: -from is required")
+	}
+
+	c := ses.EnvConfig
+	c.RetryPolicy = &ses.DefaultRetryPolicy
+	c.Metrics = ses.NewExpvarMetrics("ses")
+	c.Logger = ses.NewStdLogger(log.New(os.Stderr, "", log.LstdFlags), ses.LogWarn)
+
+	var store queue.Store = &queue.MemoryStore{}
+	if *queueDir != "" {
+		if err := os.MkdirAll(*queueDir, 0700); err != nil {
+			log.Fatal("refservice: ", err)
+		}
+		store = &queue.FileStore{Dir: *queueDir, Logger: c.Logger}
+	}
+	s, err := newService(&c, store, *from, *configSet, *templates)
+	if err != nil {
+		log.Fatal("refservice: ", err)
+	}
+	s.token = *token
+	s.loadSuppressions()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		cancel()
+	}()
+	go func() {
+		if err := s.queue.Run(ctx); err != nil && err != context.Canceled {
+			log.Fatal("refservice: queue: ", err)
+		}
+	}()
+
+	srv := &http.Server{Addr: *addr, Handler: s}
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(context.Background())
+	}()
+	log.Printf("refservice: listening on %s", *addr)
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal("refservice: ", err)
+	}
+}
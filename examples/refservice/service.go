@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+	"github.com/sourcegraph/go-ses/admin"
+	"github.com/sourcegraph/go-ses/notifications"
+	"github.com/sourcegraph/go-ses/queue"
+	"github.com/sourcegraph/go-ses/server"
+)
+
+// A service is the reference email service. Its zero value is not usable; create one with
+// newService.
+type service struct {
+	ses       *ses.Config
+	queue     *queue.Queue
+	templates map[string]*messageTemplate
+	from      string
+	configSet string
+
+	// token, if non-empty, is the bearer token required by /send and /admin/.
+	token string
+
+	mux *http.ServeMux
+
+	mu         sync.Mutex
+	suppressed map[string]bool
+}
+
+// newService returns a service that sends with c, queueing messages in store. The built-in
+// templates are extended with those in templateDir, if it is non-empty.
+func newService(c *ses.Config, store queue.Store, from, configSet, templateDir string) (*service, error) {
+	templates, err := loadTemplates(templateDir)
+	if err != nil {
+		return nil, err
+	}
+	s := &service{
+		ses:        c,
+		templates:  templates,
+		from:       from,
+		configSet:  configSet,
+		suppressed: make(map[string]bool),
+	}
+	s.queue = &queue.Queue{SES: c, Store: store, Workers: 4, OnResult: s.onResult, Logger: c.Logger}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("/send", s.authenticated(s.serveSend))
+	s.mux.Handle("/notifications", &notifications.Handler{
+		OnBounce:             s.onBounce,
+		OnComplaint:          s.onComplaint,
+		ConfirmSubscriptions: true,
+		Seen:                 notifications.NewMemorySeenStore(time.Hour),
+		Logger:               c.Logger,
+	})
+	s.mux.Handle("/admin/", s.authenticated(http.StripPrefix("/admin", &admin.Handler{SES: c, Queue: s.queue}).ServeHTTP))
+	s.mux.Handle("/debug/vars", expvar.Handler())
+	s.mux.HandleFunc("/healthz", s.serveHealth)
+	return s, nil
+}
+
+func (s *service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// authenticated returns h wrapped to require s.token, if it is set.
+func (s *service) authenticated(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" && server.BearerToken(s.token)(r) != nil {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		h(w, r)
+	}
+}
+
+// sendRequest is the body of a POST /send request.
+type sendRequest struct {
+	Template string                 `json:"template"`
+	To       string                 `json:"to"`
+	Data     map[string]interface{} `json:"data"`
+
+	// SendAt, if non-zero, delays the message until then.
+	SendAt time.Time `json:"sendAt"`
+
+	// IdempotencyKey, if non-empty, identifies the message so that a retried request doesn't
+	// send it twice.
+	IdempotencyKey string `json:"idempotencyKey"`
+}
+
+func (s *service) serveSend(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req sendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "malformed request: " + err.Error()})
+		return
+	}
+	id, err := s.send(req)
+	switch {
+	case errors.Is(err, ses.ErrRecipientSuppressed):
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+	case errors.Is(err, ses.ErrValidation), errors.Is(err, errUnknownTemplate), errors.Is(err, errTemplateData):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case err != nil:
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	default:
+		writeJSON(w, http.StatusAccepted, map[string]string{"id": id})
+	}
+}
+
+// send renders req and queues the message, returning its queue item ID.
+func (s *service) send(req sendRequest) (string, error) {
+	if err := ses.ValidateAddress(req.To); err != nil {
+		return "", err
+	}
+	if s.isSuppressed(req.To) {
+		return "", fmt.Errorf("%w: %s", ses.ErrRecipientSuppressed, req.To)
+	}
+	t, ok := s.templates[req.Template]
+	if !ok {
+		return "", fmt.Errorf("%w %q", errUnknownTemplate, req.Template)
+	}
+	m, err := t.render(req.Data)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", errTemplateData, err)
+	}
+	m.From = s.from
+	m.To = req.To
+	m.ConfigurationSet = s.configSet
+	m.Tags = []ses.MessageTag{{Name: "template", Value: req.Template}}
+	m.IdempotencyKey = req.IdempotencyKey
+	return s.queue.Enqueue(m, req.SendAt)
+}
+
+func (s *service) serveHealth(w http.ResponseWriter, r *http.Request) {
+	n, err := s.queue.Len()
+	if err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"queued": n})
+}
+
+func (s *service) onResult(res queue.Result) {
+	if res.Err != nil {
+		log.Printf("refservice: dropped message %s to %s after %d attempts: %s", res.Item.ID, res.Item.Message.To, res.Item.Attempts+1, res.Err)
+	}
+}
+
+func (s *service) onBounce(ctx context.Context, n *notifications.Notification) error {
+	if n.Bounce == nil {
+		return errors.New("bounce notification without bounce details")
+	}
+	if n.Bounce.BounceType != notifications.BounceTypePermanent {
+		return nil
+	}
+	for _, r := range n.Bounce.BouncedRecipients {
+		if err := s.suppress(ctx, r.EmailAddress, ses.SuppressionReasonBounce); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *service) onComplaint(ctx context.Context, n *notifications.Notification) error {
+	if n.Complaint == nil {
+		return errors.New("complaint notification without complaint details")
+	}
+	for _, r := range n.Complaint.ComplainedRecipients {
+		if err := s.suppress(ctx, r.EmailAddress, ses.SuppressionReasonComplaint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// suppress adds email to the account suppression list, and stops further messages to it from
+// being queued. SES maintains the account suppression list itself when it is enabled, but
+// adding to it here makes the service behave the same where it isn't, as with Localstack.
+func (s *service) suppress(ctx context.Context, email, reason string) error {
+	s.mu.Lock()
+	s.suppressed[strings.ToLower(email)] = true
+	s.mu.Unlock()
+	return s.ses.PutSuppressedDestinationContext(ctx, email, reason)
+}
+
+func (s *service) isSuppressed(email string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.suppressed[strings.ToLower(email)]
+}
+
+// loadSuppressions adds the addresses on the account suppression list to s.suppressed. It
+// logs errors instead of failing, since queued messages to suppressed addresses are dropped by
+// SES anyway.
+func (s *service) loadSuppressions() {
+	err := s.ses.ListSuppressedDestinationsPages(ses.SuppressedDestinationFilter{}, 1000, func(res ses.ListSuppressedDestinationsResult) bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, d := range res.SuppressedDestinationSummaries {
+			s.suppressed[strings.ToLower(d.EmailAddress)] = true
+		}
+		return true
+	})
+	if err != nil {
+		log.Printf("refservice: loading suppression list: %s", err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+	"github.com/sourcegraph/go-ses/notifications"
+	"github.com/sourcegraph/go-ses/queue"
+	"github.com/sourcegraph/go-ses/sestest"
+)
+
+// TestService sends a message through the HTTP API and queue to a sestest.Server, and checks
+// that a bounce suppresses the recipient both locally and on the account suppression list.
+func TestService(t *testing.T) {
+	fake := sestest.NewServer()
+	defer fake.Close()
+	fake.RequireVerified(true)
+	fake.SetVerificationStatus("sender@example.com", ses.VerificationStatusSuccess)
+
+	// sestest handles the SESv1 API. Serve the SESv2 suppression list alongside it.
+	var mu sync.Mutex
+	var suppressed []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/v2/") {
+			fake.ServeHTTP(w, r)
+			return
+		}
+		if r.Method == "PUT" {
+			var in struct{ EmailAddress string }
+			json.NewDecoder(r.Body).Decode(&in)
+			mu.Lock()
+			suppressed = append(suppressed, in.EmailAddress)
+			mu.Unlock()
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	c := fake.Config()
+	c.Endpoint = srv.URL
+
+	s, err := newService(c, &queue.MemoryStore{}, "sender@example.com", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.token = "secret"
+	s.queue.PollInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.queue.Run(ctx)
+
+	post := func(body string) int {
+		req := httptest.NewRequest("POST", "/send", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		return w.Code
+	}
+	if code := post(`{"template": "welcome", "to": "b@example.com", "data": {"name": "Bo"}}`); code != http.StatusAccepted {
+		t.Fatalf("got status %d, want 202", code)
+	}
+	for deadline := time.Now().Add(5 * time.Second); len(fake.Messages()) == 0; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("message was not sent")
+		}
+	}
+	if m := fake.Messages()[0]; m.Subject != "Welcome, Bo" || !strings.Contains(m.Text, "Hi Bo,") || m.To[0] != "b@example.com" {
+		t.Errorf("got message %+v", m)
+	}
+
+	for body, want := range map[string]int{
+		`{"template": "nope", "to": "b@example.com"}`:                                                         http.StatusBadRequest,
+		`{"template": "welcome", "to": "nobody"}`:                                                             http.StatusBadRequest,
+		`{"template": "welcome", "to": "b@example.com"}`:                                                      http.StatusBadRequest, // missing name
+		`{"template": "password-reset", "to": "b@example.com", "data": {"url": "https://example.com/reset"}}`: http.StatusAccepted,
+	} {
+		if code := post(body); code != want {
+			t.Errorf("%s: got status %d, want %d", body, code, want)
+		}
+	}
+
+	err = s.onBounce(ctx, &notifications.Notification{Bounce: &notifications.Bounce{
+		BounceType:        notifications.BounceTypePermanent,
+		BouncedRecipients: []notifications.BouncedRecipient{{EmailAddress: "B@example.com"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	if len(suppressed) != 1 || suppressed[0] != "B@example.com" {
+		t.Errorf("got suppressed %v", suppressed)
+	}
+	mu.Unlock()
+	if code := post(`{"template": "welcome", "to": "b@example.com", "data": {"name": "Bo"}}`); code != http.StatusUnprocessableEntity {
+		t.Errorf("got status %d for a suppressed recipient, want 422", code)
+	}
+
+	req := httptest.NewRequest("GET", "/admin/", nil)
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("got status %d for /admin/ without a token, want 401", w.Code)
+	}
+}
+
+func TestServiceMalformedNotification(t *testing.T) {
+	s, err := newService(&ses.Config{}, &queue.MemoryStore{}, "sender@example.com", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := s.onBounce(ctx, &notifications.Notification{NotificationType: notifications.TypeBounce}); err == nil {
+		t.Error("bounce without details: want error")
+	}
+	if err := s.onComplaint(ctx, &notifications.Notification{NotificationType: notifications.TypeComplaint}); err == nil {
+		t.Error("complaint without details: want error")
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/sourcegraph/go-ses/queue"
+)
+
+var (
+	errUnknownTemplate = errors.New("unknown template")
+	errTemplateData    = errors.New("invalid template data")
+)
+
+// templateSource is the source of a message template, as found in a NAME.json file in the
+// -templates directory. Each part is executed with the data of the send request.
+type templateSource struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// builtinTemplates are the templates available without a -templates directory.
+var builtinTemplates = map[string]templateSource{
+	"welcome": {
+		Subject: "Welcome, {{.name}}",
+		Text:    "Hi {{.name}},\n\nThanks for signing up.\n",
+		HTML:    "<p>Hi {{.name}},</p>\n<p>Thanks for signing up.</p>\n",
+	},
+	"password-reset": {
+		Subject: "Reset your password",
+		Text:    "Reset your password by visiting {{.url}}\n\nIf you didn't ask to reset it, ignore this email.\n",
+		HTML:    "<p><a href=\"{{.url}}\">Reset your password</a></p>\n<p>If you didn't ask to reset it, ignore this email.</p>\n",
+	},
+}
+
+type messageTemplate struct {
+	subject, text *template.Template
+	html          *htmltemplate.Template
+}
+
+func parseTemplate(name string, src templateSource) (*messageTemplate, error) {
+	t := &messageTemplate{}
+	var err error
+	if t.subject, err = template.New(name + ".subject").Option("missingkey=error").Parse(src.Subject); err != nil {
+		return nil, err
+	}
+	if t.text, err = template.New(name + ".text").Option("missingkey=error").Parse(src.Text); err != nil {
+		return nil, err
+	}
+	if src.HTML != "" {
+		if t.html, err = htmltemplate.New(name + ".html").Option("missingkey=error").Parse(src.HTML); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// render returns the message for data, without its sender and recipient.
+func (t *messageTemplate) render(data map[string]interface{}) (queue.Message, error) {
+	var m queue.Message
+	var b bytes.Buffer
+	if err := t.subject.Execute(&b, data); err != nil {
+		return m, err
+	}
+	m.Subject = b.String()
+	b.Reset()
+	if err := t.text.Execute(&b, data); err != nil {
+		return m, err
+	}
+	m.Text = b.String()
+	if t.html != nil {
+		b.Reset()
+		if err := t.html.Execute(&b, data); err != nil {
+			return m, err
+		}
+		m.HTML = b.String()
+	}
+	return m, nil
+}
+
+// loadTemplates parses the built-in templates and the NAME.json files in dir, if it is
+// non-empty.
+func loadTemplates(dir string) (map[string]*messageTemplate, error) {
+	sources := make(map[string]templateSource)
+	for name, src := range builtinTemplates {
+		sources[name] = src
+	}
+	if dir != "" {
+		files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			data, err := ioutil.ReadFile(f)
+			if err != nil {
+				return nil, err
+			}
+			var src templateSource
+			if err := json.Unmarshal(data, &src); err != nil {
+				return nil, fmt.Errorf("%s: %w", f, err)
+			}
+			sources[strings.TrimSuffix(filepath.Base(f), ".json")] = src
+		}
+	}
+
+	templates := make(map[string]*messageTemplate)
+	for name, src := range sources {
+		t, err := parseTemplate(name, src)
+		if err != nil {
+			return nil, err
+		}
+		templates[name] = t
+	}
+	return templates, nil
+}
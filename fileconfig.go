@@ -0,0 +1,261 @@
+package ses
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+)
+
+// fileConfig is the JSON form of a Config read by ParseConfig.
+type fileConfig struct {
+	Region   string `json:"region"`
+	Endpoint string `json:"endpoint"`
+
+	Credentials *struct {
+		// Source is "default", "env", "static", "shared", "ecs" or "ec2".
+		Source          string `json:"source"`
+		AccessKeyID     string `json:"accessKeyId"`
+		SecretAccessKey string `json:"secretAccessKey"`
+		SessionToken    string `json:"sessionToken"`
+		Filename        string `json:"filename"`
+		Profile         string `json:"profile"`
+	} `json:"credentials"`
+
+	Retry *struct {
+		MaxAttempts int     `json:"maxAttempts"`
+		BaseDelay   string  `json:"baseDelay"`
+		MaxDelay    string  `json:"maxDelay"`
+		Jitter      float64 `json:"jitter"`
+	} `json:"retry"`
+
+	RateLimit *struct {
+		Rate  float64 `json:"rate"`
+		Burst int     `json:"burst"`
+	} `json:"rateLimit"`
+
+	DuplicateWindow string   `json:"duplicateWindow"`
+	Disabled        []string `json:"disabled"`
+	LogLevel        string   `json:"logLevel"`
+	LogRequests     bool     `json:"logRequests"`
+}
+
+// LoadConfig reads the JSON configuration file filename and returns the Config it describes;
+// see ParseConfig.
+func LoadConfig(filename string) (*Config, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	c, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return c, nil
+}
+
+// ParseConfig returns the Config described by the JSON document data, so that a client can be
+// configured without code changes. For example:
+//
+//	{
+//	  "region": "eu-west-1",
+//	  "credentials": {"source": "static", "accessKeyId": "${SES_KEY_ID}", "secretAccessKey": "${SES_SECRET}"},
+//	  "retry": {"maxAttempts": 4, "baseDelay": "100ms", "maxDelay": "5s", "jitter": 0.5},
+//	  "rateLimit": {"rate": 0, "burst": 1},
+//	  "duplicateWindow": "10m",
+//	  "disabled": ["marketing", "campaign=spring-sale"],
+//	  "logLevel": "warn"
+//	}
+//
+// All fields are optional. The credentials source is one of "default" (DefaultCredentials, the
+// default), "env", "static", "shared" (with optional "filename" and "profile"), "ecs" and
+// "ec2". A rate of 0 discovers the rate from GetSendQuota. disabled lists the message kinds
+// (see Gate) whose sending is paused, and installs a KindGate even if it is empty, so that
+// kinds can be paused later with Reload. logLevel enables a Logger writing
+// to the standard logger at "debug", "info", "warn" or "error" level.
+//
+// In string values, $VAR and ${VAR} are replaced by the value of the environment variable VAR,
+// ${VAR:-default} by default if VAR is unset or empty, and $$ by $. Referring to an unset
+// variable without a default is an error, as are unknown fields and invalid values.
+func ParseConfig(data []byte) (*Config, error) {
+	var fc fileConfig
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&fc); err != nil {
+		return nil, fmt.Errorf("ses: config: %w", err)
+	}
+	c, err := fc.config()
+	if err != nil {
+		return nil, fmt.Errorf("ses: config: %w", err)
+	}
+	return c, nil
+}
+
+// config validates fc and returns its Config.
+func (fc *fileConfig) config() (*Config, error) {
+	var err error
+	expand := func(s string) string {
+		return os.Expand(s, func(name string) string {
+			if name == "$" {
+				return "$"
+			}
+			var def string
+			hasDef := false
+			if i := strings.Index(name, ":-"); i >= 0 {
+				name, def, hasDef = name[:i], name[i+2:], true
+			}
+			v, ok := os.LookupEnv(name)
+			switch {
+			case hasDef && v == "":
+				return def
+			case !ok && err == nil:
+				err = fmt.Errorf("environment variable %s is not set", name)
+			}
+			return v
+		})
+	}
+	duration := func(field, s string) time.Duration {
+		if s = expand(s); s == "" || err != nil {
+			return 0
+		}
+		d, perr := time.ParseDuration(s)
+		if perr != nil || d < 0 {
+			err = fmt.Errorf("invalid %s %q", field, s)
+		}
+		return d
+	}
+
+	c := &Config{
+		Region:      expand(fc.Region),
+		Endpoint:    expand(fc.Endpoint),
+		LogRequests: fc.LogRequests,
+	}
+	if c.Endpoint == "" && c.Region != "" {
+		if _, rerr := RegionEndpoint(c.Region); rerr != nil && err == nil {
+			err = fmt.Errorf("unknown region %q", c.Region)
+		}
+	}
+
+	if cr := fc.Credentials; cr != nil {
+		switch source := expand(cr.Source); source {
+		case "", "default":
+		case "env":
+			c.Credentials = EnvProvider{}
+		case "static":
+			p := StaticProvider{AccessKeyID: expand(cr.AccessKeyID), SecretAccessKey: expand(cr.SecretAccessKey), SecurityToken: expand(cr.SessionToken)}
+			if (p.AccessKeyID == "" || p.SecretAccessKey == "") && err == nil {
+				err = fmt.Errorf("static credentials require accessKeyId and secretAccessKey")
+			}
+			c.Credentials = p
+		case "shared":
+			c.Credentials = &SharedCredentialsProvider{Filename: expand(cr.Filename), Profile: expand(cr.Profile)}
+		case "ecs":
+			c.Credentials = &ECSProvider{}
+		case "ec2":
+			c.Credentials = &EC2RoleProvider{}
+		default:
+			if err == nil {
+				err = fmt.Errorf("unknown credentials source %q", source)
+			}
+		}
+	}
+
+	if r := fc.Retry; r != nil {
+		p := &RetryPolicy{
+			MaxAttempts: r.MaxAttempts,
+			BaseDelay:   duration("retry.baseDelay", r.BaseDelay),
+			MaxDelay:    duration("retry.maxDelay", r.MaxDelay),
+			Jitter:      r.Jitter,
+		}
+		if p.MaxAttempts < 1 && err == nil {
+			err = fmt.Errorf("retry.maxAttempts must be at least 1")
+		}
+		if (p.Jitter < 0 || p.Jitter > 1) && err == nil {
+			err = fmt.Errorf("retry.jitter must be between 0 and 1")
+		}
+		c.RetryPolicy = p
+	}
+
+	if rl := fc.RateLimit; rl != nil {
+		if rl.Rate < 0 && err == nil {
+			err = fmt.Errorf("rateLimit.rate must not be negative")
+		}
+		c.RateLimiter = NewRateLimiter(rl.Rate, rl.Burst)
+	}
+
+	if w := duration("duplicateWindow", fc.DuplicateWindow); w > 0 {
+		c.DuplicateGuard = NewDuplicateGuard(w)
+	}
+
+	if fc.Disabled != nil {
+		kinds := make([]string, len(fc.Disabled))
+		for i, k := range fc.Disabled {
+			kinds[i] = expand(k)
+		}
+		c.Gate = NewKindGate(kinds...)
+	}
+
+	if level := expand(fc.LogLevel); level != "" {
+		min, ok := map[string]LogLevel{"debug": LogDebug, "info": LogInfo, "warn": LogWarn, "error": LogError}[strings.ToLower(level)]
+		if !ok && err == nil {
+			err = fmt.Errorf("unknown logLevel %q", level)
+		}
+		c.Logger = NewStdLogger(nil, min)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload reads the JSON configuration file filename (see ParseConfig) and applies the settings
+// that can be changed while c is in use: the rate limit, the duplicate window and the disabled
+// kinds. A rate of 0 makes the limiter rediscover the rate from GetSendQuota. Removing one of
+// these settings from the file disables c's rate limiter or duplicate guard, or enables all
+// kinds. It is an error for the file to add one that c was created without (or, for disabled
+// kinds, to have a Gate other than a *KindGate), since it can't be added safely while c is in
+// use. The other settings in the file are validated but ignored.
+func (c *Config) Reload(filename string) error {
+	nc, err := LoadConfig(filename)
+	if err != nil {
+		return err
+	}
+	gate, isKindGate := c.Gate.(*KindGate)
+	switch {
+	case nc.RateLimiter != nil && c.RateLimiter == nil:
+		return fmt.Errorf("ses: config: %s: can't enable rateLimit on reload", filename)
+	case nc.DuplicateGuard != nil && c.DuplicateGuard == nil:
+		return fmt.Errorf("ses: config: %s: can't enable duplicateWindow on reload", filename)
+	case nc.Gate != nil && !isKindGate:
+		return fmt.Errorf("ses: config: %s: can't enable disabled on reload without a KindGate", filename)
+	}
+
+	if l := c.RateLimiter; l != nil {
+		if nl := nc.RateLimiter; nl != nil {
+			l.SetRate(nl.Rate())
+			l.SetBurst(int(nl.burst))
+			l.SetEnabled(true)
+		} else {
+			l.SetEnabled(false)
+		}
+	}
+	if g := c.DuplicateGuard; g != nil {
+		var window time.Duration
+		if ng := nc.DuplicateGuard; ng != nil {
+			window = ng.window
+		}
+		g.SetWindow(window)
+	}
+	if isKindGate {
+		var kinds []string
+		if ng, ok := nc.Gate.(*KindGate); ok {
+			kinds = ng.Disabled()
+		}
+		gate.SetDisabled(kinds...)
+	}
+	return nil
+}
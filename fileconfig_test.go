@@ -0,0 +1,175 @@
+package ses
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfig(t *testing.T) {
+	os.Setenv("SESTEST_KEY_ID", "AKID")
+	os.Setenv("SESTEST_SECRET", "p$ss")
+	defer os.Unsetenv("SESTEST_KEY_ID")
+	defer os.Unsetenv("SESTEST_SECRET")
+
+	c, err := ParseConfig([]byte(`{
+  "region": "${SESTEST_REGION:-eu-west-1}",
+  "credentials": {"source": "static", "accessKeyId": "$SESTEST_KEY_ID", "secretAccessKey": "${SESTEST_SECRET}$$"},
+  "retry": {"maxAttempts": 3, "baseDelay": "50ms", "maxDelay": "1s", "jitter": 0.2},
+  "rateLimit": {"rate": 14, "burst": 2},
+  "duplicateWindow": "10m",
+  "logLevel": "warn"
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Region != "eu-west-1" {
+		t.Errorf("got Region %q", c.Region)
+	}
+	if p, ok := c.Credentials.(StaticProvider); !ok || p.AccessKeyID != "AKID" || p.SecretAccessKey != "p$ss$" {
+		t.Errorf("got Credentials %#v", c.Credentials)
+	}
+	if p := c.RetryPolicy; p == nil || *p != (RetryPolicy{MaxAttempts: 3, BaseDelay: 50 * time.Millisecond, MaxDelay: time.Second, Jitter: 0.2}) {
+		t.Errorf("got RetryPolicy %+v", p)
+	}
+	if c.RateLimiter == nil || c.RateLimiter.Rate() != 14 {
+		t.Errorf("got RateLimiter %+v", c.RateLimiter)
+	}
+	if c.DuplicateGuard == nil || c.DuplicateGuard.window != 10*time.Minute {
+		t.Errorf("got DuplicateGuard %+v", c.DuplicateGuard)
+	}
+	if c.Logger == nil {
+		t.Error("got nil Logger")
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	os.Unsetenv("SESTEST_UNSET")
+	for _, test := range []struct{ config, err string }{
+		{`{"regoin": "us-east-1"}`, `unknown field "regoin"`},
+		{`{"region": "mars-1"}`, `unknown region "mars-1"`},
+		{`{"region": "${SESTEST_UNSET}"}`, `SESTEST_UNSET is not set`},
+		{`{"credentials": {"source": "vault"}}`, `unknown credentials source "vault"`},
+		{`{"credentials": {"source": "static", "accessKeyId": "AKID"}}`, `require accessKeyId and secretAccessKey`},
+		{`{"retry": {"maxAttempts": 0}}`, `maxAttempts must be at least 1`},
+		{`{"retry": {"maxAttempts": 2, "baseDelay": "soon"}}`, `invalid retry.baseDelay "soon"`},
+		{`{"logLevel": "loud"}`, `unknown logLevel "loud"`},
+	} {
+		_, err := ParseConfig([]byte(test.config))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got error %v, want %q", test.config, err, test.err)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "ses.json")
+	if err := ioutil.WriteFile(filename, []byte(`{"endpoint": "http://localhost:4566", "credentials": {"source": "env"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Endpoint != "http://localhost:4566" || c.Credentials != (EnvProvider{}) {
+		t.Errorf("got %+v", c)
+	}
+}
+
+func TestReload(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "ses.json")
+	write := func(config string) {
+		if err := ioutil.WriteFile(filename, []byte(config), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"rateLimit": {"rate": 14, "burst": 1}, "duplicateWindow": "10m"}`)
+	c, err := LoadConfig(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, g := c.RateLimiter, c.DuplicateGuard
+
+	write(`{"rateLimit": {"rate": 50, "burst": 5}, "duplicateWindow": "1h"}`)
+	if err := c.Reload(filename); err != nil {
+		t.Fatal(err)
+	}
+	if c.RateLimiter != l || l.Rate() != 50 || l.burst != 5 {
+		t.Errorf("got rate %g burst %g", l.Rate(), l.burst)
+	}
+	if c.DuplicateGuard != g || g.window != time.Hour {
+		t.Errorf("got window %s", g.window)
+	}
+
+	write(`{"disabled": ["marketing"]}`)
+	if err := c.Reload(filename); err == nil {
+		t.Error("enabling disabled on reload without a KindGate: want error")
+	}
+	write(`{}`)
+	if err := c.Reload(filename); err != nil {
+		t.Fatal(err)
+	}
+	if l.enabled() || g.window != 0 {
+		t.Errorf("removed settings still active: limiter enabled %v, window %s", l.enabled(), g.window)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	for i := 0; i < 10; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("disabled limiter blocked: %v", err)
+		}
+	}
+	write(`{"rateLimit": {"rate": 50, "burst": 5}, "duplicateWindow": "1h"}`)
+	if err := c.Reload(filename); err != nil || !l.enabled() || g.window != time.Hour {
+		t.Errorf("re-enabling: got %v, limiter enabled %v, window %s", err, l.enabled(), g.window)
+	}
+
+	c.DuplicateGuard = nil
+	if err := c.Reload(filename); err == nil {
+		t.Error("enabling duplicateWindow on reload: want error")
+	}
+	write(`{"rateLimit": {"rate": -1}}`)
+	if err := c.Reload(filename); err == nil {
+		t.Error("invalid file: want error")
+	}
+	if l.Rate() != 50 {
+		t.Errorf("invalid file changed rate to %g", l.Rate())
+	}
+}
+
+func TestReloadDisabled(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "ses.json")
+	write := func(config string) {
+		if err := ioutil.WriteFile(filename, []byte(config), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"disabled": []}`)
+	c, err := LoadConfig(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if !c.Gate.Enabled(ctx, "marketing") {
+		t.Error("marketing disabled by an empty list")
+	}
+
+	write(`{"disabled": ["marketing"]}`)
+	if err := c.Reload(filename); err != nil {
+		t.Fatal(err)
+	}
+	if c.Gate.Enabled(ctx, "marketing") || !c.Gate.Enabled(ctx, "receipts") {
+		t.Error("got wrong kinds disabled after reload")
+	}
+	write(`{}`)
+	if err := c.Reload(filename); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Gate.Enabled(ctx, "marketing") {
+		t.Error("marketing still disabled after removing the list")
+	}
+}
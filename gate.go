@@ -0,0 +1,151 @@
+package ses
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSendingDisabled is returned (wrapped with the disabled kind) by send calls that a Config's
+// Gate refused.
+var ErrSendingDisabled = errors.New("ses: sending disabled")
+
+// A Gate is consulted before each send to decide whether messages of a kind may be sent, so that
+// specific types of email can be switched off during an incident, for example from a feature
+// flag system. The kinds of a message are the name of its configuration set (see
+// WithConfigurationSet) and "name=value" for each of its tags (see WithTag); the message is
+// sent only if all of its kinds are enabled.
+type Gate interface {
+	Enabled(ctx context.Context, kind string) bool
+}
+
+// GateFunc adapts a function to a Gate.
+type GateFunc func(ctx context.Context, kind string) bool
+
+func (f GateFunc) Enabled(ctx context.Context, kind string) bool {
+	return f(ctx, kind)
+}
+
+// EnvGate returns a Gate that disables the kinds listed, separated by commas, in the
+// environment variable name, e.g. SES_DISABLED="marketing,campaign=spring-sale".
+func EnvGate(name string) Gate {
+	return GateFunc(func(ctx context.Context, kind string) bool {
+		for _, k := range strings.Split(os.Getenv(name), ",") {
+			if strings.TrimSpace(k) == kind {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// KindGate is a Gate that disables a set of kinds, which can be changed with SetDisabled while
+// the gate is in use. ParseConfig creates one for the "disabled" list of a configuration file,
+// so that Reload can switch kinds off and on.
+type KindGate struct {
+	mu       sync.Mutex
+	disabled map[string]bool
+}
+
+// NewKindGate returns a KindGate that disables kinds.
+func NewKindGate(kinds ...string) *KindGate {
+	g := &KindGate{}
+	g.SetDisabled(kinds...)
+	return g
+}
+
+// SetDisabled replaces the disabled kinds with kinds.
+func (g *KindGate) SetDisabled(kinds ...string) {
+	disabled := make(map[string]bool)
+	for _, k := range kinds {
+		disabled[k] = true
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.disabled = disabled
+}
+
+// Disabled returns the disabled kinds, in no particular order.
+func (g *KindGate) Disabled() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var kinds []string
+	for k := range g.disabled {
+		kinds = append(kinds, k)
+	}
+	return kinds
+}
+
+func (g *KindGate) Enabled(ctx context.Context, kind string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return !g.disabled[kind]
+}
+
+// FileGate is a Gate that disables the kinds listed in a file, one per line. Blank lines and
+// lines starting with "#" are ignored. The file is read again when it changes, so kinds can be
+// switched off without restarting; a missing file disables nothing.
+type FileGate struct {
+	Filename string
+
+	mu       sync.Mutex
+	modTime  time.Time
+	disabled map[string]bool
+}
+
+func (g *FileGate) Enabled(ctx context.Context, kind string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	fi, err := os.Stat(g.Filename)
+	if err != nil {
+		g.disabled, g.modTime = nil, time.Time{}
+		return true
+	}
+	if g.disabled == nil || !fi.ModTime().Equal(g.modTime) {
+		g.load()
+		g.modTime = fi.ModTime()
+	}
+	return !g.disabled[kind]
+}
+
+// load reads the disabled kinds from the file. g.mu must be held.
+func (g *FileGate) load() {
+	g.disabled = make(map[string]bool)
+	f, err := os.Open(g.Filename)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if line := strings.TrimSpace(s.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			g.disabled[line] = true
+		}
+	}
+}
+
+// checkGate returns an error wrapping ErrSendingDisabled if c.Gate disables any of the kinds of
+// a message sent with o.
+func (c *Config) checkGate(ctx context.Context, o *sendOptions) error {
+	if c.Gate == nil {
+		return nil
+	}
+	var kinds []string
+	if o.configurationSet != "" {
+		kinds = append(kinds, o.configurationSet)
+	}
+	for _, tag := range o.tags {
+		kinds = append(kinds, tag.Name+"="+tag.Value)
+	}
+	for _, kind := range kinds {
+		if !c.Gate.Enabled(ctx, kind) {
+			return fmt.Errorf("%w: %s", ErrSendingDisabled, kind)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGate(t *testing.T) {
+	c, _ := testServer(t, `<SendEmailResponse/>`)
+	os.Setenv("SESTEST_DISABLED", "marketing, campaign=spring")
+	defer os.Unsetenv("SESTEST_DISABLED")
+	c.Gate = EnvGate("SESTEST_DISABLED")
+
+	for _, test := range []struct {
+		opts     []SendOption
+		disabled bool
+	}{
+		{nil, false},
+		{[]SendOption{WithConfigurationSet("transactional")}, false},
+		{[]SendOption{WithConfigurationSet("marketing")}, true},
+		{[]SendOption{WithTag("campaign", "autumn")}, false},
+		{[]SendOption{WithConfigurationSet("transactional"), WithTag("campaign", "spring")}, true},
+	} {
+		_, err := c.SendEmail("a@example.com", "b@example.com", "s", "b", test.opts...)
+		if disabled := errors.Is(err, ErrSendingDisabled); disabled != test.disabled {
+			t.Errorf("%d options: got error %v, want disabled %v", len(test.opts), err, test.disabled)
+		}
+	}
+}
+
+func TestFileGate(t *testing.T) {
+	g := &FileGate{Filename: filepath.Join(t.TempDir(), "disabled")}
+	ctx := context.Background()
+	if !g.Enabled(ctx, "marketing") {
+		t.Error("missing file: want enabled")
+	}
+
+	if err := ioutil.WriteFile(g.Filename, []byte("# incident 42\nmarketing\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if g.Enabled(ctx, "marketing") || !g.Enabled(ctx, "transactional") {
+		t.Error("want marketing disabled and transactional enabled")
+	}
+
+	if err := ioutil.WriteFile(g.Filename, []byte(""), 0600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Second)
+	os.Chtimes(g.Filename, later, later)
+	if !g.Enabled(ctx, "marketing") {
+		t.Error("after the file changed: want marketing enabled")
+	}
+}
@@ -0,0 +1,145 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrSendInProgress is returned by send calls whose idempotency key is reserved by another send
+// that hasn't finished. The call may be retried once that send is done.
+var ErrSendInProgress = errors.New("ses: a send with the same idempotency key is in progress")
+
+// An IdempotencyStore records the message IDs of sends made with WithIdempotencyKey, so that a
+// job that is retried after a crash doesn't send its message again. Implementations backed by
+// a shared database make this work across processes.
+//
+// Before a send, its key is reserved, so that concurrent sends with the same key don't both
+// send the message. Implementations shared by several processes should let reservations
+// expire, since a process that crashes during a send leaves its reservation behind.
+type IdempotencyStore interface {
+	// Reserve atomically reserves key if nothing is recorded for it, and reports whether it
+	// did. Otherwise it returns the message ID recorded for key, or "" if key is reserved by a
+	// send that hasn't finished.
+	Reserve(ctx context.Context, key string) (messageID string, reserved bool, err error)
+
+	// Release removes the reservation of key, after the send failed.
+	Release(ctx context.Context, key string) error
+
+	// Put records that the message with the given key was sent as messageID, replacing its
+	// reservation.
+	Put(ctx context.Context, key, messageID string) error
+}
+
+// WithIdempotencyKey identifies the message by key, which the caller chooses to be the same
+// every time the same message is sent (for example, a job ID). If the Config has an
+// IdempotencyStore that has a message ID recorded for key, the message is not sent again: the
+// call succeeds, returning a response with the recorded message ID. If another send with key
+// is in progress, the call fails with ErrSendInProgress. Otherwise the message is sent and its
+// message ID recorded. Without an IdempotencyStore, the key is ignored.
+func WithIdempotencyKey(key string) SendOption {
+	return func(o *sendOptions) { o.idempotencyKey = key }
+}
+
+// MemoryIdempotencyStore is an IdempotencyStore that keeps message IDs in memory. It is safe for
+// concurrent use. It protects against retries within one process only, and grows without bound,
+// so it is mainly useful for tests and short-lived programs.
+type MemoryIdempotencyStore struct {
+	mu  sync.Mutex
+	ids map[string]string // "" for a reserved key
+}
+
+// NewMemoryIdempotencyStore returns an empty MemoryIdempotencyStore.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{ids: make(map[string]string)}
+}
+
+// Get returns the message ID recorded for key, and whether there is one.
+func (s *MemoryIdempotencyStore) Get(ctx context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.ids[key]
+	return id, id != "", nil
+}
+
+func (s *MemoryIdempotencyStore) Reserve(ctx context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.ids[key]; ok {
+		return id, false, nil
+	}
+	s.ids[key] = ""
+	return "", true, nil
+}
+
+func (s *MemoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ids[key] == "" {
+		delete(s.ids, key)
+	}
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Put(ctx context.Context, key, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ids[key] = messageID
+	return nil
+}
+
+// sentBefore reserves o's idempotency key in c.IdempotencyStore, or returns the message ID
+// recorded for it and true if the message was already sent. If the send that follows fails,
+// the caller must call releaseKey.
+func (c *Config) sentBefore(ctx context.Context, o *sendOptions) (string, bool, error) {
+	if c.IdempotencyStore == nil || o.idempotencyKey == "" {
+		return "", false, nil
+	}
+	id, reserved, err := c.IdempotencyStore.Reserve(ctx, o.idempotencyKey)
+	switch {
+	case err != nil:
+		return "", false, err
+	case reserved:
+		return "", false, nil
+	case id == "":
+		return "", false, ErrSendInProgress
+	}
+	return id, true, nil
+}
+
+// releaseKey releases the reservation of o's idempotency key made by sentBefore, after the send
+// failed, so that it may be retried.
+func (c *Config) releaseKey(ctx context.Context, o *sendOptions) {
+	if c.IdempotencyStore == nil || o.idempotencyKey == "" {
+		return
+	}
+	if err := c.IdempotencyStore.Release(ctx, o.idempotencyKey); err != nil {
+		c.log(LogError, "releasing idempotency key failed", "key", o.idempotencyKey, "error", err)
+	}
+}
+
+// recordSent records messageID in c.IdempotencyStore for o's idempotency key. The message has
+// already been sent, so a failure is logged rather than returned, where it would invite the
+// caller to send the message again. If the response had no message ID, there is nothing to
+// record, so the key is released instead.
+func (c *Config) recordSent(ctx context.Context, o *sendOptions, messageID string) {
+	if c.IdempotencyStore == nil || o.idempotencyKey == "" {
+		return
+	}
+	if messageID == "" {
+		c.log(LogWarn, "no message ID to record for idempotency key", "key", o.idempotencyKey)
+		c.releaseKey(ctx, o)
+		return
+	}
+	if err := c.IdempotencyStore.Put(ctx, o.idempotencyKey, messageID); err != nil {
+		c.log(LogError, "recording idempotency key failed", "key", o.idempotencyKey, "message_id", messageID, "error", err)
+	}
+}
+
+// sentResponse returns the response to return for a send with the given action that was
+// already made as messageID.
+func sentResponse(action, messageID string) string {
+	return fmt.Sprintf(`<%sResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/"><%sResult><MessageId>%s</MessageId></%sResult></%sResponse>`,
+		action, action, messageID, action, action)
+}
@@ -0,0 +1,102 @@
+package ses
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	sends := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sends++
+		fmt.Fprintf(w, "<SendEmailResponse><SendEmailResult><MessageId>id-%d</MessageId></SendEmailResult></SendEmailResponse>", sends)
+	}))
+	defer srv.Close()
+	c := &Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET", IdempotencyStore: NewMemoryIdempotencyStore()}
+
+	res, err := c.SendEmail("a@example.com", "b@example.com", "s", "b", WithIdempotencyKey("job-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res2, err := c.SendEmail("a@example.com", "b@example.com", "s", "b", WithIdempotencyKey("job-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sends != 1 {
+		t.Errorf("got %d sends, want 1", sends)
+	}
+	if messageID(res) != "id-1" || messageID(res2) != "id-1" {
+		t.Errorf("got message IDs %q and %q, want id-1", messageID(res), messageID(res2))
+	}
+
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b", WithIdempotencyKey("job-2")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if sends != 3 {
+		t.Errorf("got %d sends, want 3", sends)
+	}
+	if id, ok, _ := c.IdempotencyStore.(*MemoryIdempotencyStore).Get(context.Background(), "job-2"); !ok || id != "id-2" {
+		t.Errorf("got recorded ID %q, %v", id, ok)
+	}
+}
+
+func TestIdempotencyKeyConcurrent(t *testing.T) {
+	started, release := make(chan bool), make(chan struct{})
+	first := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if first {
+			first = false
+			started <- true
+			<-release
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("<SendEmailResponse><SendEmailResult><MessageId>id-2</MessageId></SendEmailResult></SendEmailResponse>"))
+	}))
+	defer srv.Close()
+	c := &Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET", IdempotencyStore: NewMemoryIdempotencyStore()}
+
+	// While the first send is in progress, a second send with the same key fails.
+	done := make(chan error)
+	go func() {
+		_, err := c.SendEmail("a@example.com", "b@example.com", "s", "b", WithIdempotencyKey("job-1"))
+		done <- err
+	}()
+	<-started
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b", WithIdempotencyKey("job-1")); err != ErrSendInProgress {
+		t.Errorf("got %v, want ErrSendInProgress", err)
+	}
+
+	// The first send fails, which releases the key for a retry.
+	close(release)
+	if err := <-done; err == nil {
+		t.Fatal("want error from the first send")
+	}
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b", WithIdempotencyKey("job-1")); err != nil {
+		t.Fatal(err)
+	}
+	if id, ok, _ := c.IdempotencyStore.(*MemoryIdempotencyStore).Get(context.Background(), "job-1"); !ok || id != "id-2" {
+		t.Errorf("got recorded ID %q, %v", id, ok)
+	}
+}
+
+func TestIdempotencyKeyNoMessageID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<SendEmailResponse/>"))
+	}))
+	defer srv.Close()
+	c := &Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET", IdempotencyStore: NewMemoryIdempotencyStore()}
+
+	// Without a message ID to record, the key is released rather than left reserved.
+	for i := 0; i < 2; i++ {
+		if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b", WithIdempotencyKey("job-1")); err != nil {
+			t.Fatalf("send %d: %v", i+1, err)
+		}
+	}
+}
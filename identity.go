@@ -0,0 +1,173 @@
+package ses
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// Identity types accepted by ListIdentities.
+const (
+	IdentityTypeEmailAddress = "EmailAddress"
+	IdentityTypeDomain       = "Domain"
+)
+
+// VerifyEmailIdentity adds an email address to the list of identities for the account and
+// sends it a verification email.
+func (c *Config) VerifyEmailIdentity(email string) error {
+	return c.VerifyEmailIdentityContext(context.Background(), email)
+}
+
+// VerifyEmailIdentityContext is like VerifyEmailIdentity but uses ctx for the request and any
+// retries.
+func (c *Config) VerifyEmailIdentityContext(ctx context.Context, email string) error {
+	data := make(url.Values)
+	data.Add("Action", "VerifyEmailIdentity")
+	data.Add("EmailAddress", email)
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+type VerifyDomainIdentityResult struct {
+	VerificationToken string
+}
+
+type VerifyDomainIdentityResponse struct {
+	VerifyDomainIdentityResult VerifyDomainIdentityResult
+}
+
+// VerifyDomainIdentity adds a domain to the list of identities for the account and returns the
+// token to publish in a TXT record at _amazonses.<domain> to prove ownership of it.
+func (c *Config) VerifyDomainIdentity(domain string) (string, error) {
+	return c.VerifyDomainIdentityContext(context.Background(), domain)
+}
+
+// VerifyDomainIdentityContext is like VerifyDomainIdentity but uses ctx for the request and any
+// retries.
+func (c *Config) VerifyDomainIdentityContext(ctx context.Context, domain string) (string, error) {
+	data := make(url.Values)
+	data.Add("Action", "VerifyDomainIdentity")
+	data.Add("Domain", domain)
+
+	res := VerifyDomainIdentityResponse{}
+	err := c.call(ctx, "POST", data, &res)
+	return res.VerifyDomainIdentityResult.VerificationToken, err
+}
+
+// DeleteIdentity deletes an email address or domain from the list of identities for the
+// account.
+func (c *Config) DeleteIdentity(identity string) error {
+	return c.DeleteIdentityContext(context.Background(), identity)
+}
+
+// DeleteIdentityContext is like DeleteIdentity but uses ctx for the request and any retries.
+func (c *Config) DeleteIdentityContext(ctx context.Context, identity string) error {
+	data := make(url.Values)
+	data.Add("Action", "DeleteIdentity")
+	data.Add("Identity", identity)
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+type ListIdentitiesResult struct {
+	Identities []string `xml:"Identities>member"`
+
+	// NextToken is passed to ListIdentities to fetch the next page. It is empty on the last
+	// page.
+	NextToken string
+}
+
+type ListIdentitiesResponse struct {
+	ListIdentitiesResult ListIdentitiesResult
+}
+
+// ListIdentities returns a page of the identities for the account. If identityType is
+// non-empty, only identities of that type (IdentityTypeEmailAddress or IdentityTypeDomain) are
+// returned. maxItems limits the page size (0 means the SES default), and nextToken is the
+// NextToken from the previous page, or empty for the first page.
+func (c *Config) ListIdentities(identityType string, maxItems int, nextToken string) (ListIdentitiesResult, error) {
+	return c.ListIdentitiesContext(context.Background(), identityType, maxItems, nextToken)
+}
+
+// ListIdentitiesContext is like ListIdentities but uses ctx for the request and any retries.
+func (c *Config) ListIdentitiesContext(ctx context.Context, identityType string, maxItems int, nextToken string) (ListIdentitiesResult, error) {
+	data := make(url.Values)
+	data.Add("Action", "ListIdentities")
+	if identityType != "" {
+		data.Add("IdentityType", identityType)
+	}
+	if maxItems > 0 {
+		data.Add("MaxItems", strconv.Itoa(maxItems))
+	}
+	if nextToken != "" {
+		data.Add("NextToken", nextToken)
+	}
+
+	res := ListIdentitiesResponse{}
+	err := c.call(ctx, "GET", data, &res)
+	return res.ListIdentitiesResult, err
+}
+
+// Identity verification statuses.
+const (
+	VerificationStatusPending          = "Pending"
+	VerificationStatusSuccess          = "Success"
+	VerificationStatusFailed           = "Failed"
+	VerificationStatusTemporaryFailure = "TemporaryFailure"
+	VerificationStatusNotStarted       = "NotStarted"
+)
+
+type IdentityVerificationAttributes struct {
+	VerificationStatus string
+
+	// VerificationToken is set for domain identities only.
+	VerificationToken string
+}
+
+type GetIdentityVerificationAttributesResult struct {
+	VerificationAttributes []struct {
+		Key   string                         `xml:"key"`
+		Value IdentityVerificationAttributes `xml:"value"`
+	} `xml:"VerificationAttributes>entry"`
+}
+
+type GetIdentityVerificationAttributesResponse struct {
+	GetIdentityVerificationAttributesResult GetIdentityVerificationAttributesResult
+}
+
+// maxIdentitiesPerRequest is the number of identities SES accepts in one
+// GetIdentityVerificationAttributes request.
+const maxIdentitiesPerRequest = 100
+
+// GetIdentityVerificationAttributes returns the verification status (and, for domains, the
+// verification token) of each of the given identities, keyed by identity. Any number of
+// identities may be given; they are requested in batches of 100.
+func (c *Config) GetIdentityVerificationAttributes(identities ...string) (map[string]IdentityVerificationAttributes, error) {
+	return c.GetIdentityVerificationAttributesContext(context.Background(), identities...)
+}
+
+// GetIdentityVerificationAttributesContext is like GetIdentityVerificationAttributes but uses
+// ctx for the request and any retries.
+func (c *Config) GetIdentityVerificationAttributesContext(ctx context.Context, identities ...string) (map[string]IdentityVerificationAttributes, error) {
+	attrs := make(map[string]IdentityVerificationAttributes)
+	for len(identities) > 0 {
+		batch := identities
+		if len(batch) > maxIdentitiesPerRequest {
+			batch = batch[:maxIdentitiesPerRequest]
+		}
+		identities = identities[len(batch):]
+
+		data := make(url.Values)
+		data.Add("Action", "GetIdentityVerificationAttributes")
+		addMembers(data, "Identities", batch)
+
+		res := GetIdentityVerificationAttributesResponse{}
+		if err := c.call(ctx, "GET", data, &res); err != nil {
+			return nil, err
+		}
+		for _, e := range res.GetIdentityVerificationAttributesResult.VerificationAttributes {
+			attrs[e.Key] = e.Value
+		}
+	}
+	return attrs, nil
+}
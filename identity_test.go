@@ -0,0 +1,133 @@
+package ses
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVerifyDomainIdentity(t *testing.T) {
+	c, form := testServer(t, `<VerifyDomainIdentityResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <VerifyDomainIdentityResult>
+    <VerificationToken>QTKknzFg2J4ygwa+XvHAxUl1hyHoY0gVfZdfjIedHZ0=</VerificationToken>
+  </VerifyDomainIdentityResult>
+  <ResponseMetadata>
+    <RequestId>94f6368e-9bf2-11e1-8ee7-c98a0037a2b6</RequestId>
+  </ResponseMetadata>
+</VerifyDomainIdentityResponse>`)
+
+	token, err := c.VerifyDomainIdentity("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "QTKknzFg2J4ygwa+XvHAxUl1hyHoY0gVfZdfjIedHZ0="; token != want {
+		t.Errorf("got token %q, want %q", token, want)
+	}
+	checkForm(t, *form, map[string]string{"Action": "VerifyDomainIdentity", "Domain": "example.com", "AWSAccessKeyId": "AKID"})
+}
+
+func TestListIdentities(t *testing.T) {
+	c, form := testServer(t, `<ListIdentitiesResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <ListIdentitiesResult>
+    <Identities>
+      <member>example.com</member>
+      <member>user@example.com</member>
+    </Identities>
+    <NextToken>abc</NextToken>
+  </ListIdentitiesResult>
+</ListIdentitiesResponse>`)
+
+	res, err := c.ListIdentities(IdentityTypeDomain, 2, "xyz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"example.com", "user@example.com"}; !reflect.DeepEqual(res.Identities, want) {
+		t.Errorf("got identities %v, want %v", res.Identities, want)
+	}
+	if res.NextToken != "abc" {
+		t.Errorf("got NextToken %q, want %q", res.NextToken, "abc")
+	}
+	checkForm(t, *form, map[string]string{"Action": "ListIdentities", "IdentityType": "Domain", "MaxItems": "2", "NextToken": "xyz"})
+}
+
+func TestGetIdentityVerificationAttributes(t *testing.T) {
+	c, form := testServer(t, `<GetIdentityVerificationAttributesResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <GetIdentityVerificationAttributesResult>
+    <VerificationAttributes>
+      <entry>
+        <key>example.com</key>
+        <value>
+          <VerificationToken>token</VerificationToken>
+          <VerificationStatus>Pending</VerificationStatus>
+        </value>
+      </entry>
+      <entry>
+        <key>user@example.com</key>
+        <value>
+          <VerificationStatus>Success</VerificationStatus>
+        </value>
+      </entry>
+    </VerificationAttributes>
+  </GetIdentityVerificationAttributesResult>
+</GetIdentityVerificationAttributesResponse>`)
+
+	attrs, err := c.GetIdentityVerificationAttributes("example.com", "user@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]IdentityVerificationAttributes{
+		"example.com":      {VerificationStatus: VerificationStatusPending, VerificationToken: "token"},
+		"user@example.com": {VerificationStatus: VerificationStatusSuccess},
+	}
+	if !reflect.DeepEqual(attrs, want) {
+		t.Errorf("got %+v, want %+v", attrs, want)
+	}
+	checkForm(t, *form, map[string]string{"Identities.member.1": "example.com", "Identities.member.2": "user@example.com"})
+}
+
+func TestGetIdentityVerificationAttributesBatches(t *testing.T) {
+	var batches []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		var b strings.Builder
+		n := 0
+		for ; r.Form.Get(fmt.Sprintf("Identities.member.%d", n+1)) != ""; n++ {
+			fmt.Fprintf(&b, "<entry><key>%s</key><value><VerificationStatus>Success</VerificationStatus></value></entry>", r.Form.Get(fmt.Sprintf("Identities.member.%d", n+1)))
+		}
+		batches = append(batches, n)
+		fmt.Fprintf(w, "<GetIdentityVerificationAttributesResponse><GetIdentityVerificationAttributesResult><VerificationAttributes>%s</VerificationAttributes></GetIdentityVerificationAttributesResult></GetIdentityVerificationAttributesResponse>", b.String())
+	}))
+	defer srv.Close()
+
+	identities := make([]string, 250)
+	for i := range identities {
+		identities[i] = fmt.Sprintf("user%d@example.com", i)
+	}
+	c := Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET"}
+	attrs, err := c.GetIdentityVerificationAttributes(identities...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attrs) != 250 || attrs["user249@example.com"].VerificationStatus != VerificationStatusSuccess {
+		t.Errorf("got %d attributes", len(attrs))
+	}
+	if !reflect.DeepEqual(batches, []int{100, 100, 50}) {
+		t.Errorf("got batches %v, want [100 100 50]", batches)
+	}
+}
+
+func TestVerifyEmailIdentityAndDeleteIdentity(t *testing.T) {
+	c, form := testServer(t, `<VerifyEmailIdentityResponse/>`)
+	if err := c.VerifyEmailIdentity("user@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"Action": "VerifyEmailIdentity", "EmailAddress": "user@example.com"})
+
+	if err := c.DeleteIdentity("user@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"Action": "DeleteIdentity", "Identity": "user@example.com"})
+}
@@ -0,0 +1,101 @@
+package ses
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// Notification types accepted by SetIdentityNotificationTopic.
+const (
+	NotificationTypeBounce    = "Bounce"
+	NotificationTypeComplaint = "Complaint"
+	NotificationTypeDelivery  = "Delivery"
+)
+
+// SetIdentityNotificationTopic sets the SNS topic that notifications of notificationType (one
+// of the NotificationType constants) for email sent from identity are published to. If
+// snsTopic is empty, publishing is disabled.
+func (c *Config) SetIdentityNotificationTopic(identity, notificationType, snsTopic string) error {
+	return c.SetIdentityNotificationTopicContext(context.Background(), identity, notificationType, snsTopic)
+}
+
+// SetIdentityNotificationTopicContext is like SetIdentityNotificationTopic but uses ctx for the
+// request and any retries.
+func (c *Config) SetIdentityNotificationTopicContext(ctx context.Context, identity, notificationType, snsTopic string) error {
+	data := make(url.Values)
+	data.Add("Action", "SetIdentityNotificationTopic")
+	data.Add("Identity", identity)
+	data.Add("NotificationType", notificationType)
+	if snsTopic != "" {
+		data.Add("SnsTopic", snsTopic)
+	}
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+// SetIdentityFeedbackForwardingEnabled enables or disables forwarding of bounces and
+// complaints for identity by email. Forwarding can only be disabled when both bounce and
+// complaint notifications are published to SNS topics.
+func (c *Config) SetIdentityFeedbackForwardingEnabled(identity string, enabled bool) error {
+	return c.SetIdentityFeedbackForwardingEnabledContext(context.Background(), identity, enabled)
+}
+
+// SetIdentityFeedbackForwardingEnabledContext is like SetIdentityFeedbackForwardingEnabled but
+// uses ctx for the request and any retries.
+func (c *Config) SetIdentityFeedbackForwardingEnabledContext(ctx context.Context, identity string, enabled bool) error {
+	data := make(url.Values)
+	data.Add("Action", "SetIdentityFeedbackForwardingEnabled")
+	data.Add("Identity", identity)
+	data.Add("ForwardingEnabled", strconv.FormatBool(enabled))
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+type IdentityNotificationAttributes struct {
+	BounceTopic    string
+	ComplaintTopic string
+	DeliveryTopic  string
+
+	ForwardingEnabled bool
+
+	HeadersInBounceNotificationsEnabled    bool
+	HeadersInComplaintNotificationsEnabled bool
+	HeadersInDeliveryNotificationsEnabled  bool
+}
+
+type GetIdentityNotificationAttributesResult struct {
+	NotificationAttributes []struct {
+		Key   string                         `xml:"key"`
+		Value IdentityNotificationAttributes `xml:"value"`
+	} `xml:"NotificationAttributes>entry"`
+}
+
+type GetIdentityNotificationAttributesResponse struct {
+	GetIdentityNotificationAttributesResult GetIdentityNotificationAttributesResult
+}
+
+// GetIdentityNotificationAttributes returns the notification attributes of each of the given
+// identities, keyed by identity.
+func (c *Config) GetIdentityNotificationAttributes(identities ...string) (map[string]IdentityNotificationAttributes, error) {
+	return c.GetIdentityNotificationAttributesContext(context.Background(), identities...)
+}
+
+// GetIdentityNotificationAttributesContext is like GetIdentityNotificationAttributes but uses
+// ctx for the request and any retries.
+func (c *Config) GetIdentityNotificationAttributesContext(ctx context.Context, identities ...string) (map[string]IdentityNotificationAttributes, error) {
+	data := make(url.Values)
+	data.Add("Action", "GetIdentityNotificationAttributes")
+	addMembers(data, "Identities", identities)
+
+	res := GetIdentityNotificationAttributesResponse{}
+	if err := c.call(ctx, "GET", data, &res); err != nil {
+		return nil, err
+	}
+
+	attrs := make(map[string]IdentityNotificationAttributes)
+	for _, e := range res.GetIdentityNotificationAttributesResult.NotificationAttributes {
+		attrs[e.Key] = e.Value
+	}
+	return attrs, nil
+}
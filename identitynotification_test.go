@@ -0,0 +1,65 @@
+package ses
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetIdentityNotificationTopic(t *testing.T) {
+	c, form := testServer(t, `<SetIdentityNotificationTopicResponse/>`)
+	if err := c.SetIdentityNotificationTopic("example.com", NotificationTypeBounce, "arn:aws:sns:us-east-1:123456789012:bounces"); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{
+		"Action":           "SetIdentityNotificationTopic",
+		"Identity":         "example.com",
+		"NotificationType": "Bounce",
+		"SnsTopic":         "arn:aws:sns:us-east-1:123456789012:bounces",
+	})
+
+	if err := c.SetIdentityNotificationTopic("example.com", NotificationTypeDelivery, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := (*form)["SnsTopic"]; ok {
+		t.Error("got SnsTopic when disabling notifications")
+	}
+}
+
+func TestSetIdentityFeedbackForwardingEnabled(t *testing.T) {
+	c, form := testServer(t, `<SetIdentityFeedbackForwardingEnabledResponse/>`)
+	if err := c.SetIdentityFeedbackForwardingEnabled("example.com", false); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"Action": "SetIdentityFeedbackForwardingEnabled", "ForwardingEnabled": "false"})
+}
+
+func TestGetIdentityNotificationAttributes(t *testing.T) {
+	c, _ := testServer(t, `<GetIdentityNotificationAttributesResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <GetIdentityNotificationAttributesResult>
+    <NotificationAttributes>
+      <entry>
+        <key>example.com</key>
+        <value>
+          <ForwardingEnabled>false</ForwardingEnabled>
+          <HeadersInBounceNotificationsEnabled>true</HeadersInBounceNotificationsEnabled>
+          <BounceTopic>arn:aws:sns:us-east-1:123456789012:bounces</BounceTopic>
+          <ComplaintTopic>arn:aws:sns:us-east-1:123456789012:complaints</ComplaintTopic>
+        </value>
+      </entry>
+    </NotificationAttributes>
+  </GetIdentityNotificationAttributesResult>
+</GetIdentityNotificationAttributesResponse>`)
+
+	attrs, err := c.GetIdentityNotificationAttributes("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]IdentityNotificationAttributes{"example.com": {
+		BounceTopic:                         "arn:aws:sns:us-east-1:123456789012:bounces",
+		ComplaintTopic:                      "arn:aws:sns:us-east-1:123456789012:complaints",
+		HeadersInBounceNotificationsEnabled: true,
+	}}
+	if !reflect.DeepEqual(attrs, want) {
+		t.Errorf("got %+v, want %+v", attrs, want)
+	}
+}
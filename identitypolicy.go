@@ -0,0 +1,103 @@
+package ses
+
+import (
+	"context"
+	"net/url"
+)
+
+// PutIdentityPolicy creates or replaces the sending authorization policy named name for an
+// identity. policy is a JSON policy document that grants other accounts permission to send
+// as the identity (see WithSourceArn).
+func (c *Config) PutIdentityPolicy(identity, name, policy string) error {
+	return c.PutIdentityPolicyContext(context.Background(), identity, name, policy)
+}
+
+// PutIdentityPolicyContext is like PutIdentityPolicy but uses ctx for the request and any
+// retries.
+func (c *Config) PutIdentityPolicyContext(ctx context.Context, identity, name, policy string) error {
+	data := make(url.Values)
+	data.Add("Action", "PutIdentityPolicy")
+	data.Add("Identity", identity)
+	data.Add("PolicyName", name)
+	data.Add("Policy", policy)
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+type GetIdentityPoliciesResult struct {
+	Policies []struct {
+		Key   string `xml:"key"`
+		Value string `xml:"value"`
+	} `xml:"Policies>entry"`
+}
+
+type GetIdentityPoliciesResponse struct {
+	GetIdentityPoliciesResult GetIdentityPoliciesResult
+}
+
+// GetIdentityPolicies returns the named sending authorization policies of an identity, keyed by
+// policy name.
+func (c *Config) GetIdentityPolicies(identity string, names ...string) (map[string]string, error) {
+	return c.GetIdentityPoliciesContext(context.Background(), identity, names...)
+}
+
+// GetIdentityPoliciesContext is like GetIdentityPolicies but uses ctx for the request and any
+// retries.
+func (c *Config) GetIdentityPoliciesContext(ctx context.Context, identity string, names ...string) (map[string]string, error) {
+	data := make(url.Values)
+	data.Add("Action", "GetIdentityPolicies")
+	data.Add("Identity", identity)
+	addMembers(data, "PolicyNames", names)
+
+	res := GetIdentityPoliciesResponse{}
+	if err := c.call(ctx, "GET", data, &res); err != nil {
+		return nil, err
+	}
+
+	policies := make(map[string]string)
+	for _, e := range res.GetIdentityPoliciesResult.Policies {
+		policies[e.Key] = e.Value
+	}
+	return policies, nil
+}
+
+type ListIdentityPoliciesResult struct {
+	PolicyNames []string `xml:"PolicyNames>member"`
+}
+
+type ListIdentityPoliciesResponse struct {
+	ListIdentityPoliciesResult ListIdentityPoliciesResult
+}
+
+// ListIdentityPolicies returns the names of the sending authorization policies of an identity.
+func (c *Config) ListIdentityPolicies(identity string) ([]string, error) {
+	return c.ListIdentityPoliciesContext(context.Background(), identity)
+}
+
+// ListIdentityPoliciesContext is like ListIdentityPolicies but uses ctx for the request and any
+// retries.
+func (c *Config) ListIdentityPoliciesContext(ctx context.Context, identity string) ([]string, error) {
+	data := make(url.Values)
+	data.Add("Action", "ListIdentityPolicies")
+	data.Add("Identity", identity)
+
+	res := ListIdentityPoliciesResponse{}
+	err := c.call(ctx, "GET", data, &res)
+	return res.ListIdentityPoliciesResult.PolicyNames, err
+}
+
+// DeleteIdentityPolicy deletes the named sending authorization policy of an identity.
+func (c *Config) DeleteIdentityPolicy(identity, name string) error {
+	return c.DeleteIdentityPolicyContext(context.Background(), identity, name)
+}
+
+// DeleteIdentityPolicyContext is like DeleteIdentityPolicy but uses ctx for the request and any
+// retries.
+func (c *Config) DeleteIdentityPolicyContext(ctx context.Context, identity, name string) error {
+	data := make(url.Values)
+	data.Add("Action", "DeleteIdentityPolicy")
+	data.Add("Identity", identity)
+	data.Add("PolicyName", name)
+
+	return c.call(ctx, "POST", data, nil)
+}
@@ -0,0 +1,50 @@
+package ses
+
+import "testing"
+
+func TestGetIdentityPolicies(t *testing.T) {
+	c, form := testServer(t, `<GetIdentityPoliciesResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <GetIdentityPoliciesResult>
+    <Policies>
+      <entry>
+        <key>central-sender</key>
+        <value>{"Version":"2012-10-17","Statement":[]}</value>
+      </entry>
+    </Policies>
+  </GetIdentityPoliciesResult>
+</GetIdentityPoliciesResponse>`)
+	policies, err := c.GetIdentityPolicies("example.com", "central-sender")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"Action": "GetIdentityPolicies", "Identity": "example.com", "PolicyNames.member.1": "central-sender"})
+	if got := policies["central-sender"]; got != `{"Version":"2012-10-17","Statement":[]}` {
+		t.Errorf("got policy %q", got)
+	}
+}
+
+func TestListIdentityPolicies(t *testing.T) {
+	c, _ := testServer(t, `<ListIdentityPoliciesResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <ListIdentityPoliciesResult>
+    <PolicyNames>
+      <member>central-sender</member>
+      <member>billing</member>
+    </PolicyNames>
+  </ListIdentityPoliciesResult>
+</ListIdentityPoliciesResponse>`)
+	names, err := c.ListIdentityPolicies("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[1] != "billing" {
+		t.Errorf("got %v", names)
+	}
+}
+
+func TestPutIdentityPolicy(t *testing.T) {
+	c, form := testServer(t, `<PutIdentityPolicyResponse/>`)
+	if err := c.PutIdentityPolicy("example.com", "central-sender", `{}`); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"Action": "PutIdentityPolicy", "Identity": "example.com", "PolicyName": "central-sender", "Policy": "{}"})
+}
@@ -0,0 +1,318 @@
+// Package listcheck checks large lists of email addresses before they are imported or sent a
+// campaign: each address is checked for valid syntax, for a domain that accepts mail, and
+// against the account's suppression list.
+package listcheck
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+// Statuses of a Result.
+const (
+	StatusValid      = "valid"
+	StatusInvalid    = "invalid"    // the address is malformed
+	StatusNoMail     = "no_mail"    // the domain doesn't exist or accepts no mail
+	StatusSuppressed = "suppressed" // the address is on the account suppression list
+	StatusUnknown    = "unknown"    // the domain couldn't be looked up
+)
+
+// Result is the outcome of checking an address.
+type Result struct {
+	Address string
+	Status  string
+
+	// Reason explains a status other than StatusValid.
+	Reason string
+}
+
+// A Source yields the addresses to check. Next returns io.EOF after the last address.
+type Source interface {
+	Next() (string, error)
+}
+
+// LineSource returns a Source that reads one address per line from r, ignoring blank lines.
+func LineSource(r io.Reader) Source {
+	s := bufio.NewScanner(r)
+	return sourceFunc(func() (string, error) {
+		for s.Scan() {
+			if line := strings.TrimSpace(s.Text()); line != "" {
+				return line, nil
+			}
+		}
+		if err := s.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	})
+}
+
+type sourceFunc func() (string, error)
+
+func (f sourceFunc) Next() (string, error) { return f() }
+
+// A Writer receives the results of a Job. Write is not called concurrently.
+type Writer interface {
+	Write(Result) error
+}
+
+// CSVWriter writes results as CSV records of address, status and reason. Flush must be called
+// after the job.
+type CSVWriter struct {
+	w *csv.Writer
+}
+
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{csv.NewWriter(w)}
+}
+
+func (w *CSVWriter) Write(r Result) error {
+	return w.w.Write([]string{r.Address, r.Status, r.Reason})
+}
+
+// Flush writes any buffered records and returns any error that occurred while writing.
+func (w *CSVWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// Resolver looks up DNS records. *net.Resolver implements it.
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Progress reports the progress of a Job.
+type Progress struct {
+	Checked int64
+
+	// Counts is the number of results with each status.
+	Counts  map[string]int64
+	Elapsed time.Duration
+}
+
+// Job checks the addresses from a Source concurrently. Domains are looked up once per job.
+type Job struct {
+	// SES, if non-nil, is used to fetch the account suppression list (once, at the start of the
+	// job), and addresses on it get StatusSuppressed.
+	SES *ses.Config
+
+	// Resolver looks up the domains of addresses. If nil, net.DefaultResolver is used.
+	Resolver Resolver
+
+	// SkipDNS disables the domain lookups.
+	SkipDNS bool
+
+	// Workers is the number of addresses checked concurrently. If less than 1, 16 is used.
+	Workers int
+
+	// OnProgress, if non-nil, is called every ProgressInterval (default 1 second) while the job
+	// runs, and once at the end.
+	OnProgress       func(Progress)
+	ProgressInterval time.Duration
+
+	suppressed map[string]string
+	domains    sync.Map // domain -> *domainResult
+}
+
+type domainResult struct {
+	once   sync.Once
+	status string
+	reason string
+}
+
+// Run checks every address from src and writes its result to w, in no particular order. It
+// returns the final progress, and the first error from src, w or ctx.
+func (j *Job) Run(ctx context.Context, src Source, w Writer) (Progress, error) {
+	start := time.Now()
+	if j.SES != nil {
+		if err := j.loadSuppressed(); err != nil {
+			return Progress{}, err
+		}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := j.Workers
+	if workers < 1 {
+		workers = 16
+	}
+	addrs := make(chan string, workers)
+	results := make(chan Result, workers)
+
+	var errOnce sync.Once
+	var firstErr error
+	fail := func(err error) {
+		errOnce.Do(func() { firstErr = err; cancel() })
+	}
+
+	go func() {
+		defer close(addrs)
+		for {
+			addr, err := src.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				fail(err)
+				return
+			}
+			select {
+			case addrs <- addr:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for addr := range addrs {
+				select {
+				case results <- j.check(ctx, addr):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() { wg.Wait(); close(results) }()
+
+	var checked int64
+	counts := make(map[string]int64)
+	var mu sync.Mutex
+	progress := func() Progress {
+		mu.Lock()
+		defer mu.Unlock()
+		p := Progress{Checked: atomic.LoadInt64(&checked), Counts: make(map[string]int64), Elapsed: time.Since(start)}
+		for k, v := range counts {
+			p.Counts[k] = v
+		}
+		return p
+	}
+	stopProgress := func() {}
+	if j.OnProgress != nil {
+		interval := j.ProgressInterval
+		if interval == 0 {
+			interval = time.Second
+		}
+		t := time.NewTicker(interval)
+		done, stopped := make(chan struct{}), make(chan struct{})
+		go func() {
+			defer close(stopped)
+			for {
+				select {
+				case <-t.C:
+					j.OnProgress(progress())
+				case <-done:
+					return
+				}
+			}
+		}()
+		stopProgress = func() { t.Stop(); close(done); <-stopped }
+	}
+
+	for r := range results {
+		if err := w.Write(r); err != nil {
+			fail(err)
+			break
+		}
+		mu.Lock()
+		counts[r.Status]++
+		mu.Unlock()
+		atomic.AddInt64(&checked, 1)
+	}
+	for range results {
+		// Drain after a write error, so the workers exit.
+	}
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+	stopProgress()
+	p := progress()
+	if j.OnProgress != nil {
+		j.OnProgress(p)
+	}
+	return p, firstErr
+}
+
+// loadSuppressed fetches the account suppression list.
+func (j *Job) loadSuppressed() error {
+	j.suppressed = make(map[string]string)
+	return j.SES.ListSuppressedDestinationsPages(ses.SuppressedDestinationFilter{}, 1000, func(res ses.ListSuppressedDestinationsResult) bool {
+		for _, d := range res.SuppressedDestinationSummaries {
+			j.suppressed[strings.ToLower(d.EmailAddress)] = d.Reason
+		}
+		return true
+	})
+}
+
+// check returns the result for addr.
+func (j *Job) check(ctx context.Context, addr string) Result {
+	if err := ses.ValidateAddress(addr); err != nil {
+		var verr *ses.ValidationError
+		errors.As(err, &verr)
+		return Result{addr, StatusInvalid, verr.Reason}
+	}
+	bare := addr
+	if a, err := mail.ParseAddress(addr); err == nil {
+		bare = a.Address
+	}
+	if reason, ok := j.suppressed[strings.ToLower(bare)]; ok {
+		return Result{addr, StatusSuppressed, strings.ToLower(reason)}
+	}
+	if j.SkipDNS {
+		return Result{Address: addr, Status: StatusValid}
+	}
+
+	domain := strings.ToLower(bare[strings.LastIndex(bare, "@")+1:])
+	v, _ := j.domains.LoadOrStore(domain, new(domainResult))
+	d := v.(*domainResult)
+	d.once.Do(func() { d.status, d.reason = j.lookup(ctx, domain) })
+	return Result{addr, d.status, d.reason}
+}
+
+// lookup checks that domain accepts mail: it has MX records or, failing that, an address
+// (RFC 5321 section 5.1), and isn't a null MX (RFC 7505).
+func (j *Job) lookup(ctx context.Context, domain string) (status, reason string) {
+	var r Resolver = net.DefaultResolver
+	if j.Resolver != nil {
+		r = j.Resolver
+	}
+	mxs, err := r.LookupMX(ctx, domain)
+	if err == nil && len(mxs) > 0 {
+		if len(mxs) == 1 && (mxs[0].Host == "." || mxs[0].Host == "") {
+			return StatusNoMail, "null MX"
+		}
+		return StatusValid, ""
+	}
+	if err != nil && !notFound(err) {
+		return StatusUnknown, err.Error()
+	}
+	if _, err := r.LookupHost(ctx, domain); err != nil {
+		if notFound(err) {
+			return StatusNoMail, "no MX or address records"
+		}
+		return StatusUnknown, err.Error()
+	}
+	return StatusValid, ""
+}
+
+func notFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
@@ -0,0 +1,138 @@
+package listcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+type fakeResolver struct {
+	mu      sync.Mutex
+	lookups map[string]int
+}
+
+func (r *fakeResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	r.mu.Lock()
+	r.lookups[name]++
+	r.mu.Unlock()
+	switch name {
+	case "example.com":
+		return []*net.MX{{Host: "mx.example.com.", Pref: 10}}, nil
+	case "null.example":
+		return []*net.MX{{Host: "."}}, nil
+	case "broken.example":
+		return nil, &net.DNSError{Err: "server misbehaving", Name: name, IsTemporary: true}
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if host == "a-only.example" {
+		return []string{"192.0.2.1"}, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+type results []Result
+
+func (r *results) Write(res Result) error { *r = append(*r, res); return nil }
+
+func TestJob(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"SuppressedDestinationSummaries":[{"EmailAddress":"Bounced@example.com","Reason":"BOUNCE"}]}`))
+	}))
+	defer srv.Close()
+
+	input := strings.Join([]string{
+		"a@example.com",
+		"Ann <b@example.com>",
+		"",
+		"bounced@example.com",
+		"not an address",
+		"c@null.example",
+		"d@a-only.example",
+		"e@missing.example",
+		"f@broken.example",
+	}, "\n")
+	resolver := &fakeResolver{lookups: make(map[string]int)}
+	var progress []Progress
+	j := Job{
+		SES:        &ses.Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET"},
+		Resolver:   resolver,
+		Workers:    4,
+		OnProgress: func(p Progress) { progress = append(progress, p) },
+	}
+	var res results
+	p, err := j.Run(context.Background(), LineSource(strings.NewReader(input)), &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]string)
+	for _, r := range res {
+		got[r.Address] = r.Status
+	}
+	want := map[string]string{
+		"a@example.com":       StatusValid,
+		"Ann <b@example.com>": StatusValid,
+		"bounced@example.com": StatusSuppressed,
+		"not an address":      StatusInvalid,
+		"c@null.example":      StatusNoMail,
+		"d@a-only.example":    StatusValid,
+		"e@missing.example":   StatusNoMail,
+		"f@broken.example":    StatusUnknown,
+	}
+	for addr, status := range want {
+		if got[addr] != status {
+			t.Errorf("%s: got %q, want %q", addr, got[addr], status)
+		}
+	}
+	if len(res) != len(want) {
+		t.Errorf("got %d results, want %d", len(res), len(want))
+	}
+	if resolver.lookups["example.com"] != 1 {
+		t.Errorf("looked up example.com %d times, want 1", resolver.lookups["example.com"])
+	}
+	if p.Checked != 8 || p.Counts[StatusValid] != 3 || len(progress) == 0 || progress[len(progress)-1].Checked != 8 {
+		t.Errorf("got progress %+v, reports %+v", p, progress)
+	}
+}
+
+type failWriter struct{}
+
+func (failWriter) Write(Result) error { return errors.New("disk full") }
+
+func TestJobWriteError(t *testing.T) {
+	var input strings.Builder
+	for i := 0; i < 1000; i++ {
+		input.WriteString("a@example.com\n")
+	}
+	j := Job{SkipDNS: true}
+	if _, err := j.Run(context.Background(), LineSource(strings.NewReader(input.String())), failWriter{}); err == nil || err.Error() != "disk full" {
+		t.Errorf("got %v, want the write error", err)
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf)
+	w.Write(Result{"Ann <a@example.com>", StatusValid, ""})
+	w.Write(Result{"x", StatusInvalid, "not a valid email address"})
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	sort.Strings(lines)
+	if want := []string{"Ann <a@example.com>,valid,", "x,invalid,not a valid email address"}; strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q", lines)
+	}
+}
@@ -0,0 +1,148 @@
+package ses
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// LogLevel is the severity of a log message.
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "DEBUG"
+	case LogInfo:
+		return "INFO"
+	case LogWarn:
+		return "WARN"
+	case LogError:
+		return "ERROR"
+	}
+	return fmt.Sprintf("LogLevel(%d)", int(l))
+}
+
+// A Logger receives the log messages of a Config. keyvals are alternating keys and values, in
+// the style of log/slog. Every message about a request includes a "request" key whose value
+// correlates all the messages (including retries) for that request.
+type Logger interface {
+	Log(level LogLevel, msg string, keyvals ...interface{})
+}
+
+// LoggerFunc adapts a function to a Logger.
+type LoggerFunc func(level LogLevel, msg string, keyvals ...interface{})
+
+func (f LoggerFunc) Log(level LogLevel, msg string, keyvals ...interface{}) {
+	f(level, msg, keyvals...)
+}
+
+// NewStdLogger returns a Logger that writes messages at level min or above to l, formatted as
+// "LEVEL msg key=value ...". If l is nil, the standard logger is used.
+func NewStdLogger(l *log.Logger, min LogLevel) Logger {
+	if l == nil {
+		l = log.New(log.Writer(), log.Prefix(), log.Flags())
+	}
+	return LoggerFunc(func(level LogLevel, msg string, keyvals ...interface{}) {
+		if level < min {
+			return
+		}
+		var b strings.Builder
+		b.WriteString(level.String())
+		b.WriteString(" ")
+		b.WriteString(msg)
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			fmt.Fprintf(&b, " %v=%q", keyvals[i], fmt.Sprint(keyvals[i+1]))
+		}
+		l.Print(b.String())
+	})
+}
+
+// log sends a message to c.Logger, if set.
+func (c *Config) log(level LogLevel, msg string, keyvals ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Log(level, msg, keyvals...)
+	}
+}
+
+// newRequestID returns a random ID for correlating the log messages of a request.
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// logging returns next wrapped to log each attempt and report it to c.Metrics.
+func (c *Config) logging(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		info := GetRequestInfo(req)
+		var t *phaseTracer
+		if c.Metrics != nil {
+			t = new(phaseTracer)
+			req = req.WithContext(t.trace(req.Context()))
+		}
+		var resp *http.Response
+		_, err := c.logRequest(info.ID, req.Method, info.params, t, func() (string, error) {
+			var err error
+			if resp, err = next(req); err != nil {
+				return "", err
+			}
+			body := bufferBody(resp)
+			if err := responseError(req, resp, body); err != nil {
+				return "", err
+			}
+			return string(body), nil
+		})
+		if resp != nil {
+			return resp, nil
+		}
+		return nil, err
+	}
+}
+
+// logRequest performs one attempt of the request identified by id by calling f, and logs it
+// and reports it, with the phases recorded by t, to c.Metrics.
+func (c *Config) logRequest(id, method string, data url.Values, t *phaseTracer, f func() (string, error)) (string, error) {
+	f = c.measure(id, data.Get("Action"), t, f)
+	if c.Logger == nil {
+		return f()
+	}
+
+	action := data.Get("Action")
+	kv := []interface{}{"request", id, "action", action, "method", method}
+	if c.LogRequests {
+		kv = append(kv, "body", data.Encode())
+	}
+	c.log(LogDebug, "ses request", kv...)
+
+	start := time.Now()
+	res, err := f()
+	elapsed := time.Since(start)
+
+	switch e := err.(type) {
+	case nil:
+		kv := []interface{}{"request", id, "action", action, "duration", elapsed}
+		if c.LogRequests {
+			kv = append(kv, "body", res)
+		}
+		c.log(LogDebug, "ses response", kv...)
+	case *APIError:
+		c.log(LogError, "ses error response", "request", id, "action", action, "duration", elapsed,
+			"status", e.StatusCode, "code", e.Code, "message", e.Message, "aws_request_id", e.RequestID)
+	default:
+		c.log(LogError, "ses request failed", "request", id, "action", action, "duration", elapsed, "error", err)
+	}
+	return res, err
+}
@@ -0,0 +1,83 @@
+package ses
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type logEntry struct {
+	level LogLevel
+	msg   string
+	kv    map[string]interface{}
+}
+
+func recordLogs(entries *[]logEntry) Logger {
+	return LoggerFunc(func(level LogLevel, msg string, keyvals ...interface{}) {
+		e := logEntry{level: level, msg: msg, kv: map[string]interface{}{}}
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			e.kv[keyvals[i].(string)] = keyvals[i+1]
+		}
+		*entries = append(*entries, e)
+	})
+}
+
+func TestLogger(t *testing.T) {
+	var n int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		if n == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(throttlingResponse))
+			return
+		}
+		w.Write([]byte("<SendEmailResponse/>"))
+	}))
+	defer srv.Close()
+
+	var entries []logEntry
+	c := Config{
+		Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET",
+		RetryPolicy: &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+		Logger:      recordLogs(&entries),
+		LogRequests: true,
+	}
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	var msgs []string
+	for _, e := range entries {
+		msgs = append(msgs, e.msg)
+		if e.kv["request"] != entries[0].kv["request"] {
+			t.Errorf("%s: got request ID %v, want %v", e.msg, e.kv["request"], entries[0].kv["request"])
+		}
+	}
+	want := []string{"ses request", "ses error response", "retrying ses request", "ses request", "ses response"}
+	if strings.Join(msgs, ",") != strings.Join(want, ",") {
+		t.Fatalf("got messages %q, want %q", msgs, want)
+	}
+	if e := entries[1]; e.level != LogError || e.kv["code"] != "Throttling" || e.kv["aws_request_id"] != "a1b2c3" {
+		t.Errorf("got error entry %+v", e)
+	}
+	if body, _ := entries[0].kv["body"].(string); !strings.Contains(body, "Action=SendEmail") {
+		t.Errorf("got request body %q, want request parameters", body)
+	}
+	if body := entries[4].kv["body"]; body != "<SendEmailResponse/>" {
+		t.Errorf("got response body %q", body)
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewStdLogger(log.New(&buf, "", 0), LogInfo)
+	l.Log(LogDebug, "hidden")
+	l.Log(LogWarn, "shown", "request", "abc", "attempt", 2)
+	if got, want := buf.String(), "WARN shown request=\"abc\" attempt=\"2\"\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,139 @@
+// Package magiclink sends emails containing signed, expiring verification links and validates
+// those links when they are followed.
+package magiclink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+var (
+	// ErrInvalid is returned by Verify for links that are malformed or whose signature doesn't
+	// match.
+	ErrInvalid = errors.New("magiclink: invalid link")
+
+	// ErrExpired is returned by Verify for links whose TTL has passed.
+	ErrExpired = errors.New("magiclink: link expired")
+
+	// ErrShortKey is returned by Link and Verify when the Linker's Key is shorter than
+	// MinKeySize, so that a missing key can't make links forgeable.
+	ErrShortKey = errors.New("magiclink: key too short")
+)
+
+// MinKeySize is the minimum length of a Linker's Key in bytes.
+const MinKeySize = 32
+
+// timeNow is overridden in tests.
+var timeNow = time.Now
+
+// A Linker creates and verifies links for email addresses.
+type Linker struct {
+	// Key is the secret used to sign links. It must be at least MinKeySize random bytes and
+	// must be kept private.
+	Key []byte
+
+	// URL is the URL of the handler that verifies links, e.g. "https://example.com/verify". The
+	// email, expires and sig query parameters are added to it.
+	URL string
+
+	// TTL is how long links remain valid. If zero, 24 hours is used.
+	TTL time.Duration
+}
+
+func (l *Linker) ttl() time.Duration {
+	if l.TTL == 0 {
+		return 24 * time.Hour
+	}
+	return l.TTL
+}
+
+func (l *Linker) sign(email string, expires int64) string {
+	h := hmac.New(sha256.New, l.Key)
+	h.Write([]byte(email))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// Link returns a signed link for email and the time it expires.
+func (l *Linker) Link(email string) (string, time.Time, error) {
+	if len(l.Key) < MinKeySize {
+		return "", time.Time{}, ErrShortKey
+	}
+	u, err := url.Parse(l.URL)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expires := timeNow().Add(l.ttl()).Truncate(time.Second)
+
+	q := u.Query()
+	q.Set("email", email)
+	q.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	q.Set("sig", l.sign(email, expires.Unix()))
+	u.RawQuery = q.Encode()
+	return u.String(), expires, nil
+}
+
+// Verify checks the email, expires and sig parameters of a followed link (typically
+// r.URL.Query() in the verification handler) and returns the verified email address.
+func (l *Linker) Verify(query url.Values) (string, error) {
+	if len(l.Key) < MinKeySize {
+		return "", ErrShortKey
+	}
+	email := query.Get("email")
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if email == "" || err != nil {
+		return "", ErrInvalid
+	}
+	if !hmac.Equal([]byte(query.Get("sig")), []byte(l.sign(email, expires))) {
+		return "", ErrInvalid
+	}
+	if timeNow().Unix() > expires {
+		return "", ErrExpired
+	}
+	return email, nil
+}
+
+// TemplateData is the data that a Sender's Template is executed with.
+type TemplateData struct {
+	Email   string
+	Link    string
+	Expires time.Time
+}
+
+// A Sender emails verification links.
+type Sender struct {
+	Linker *Linker
+	SES    ses.SenderAPI
+
+	From    string
+	Subject string
+
+	// Template renders the text body of the email from a TemplateData, for example:
+	//
+	//  Click {{.Link}} to verify {{.Email}}. The link expires at {{.Expires}}.
+	Template *template.Template
+}
+
+// Send emails a verification link for email and returns the SES response.
+func (s *Sender) Send(ctx context.Context, email string) (string, error) {
+	link, expires, err := s.Linker.Link(email)
+	if err != nil {
+		return "", err
+	}
+	var body bytes.Buffer
+	if err := s.Template.Execute(&body, TemplateData{Email: email, Link: link, Expires: expires}); err != nil {
+		return "", err
+	}
+	return s.SES.SendEmailContext(ctx, s.From, email, s.Subject, body.String())
+}
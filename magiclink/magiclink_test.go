@@ -0,0 +1,91 @@
+package magiclink
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestLinkVerify(t *testing.T) {
+	l := &Linker{Key: testKey, URL: "https://example.com/verify?lang=en", TTL: time.Hour}
+	link, _, err := l.Link("user@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Query().Get("lang") != "en" {
+		t.Errorf("link %s lost existing query parameters", link)
+	}
+
+	email, err := l.Verify(u.Query())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if email != "user@example.com" {
+		t.Errorf("got email %q, want %q", email, "user@example.com")
+	}
+
+	tampered := u.Query()
+	tampered.Set("email", "attacker@example.com")
+	if _, err := l.Verify(tampered); err != ErrInvalid {
+		t.Errorf("tampered link: got %v, want %v", err, ErrInvalid)
+	}
+
+	timeNow = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	defer func() { timeNow = time.Now }()
+	if _, err := l.Verify(u.Query()); err != ErrExpired {
+		t.Errorf("expired link: got %v, want %v", err, ErrExpired)
+	}
+}
+
+func TestSend(t *testing.T) {
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.Form
+		w.Write([]byte("<SendEmailResponse/>"))
+	}))
+	defer srv.Close()
+
+	s := &Sender{
+		Linker:   &Linker{Key: testKey, URL: "https://example.com/verify"},
+		SES:      &ses.Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET"},
+		From:     "noreply@example.com",
+		Subject:  "Verify your email",
+		Template: template.Must(template.New("").Parse("Click {{.Link}} to verify {{.Email}}.")),
+	}
+	if _, err := s.Send(context.Background(), "user@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if to := form.Get("Destination.ToAddresses.member.1"); to != "user@example.com" {
+		t.Errorf("got recipient %q", to)
+	}
+	body := form.Get("Message.Body.Text.Data")
+	if !strings.HasPrefix(body, "Click https://example.com/verify?email=user%40example.com&expires=") {
+		t.Errorf("got body %q", body)
+	}
+}
+
+func TestShortKey(t *testing.T) {
+	for _, key := range [][]byte{nil, []byte("short")} {
+		l := &Linker{Key: key, URL: "https://example.com/verify"}
+		if _, _, err := l.Link("a@example.com"); err != ErrShortKey {
+			t.Errorf("Link with a %d byte key: got %v, want %v", len(key), err, ErrShortKey)
+		}
+		if _, err := l.Verify(url.Values{"email": {"a@example.com"}, "expires": {"0"}, "sig": {""}}); err != ErrShortKey {
+			t.Errorf("Verify with a %d byte key: got %v, want %v", len(key), err, ErrShortKey)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package ses
+
+import (
+	"context"
+	"net/url"
+)
+
+// Behaviors when the MX record of a custom MAIL FROM domain is not found.
+const (
+	// BehaviorOnMXFailureUseDefaultValue falls back to using amazonses.com as the MAIL FROM
+	// domain.
+	BehaviorOnMXFailureUseDefaultValue = "UseDefaultValue"
+
+	// BehaviorOnMXFailureRejectMessage rejects the message with a MailFromDomainNotVerified
+	// error.
+	BehaviorOnMXFailureRejectMessage = "RejectMessage"
+)
+
+// SetIdentityMailFromDomain sets the custom MAIL FROM domain of an identity, which must be a
+// subdomain of a verified domain, for example "bounce.example.com" for "example.com". Using a
+// custom MAIL FROM domain aligns SPF with the From domain for DMARC. An empty mailFromDomain
+// disables the custom domain. behaviorOnMXFailure is BehaviorOnMXFailureUseDefaultValue or
+// BehaviorOnMXFailureRejectMessage, or empty for the SES default (UseDefaultValue).
+func (c *Config) SetIdentityMailFromDomain(identity, mailFromDomain, behaviorOnMXFailure string) error {
+	return c.SetIdentityMailFromDomainContext(context.Background(), identity, mailFromDomain, behaviorOnMXFailure)
+}
+
+// SetIdentityMailFromDomainContext is like SetIdentityMailFromDomain but uses ctx for the
+// request and any retries.
+func (c *Config) SetIdentityMailFromDomainContext(ctx context.Context, identity, mailFromDomain, behaviorOnMXFailure string) error {
+	data := make(url.Values)
+	data.Add("Action", "SetIdentityMailFromDomain")
+	data.Add("Identity", identity)
+	if mailFromDomain != "" {
+		data.Add("MailFromDomain", mailFromDomain)
+	}
+	if behaviorOnMXFailure != "" {
+		data.Add("BehaviorOnMXFailure", behaviorOnMXFailure)
+	}
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+type IdentityMailFromDomainAttributes struct {
+	MailFromDomain string
+
+	// MailFromDomainStatus is the status of the MX and SPF records of MailFromDomain, one of
+	// VerificationStatusPending, VerificationStatusSuccess, VerificationStatusFailed and
+	// VerificationStatusTemporaryFailure.
+	MailFromDomainStatus string
+	BehaviorOnMXFailure  string
+}
+
+type GetIdentityMailFromDomainAttributesResult struct {
+	MailFromDomainAttributes []struct {
+		Key   string                           `xml:"key"`
+		Value IdentityMailFromDomainAttributes `xml:"value"`
+	} `xml:"MailFromDomainAttributes>entry"`
+}
+
+type GetIdentityMailFromDomainAttributesResponse struct {
+	GetIdentityMailFromDomainAttributesResult GetIdentityMailFromDomainAttributesResult
+}
+
+// GetIdentityMailFromDomainAttributes returns the custom MAIL FROM domain attributes of each of
+// the given identities, keyed by identity.
+func (c *Config) GetIdentityMailFromDomainAttributes(identities ...string) (map[string]IdentityMailFromDomainAttributes, error) {
+	return c.GetIdentityMailFromDomainAttributesContext(context.Background(), identities...)
+}
+
+// GetIdentityMailFromDomainAttributesContext is like GetIdentityMailFromDomainAttributes but
+// uses ctx for the request and any retries.
+func (c *Config) GetIdentityMailFromDomainAttributesContext(ctx context.Context, identities ...string) (map[string]IdentityMailFromDomainAttributes, error) {
+	data := make(url.Values)
+	data.Add("Action", "GetIdentityMailFromDomainAttributes")
+	addMembers(data, "Identities", identities)
+
+	res := GetIdentityMailFromDomainAttributesResponse{}
+	if err := c.call(ctx, "GET", data, &res); err != nil {
+		return nil, err
+	}
+
+	attrs := make(map[string]IdentityMailFromDomainAttributes)
+	for _, e := range res.GetIdentityMailFromDomainAttributesResult.MailFromDomainAttributes {
+		attrs[e.Key] = e.Value
+	}
+	return attrs, nil
+}
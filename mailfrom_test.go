@@ -0,0 +1,49 @@
+package ses
+
+import "testing"
+
+func TestSetIdentityMailFromDomain(t *testing.T) {
+	c, form := testServer(t, `<SetIdentityMailFromDomainResponse/>`)
+	if err := c.SetIdentityMailFromDomain("example.com", "bounce.example.com", BehaviorOnMXFailureRejectMessage); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{
+		"Action":              "SetIdentityMailFromDomain",
+		"Identity":            "example.com",
+		"MailFromDomain":      "bounce.example.com",
+		"BehaviorOnMXFailure": "RejectMessage",
+	})
+
+	if err := c.SetIdentityMailFromDomain("example.com", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := (*form)["MailFromDomain"]; ok {
+		t.Error("got MailFromDomain when disabling")
+	}
+}
+
+func TestGetIdentityMailFromDomainAttributes(t *testing.T) {
+	c, form := testServer(t, `<GetIdentityMailFromDomainAttributesResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <GetIdentityMailFromDomainAttributesResult>
+    <MailFromDomainAttributes>
+      <entry>
+        <key>example.com</key>
+        <value>
+          <MailFromDomain>bounce.example.com</MailFromDomain>
+          <MailFromDomainStatus>Success</MailFromDomainStatus>
+          <BehaviorOnMXFailure>UseDefaultValue</BehaviorOnMXFailure>
+        </value>
+      </entry>
+    </MailFromDomainAttributes>
+  </GetIdentityMailFromDomainAttributesResult>
+</GetIdentityMailFromDomainAttributesResponse>`)
+	attrs, err := c.GetIdentityMailFromDomainAttributes("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"Action": "GetIdentityMailFromDomainAttributes", "Identities.member.1": "example.com"})
+	want := IdentityMailFromDomainAttributes{MailFromDomain: "bounce.example.com", MailFromDomainStatus: VerificationStatusSuccess, BehaviorOnMXFailure: BehaviorOnMXFailureUseDefaultValue}
+	if got := attrs["example.com"]; got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
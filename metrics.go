@@ -0,0 +1,175 @@
+package ses
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"expvar"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// RequestStats describes a completed request attempt.
+type RequestStats struct {
+	// RequestID correlates the attempts of a request, as in log messages.
+	RequestID string
+	Action    string
+	Duration  time.Duration
+
+	// StatusCode is the HTTP status of the response, or 0 if there was none.
+	StatusCode int
+
+	// ErrorCode is the SES error code of a failed attempt, such as "Throttling", if any.
+	ErrorCode string
+
+	// Err is the error of a failed attempt, or nil.
+	Err error
+
+	// Phases breaks Duration down into the phases of the HTTP request.
+	Phases RequestPhases
+}
+
+// RequestPhases are the durations of the phases of a request attempt, measured with
+// net/http/httptrace, so that latency can be attributed to the network or to SES. Phases that
+// didn't happen, such as DNS and Connect on a reused connection, are 0.
+type RequestPhases struct {
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+
+	// TTFB is the time from writing the request to receiving the first byte of the response,
+	// which is mostly time spent by SES.
+	TTFB time.Duration
+
+	ConnReused bool
+}
+
+// phaseTracer records the RequestPhases of a request through an httptrace.ClientTrace.
+type phaseTracer struct {
+	mu                                   sync.Mutex
+	phases                               RequestPhases
+	dnsStart, connStart, tlsStart, wrote time.Time
+}
+
+// trace returns ctx with the ClientTrace of t.
+func (t *phaseTracer) trace(ctx context.Context) context.Context {
+	since := func(start *time.Time, d *time.Duration) {
+		t.mu.Lock()
+		if !start.IsZero() && *d == 0 {
+			*d = time.Since(*start)
+		}
+		t.mu.Unlock()
+	}
+	mark := func(start *time.Time) {
+		t.mu.Lock()
+		if start.IsZero() {
+			*start = time.Now()
+		}
+		t.mu.Unlock()
+	}
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { mark(&t.dnsStart) },
+		DNSDone:           func(httptrace.DNSDoneInfo) { since(&t.dnsStart, &t.phases.DNS) },
+		ConnectStart:      func(network, addr string) { mark(&t.connStart) },
+		ConnectDone:       func(network, addr string, err error) { since(&t.connStart, &t.phases.Connect) },
+		TLSHandshakeStart: func() { mark(&t.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { since(&t.tlsStart, &t.phases.TLS) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.phases.ConnReused = info.Reused
+			t.mu.Unlock()
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { mark(&t.wrote) },
+		GotFirstResponseByte: func() { since(&t.wrote, &t.phases.TTFB) },
+	})
+}
+
+func (t *phaseTracer) result() RequestPhases {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.phases
+}
+
+// Metrics receives instrumentation events for the requests made with a Config. Each attempt
+// of a retried request is reported separately. Implementations must be safe for concurrent use.
+type Metrics interface {
+	OnRequestStart(requestID, action string)
+	OnRequestEnd(stats RequestStats)
+}
+
+// measure returns f wrapped to report its calls to c.Metrics, including the phases recorded by
+// t, if it is non-nil.
+func (c *Config) measure(id, action string, t *phaseTracer, f func() (string, error)) func() (string, error) {
+	m := c.Metrics
+	if m == nil {
+		return f
+	}
+	return func() (string, error) {
+		m.OnRequestStart(id, action)
+		start := time.Now()
+		res, err := f()
+		stats := RequestStats{RequestID: id, Action: action, Duration: time.Since(start), Err: err}
+		if t != nil {
+			stats.Phases = t.result()
+		}
+		switch e := err.(type) {
+		case nil:
+			stats.StatusCode = 200
+		case *APIError:
+			stats.StatusCode = e.StatusCode
+			stats.ErrorCode = e.Code
+		}
+		m.OnRequestEnd(stats)
+		return res, err
+	}
+}
+
+// ExpvarMetrics is a Metrics that counts requests per action in an expvar.Map, so they are
+// served (as JSON) by the expvar handler at /debug/vars and can be scraped into other
+// monitoring systems. For each action it maintains the counters
+//
+//	<action>.requests     completed attempts
+//	<action>.in_flight    attempts in progress
+//	<action>.errors       failed attempts
+//	<action>.throttled    attempts that failed because of throttling
+//	<action>.latency_ns   total duration of completed attempts
+//	<action>.dns_ns       total duration of DNS lookups
+//	<action>.connect_ns   total duration of connecting
+//	<action>.tls_ns       total duration of TLS handshakes
+//	<action>.ttfb_ns      total time to the first response byte
+//
+// and <action>.errors.<code> for each SES error code.
+type ExpvarMetrics struct {
+	Map *expvar.Map
+}
+
+// NewExpvarMetrics returns an ExpvarMetrics whose map is published as the expvar name. Like
+// expvar.Publish, it panics if name is already in use.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	return &ExpvarMetrics{Map: expvar.NewMap(name)}
+}
+
+func (m *ExpvarMetrics) OnRequestStart(requestID, action string) {
+	m.Map.Add(action+".in_flight", 1)
+}
+
+func (m *ExpvarMetrics) OnRequestEnd(stats RequestStats) {
+	a := stats.Action
+	m.Map.Add(a+".in_flight", -1)
+	m.Map.Add(a+".requests", 1)
+	m.Map.Add(a+".latency_ns", int64(stats.Duration))
+	m.Map.Add(a+".dns_ns", int64(stats.Phases.DNS))
+	m.Map.Add(a+".connect_ns", int64(stats.Phases.Connect))
+	m.Map.Add(a+".tls_ns", int64(stats.Phases.TLS))
+	m.Map.Add(a+".ttfb_ns", int64(stats.Phases.TTFB))
+	if stats.Err != nil {
+		m.Map.Add(a+".errors", 1)
+		if stats.ErrorCode != "" {
+			m.Map.Add(a+".errors."+stats.ErrorCode, 1)
+		}
+		if errors.Is(stats.Err, ErrThrottled) {
+			m.Map.Add(a+".throttled", 1)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package ses
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestExpvarMetrics(t *testing.T) {
+	n := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		if n == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(throttlingResponse))
+			return
+		}
+		w.Write([]byte(`<SendEmailResponse/>`))
+	}))
+	defer srv.Close()
+
+	m := NewExpvarMetrics("sestest_metrics")
+	c := &Config{
+		Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET",
+		RetryPolicy: &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+		Metrics:     m,
+	}
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"SendEmail.requests":          "2",
+		"SendEmail.in_flight":         "0",
+		"SendEmail.errors":            "1",
+		"SendEmail.errors.Throttling": "1",
+		"SendEmail.throttled":         "1",
+	} {
+		v := m.Map.Get(name)
+		if v == nil || v.String() != want {
+			t.Errorf("got %s %v, want %s", name, v, want)
+		}
+	}
+	if v := m.Map.Get("SendEmail.latency_ns"); v == nil || v.String() == "0" {
+		t.Errorf("got latency %v", v)
+	}
+}
+
+type statsRecorder []RequestStats
+
+func (r *statsRecorder) OnRequestStart(requestID, action string) {}
+func (r *statsRecorder) OnRequestEnd(stats RequestStats)         { *r = append(*r, stats) }
+
+func TestRequestPhases(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+		w.Write([]byte(`<SendEmailResponse/>`))
+	}))
+	defer srv.Close()
+	defer func(t http.RoundTripper) { http.DefaultTransport = t }(http.DefaultTransport)
+	http.DefaultTransport = srv.Client().Transport
+
+	var stats statsRecorder
+	c := &Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET", Metrics: &stats}
+	for i := 0; i < 2; i++ {
+		if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(stats) != 2 {
+		t.Fatalf("got %d stats, want 2", len(stats))
+	}
+	p := stats[0].Phases
+	if p.Connect == 0 || p.TLS == 0 || p.TTFB < 5*time.Millisecond || p.ConnReused {
+		t.Errorf("got phases %+v for a new connection", p)
+	}
+	if p := stats[1].Phases; !p.ConnReused || p.TLS != 0 || p.TTFB == 0 {
+		t.Errorf("got phases %+v for a reused connection", p)
+	}
+}
@@ -0,0 +1,135 @@
+package ses
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// A RoundTripFunc sends an SES HTTP request and returns its response. Like an
+// http.RoundTripper, it returns an error only if no response was received; an SES error
+// response is returned as a response with its status code.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// A Middleware wraps the RoundTripFunc that sends each SES request, to inspect or modify the
+// request and its response. See Config.Middleware.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// RequestInfo describes the SES request that an *http.Request passed to a Middleware is an
+// attempt of.
+type RequestInfo struct {
+	// ID correlates the attempts of a request, as in log messages.
+	ID     string
+	Action string
+
+	// Attempt is 1 for the first attempt, 2 for the first retry, etc.
+	Attempt int
+
+	params url.Values // for log messages
+	v2     bool       // whether the request is to the SESv2 API
+}
+
+type requestInfoKey struct{}
+
+// GetRequestInfo returns the RequestInfo of req, which must have been passed to a Middleware.
+func GetRequestInfo(req *http.Request) RequestInfo {
+	info, _ := req.Context().Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
+
+func withRequestInfo(req *http.Request, info RequestInfo) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), requestInfoKey{}, info))
+}
+
+// roundTrip returns the chain that c sends requests through: retries (if c.RetryPolicy is
+// set), then logging and metrics, signing, c.Middleware, and finally the HTTP client.
+func (c *Config) roundTrip() RoundTripFunc {
+	rt := RoundTripFunc(http.DefaultClient.Do)
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		rt = c.Middleware[i](rt)
+	}
+	rt = c.signing(rt)
+	rt = c.logging(rt)
+	if c.RetryPolicy != nil {
+		rt = c.retrying(rt)
+	}
+	return rt
+}
+
+// do sends req, the request for the action described by info, through c's chain and returns the
+// response body. req's body, if any, is taken from req.GetBody, which is called once per
+// attempt.
+func (c *Config) do(req *http.Request, info RequestInfo) (string, error) {
+	info.Attempt = 1
+	req = withRequestInfo(req, info)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return "", err
+		}
+		req.Body = body
+	}
+	resp, err := c.roundTrip()(req)
+	if err != nil {
+		return "", err
+	}
+	body := bufferBody(resp)
+	if err := responseError(req, resp, body); err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// bufferBody reads and closes the body of resp, and replaces it with a copy, so that it can be
+// read again. It returns the body.
+func bufferBody(resp *http.Response) []byte {
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body
+}
+
+// responseError returns the *APIError for resp, whose body is body, or nil if resp is a
+// success.
+func responseError(req *http.Request, resp *http.Response, body []byte) error {
+	if GetRequestInfo(req).v2 {
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return newV2APIError(resp.StatusCode, resp.Header, body)
+		}
+		return nil
+	}
+	if resp.StatusCode != 200 {
+		return newAPIError(resp.StatusCode, body)
+	}
+	return nil
+}
+
+// requestBody returns a copy of the body of req, read from req.GetBody.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.GetBody == nil {
+		return nil, nil
+	}
+	r, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// prependBody returns a GetBody function that returns prefix followed by getBody's body.
+func prependBody(prefix string, getBody func() (io.ReadCloser, error)) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		r, err := getBody()
+		if err != nil {
+			return nil, err
+		}
+		return struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader([]byte(prefix)), r), r}, nil
+	}
+}
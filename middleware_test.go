@@ -0,0 +1,113 @@
+package ses
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMiddleware(t *testing.T) {
+	var n int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Trace-Id") != "trace" {
+			t.Errorf("got X-Trace-Id %q, want %q", r.Header.Get("X-Trace-Id"), "trace")
+		}
+		r.ParseForm()
+		if r.Form.Get("Source") != "a@example.com" || r.Form.Get("AWSAccessKeyId") != "AKID" {
+			t.Errorf("got form %v", r.Form)
+		}
+		if n++; n == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(throttlingResponse))
+			return
+		}
+		w.Write([]byte("<SendEmailResponse/>"))
+	}))
+	defer srv.Close()
+
+	var calls []string
+	var infos []RequestInfo
+	c := Config{
+		Endpoint:        srv.URL,
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "SECRET",
+		RetryPolicy:     &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+		Middleware: []Middleware{
+			func(next RoundTripFunc) RoundTripFunc {
+				return func(req *http.Request) (*http.Response, error) {
+					calls = append(calls, "outer")
+					infos = append(infos, GetRequestInfo(req))
+					if req.Header.Get("X-Amzn-Authorization") == "" {
+						t.Error("request is not signed")
+					}
+					req.Header.Set("X-Trace-Id", "trace")
+					return next(req)
+				}
+			},
+			func(next RoundTripFunc) RoundTripFunc {
+				return func(req *http.Request) (*http.Response, error) {
+					calls = append(calls, "inner")
+					resp, err := next(req)
+					if err == nil && resp.StatusCode == 200 {
+						body, _ := ioutil.ReadAll(resp.Body)
+						if string(body) != "<SendEmailResponse/>" {
+							t.Errorf("got body %q", body)
+						}
+						resp.Body = ioutil.NopCloser(strings.NewReader(string(body)))
+					}
+					return resp, err
+				}
+			},
+		},
+	}
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(calls, ","), "outer,inner,outer,inner"; got != want {
+		t.Errorf("got calls %s, want %s", got, want)
+	}
+	if len(infos) != 2 || infos[0].ID == "" || infos[1].ID != infos[0].ID || infos[0].Action != "SendEmail" || infos[0].Attempt != 1 || infos[1].Attempt != 2 {
+		t.Errorf("got request infos %+v", infos)
+	}
+}
+
+func TestMiddlewareV2(t *testing.T) {
+	c := testV2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+			t.Errorf("got Authorization %q", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{}`))
+	})
+	var info RequestInfo
+	c.Middleware = []Middleware{func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			info = GetRequestInfo(req)
+			return next(req)
+		}
+	}}
+	if err := c.DeleteSuppressedDestination("a@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if info.Action != "DeleteSuppressedDestination" || info.Attempt != 1 {
+		t.Errorf("got request info %+v", info)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	c := Config{Endpoint: "http://ses.invalid", AccessKeyID: "AKID", SecretAccessKey: "SECRET"}
+	c.Middleware = []Middleware{func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       ioutil.NopCloser(strings.NewReader(throttlingResponse)),
+			}, nil
+		}
+	}}
+	_, err := c.GetSendQuota()
+	if e, ok := err.(*APIError); !ok || e.Code != "Throttling" {
+		t.Errorf("got %v, want Throttling error", err)
+	}
+}
@@ -0,0 +1,156 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+// A Handler receives SNS messages carrying SES notifications over HTTP, verifies them, and
+// dispatches them to the callback for their type. A callback that returns an error causes a
+// 500 response, so that SNS redelivers the message later.
+type Handler struct {
+	OnBounce    func(ctx context.Context, n *Notification) error
+	OnComplaint func(ctx context.Context, n *Notification) error
+	OnDelivery  func(ctx context.Context, n *Notification) error
+
+	// ConfirmSubscriptions, if true, confirms SNS subscriptions to the handler's URL by
+	// visiting the SubscribeURL of SubscriptionConfirmation messages.
+	ConfirmSubscriptions bool
+
+	// TopicARNs, if non-empty, lists the SNS topics that messages are accepted from.
+	TopicARNs []string
+
+	// Seen, if non-nil, records the IDs of handled notifications, so that a notification that
+	// SNS delivers more than once is only passed to the callbacks once.
+	Seen SeenStore
+
+	// Verifier verifies SNS message signatures. If nil, DefaultVerifier is used.
+	Verifier *Verifier
+
+	// Client is used to confirm subscriptions. If nil, http.DefaultClient is used.
+	Client *http.Client
+
+	// Logger, if non-nil, receives a message for each rejected or failed request.
+	Logger ses.Logger
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	m, err := ParseSNS(r.Body)
+	if err != nil {
+		http.Error(w, "malformed SNS message", http.StatusBadRequest)
+		return
+	}
+	if !h.topicAllowed(m.TopicARN) {
+		http.Error(w, "topic not allowed", http.StatusForbidden)
+		return
+	}
+	v := h.Verifier
+	if v == nil {
+		v = DefaultVerifier
+	}
+	if err := v.Verify(m); err != nil {
+		h.log(ses.LogWarn, "rejecting SNS message", "message_id", m.MessageID, "error", err)
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	switch m.Type {
+	case SNSTypeSubscriptionConfirmation:
+		if h.ConfirmSubscriptions {
+			if err := h.confirm(r.Context(), m.SubscribeURL); err != nil {
+				h.log(ses.LogError, "confirming SNS subscription failed", "topic", m.TopicARN, "error", err)
+				http.Error(w, "subscription confirmation failed", http.StatusInternalServerError)
+				return
+			}
+		}
+	case SNSTypeNotification:
+		n, err := Parse(m.Message)
+		if err != nil {
+			http.Error(w, "malformed SES notification", http.StatusBadRequest)
+			return
+		}
+		if h.Seen != nil {
+			seen, err := h.Seen.MarkSeen(r.Context(), m.MessageID)
+			if err != nil {
+				h.log(ses.LogError, "checking SNS message ID failed", "message_id", m.MessageID, "error", err)
+				http.Error(w, "notification handler failed", http.StatusInternalServerError)
+				return
+			}
+			if seen {
+				break
+			}
+		}
+		if err := h.dispatch(r.Context(), n); err != nil {
+			if h.Seen != nil {
+				if err := h.Seen.Forget(r.Context(), m.MessageID); err != nil {
+					h.log(ses.LogError, "forgetting SNS message ID failed", "message_id", m.MessageID, "error", err)
+				}
+			}
+			h.log(ses.LogError, "handling notification failed", "type", n.NotificationType, "message_id", m.MessageID, "error", err)
+			http.Error(w, "notification handler failed", http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) log(level ses.LogLevel, msg string, keyvals ...interface{}) {
+	if h.Logger != nil {
+		h.Logger.Log(level, msg, keyvals...)
+	}
+}
+
+func (h *Handler) topicAllowed(arn string) bool {
+	if len(h.TopicARNs) == 0 {
+		return true
+	}
+	for _, a := range h.TopicARNs {
+		if a == arn {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Handler) dispatch(ctx context.Context, n *Notification) error {
+	var f func(context.Context, *Notification) error
+	switch n.NotificationType {
+	case TypeBounce:
+		f = h.OnBounce
+	case TypeComplaint:
+		f = h.OnComplaint
+	case TypeDelivery:
+		f = h.OnDelivery
+	}
+	if f == nil {
+		return nil
+	}
+	return f(ctx, n)
+}
+
+func (h *Handler) confirm(ctx context.Context, subscribeURL string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", subscribeURL, nil)
+	if err != nil {
+		return err
+	}
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("subscribe URL returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+func post(h http.Handler, m *SNSMessage) int {
+	body, _ := json.Marshal(m)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/sns", bytes.NewReader(body)))
+	return w.Code
+}
+
+func TestHandler(t *testing.T) {
+	v, sign := testSigner(t)
+	var bounces []string
+	h := &Handler{
+		Verifier:  v,
+		TopicARNs: []string{"arn:aws:sns:us-east-1:123456789012:bounces"},
+		OnBounce: func(ctx context.Context, n *Notification) error {
+			bounces = append(bounces, n.Bounce.BouncedRecipients[0].EmailAddress)
+			return nil
+		},
+		OnComplaint: func(ctx context.Context, n *Notification) error {
+			return errors.New("complaint store unavailable")
+		},
+	}
+	var logged []string
+	h.Logger = ses.LoggerFunc(func(level ses.LogLevel, msg string, keyvals ...interface{}) {
+		logged = append(logged, msg)
+	})
+
+	m := &SNSMessage{Type: SNSTypeNotification, MessageID: "1", TopicARN: "arn:aws:sns:us-east-1:123456789012:bounces", Message: bounceNotification, Timestamp: "2016-01-27T14:59:38.237Z"}
+	sign(m)
+	if code := post(h, m); code != http.StatusOK {
+		t.Errorf("bounce: got status %d", code)
+	}
+	if len(bounces) != 1 || bounces[0] != "jane@example.com" {
+		t.Errorf("got bounces %v", bounces)
+	}
+
+	m.Message = `{"notificationType": "Complaint", "complaint": {}}`
+	sign(m)
+	if code := post(h, m); code != http.StatusInternalServerError {
+		t.Errorf("failing callback: got status %d, want 500", code)
+	}
+	if len(logged) != 1 || logged[0] != "handling notification failed" {
+		t.Errorf("got log messages %q", logged)
+	}
+
+	m.Message = bounceNotification // signature no longer matches
+	if code := post(h, m); code != http.StatusForbidden {
+		t.Errorf("bad signature: got status %d, want 403", code)
+	}
+
+	m.TopicARN = "arn:aws:sns:us-east-1:123456789012:other"
+	sign(m)
+	if code := post(h, m); code != http.StatusForbidden {
+		t.Errorf("other topic: got status %d, want 403", code)
+	}
+}
+
+func TestHandlerConfirmSubscription(t *testing.T) {
+	v, sign := testSigner(t)
+	var confirmed bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		confirmed = r.URL.Query().Get("Token") == "tok"
+	}))
+	defer srv.Close()
+
+	h := &Handler{Verifier: v, ConfirmSubscriptions: true}
+	m := &SNSMessage{Type: SNSTypeSubscriptionConfirmation, MessageID: "1", Token: "tok", SubscribeURL: srv.URL + "/?Action=ConfirmSubscription&Token=tok"}
+	sign(m)
+	if code := post(h, m); code != http.StatusOK {
+		t.Errorf("got status %d", code)
+	}
+	if !confirmed {
+		t.Error("subscription was not confirmed")
+	}
+}
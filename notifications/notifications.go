@@ -0,0 +1,123 @@
+// Package notifications parses the bounce, complaint and delivery notifications that Amazon SES
+// publishes to Amazon SNS, verifies the SNS messages that carry them, and provides an
+// http.Handler that dispatches them to callbacks, optionally once per message ID.
+package notifications
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Notification types.
+const (
+	TypeBounce    = "Bounce"
+	TypeComplaint = "Complaint"
+	TypeDelivery  = "Delivery"
+)
+
+// Bounce types.
+const (
+	BounceTypeUndetermined = "Undetermined"
+	BounceTypePermanent    = "Permanent"
+	BounceTypeTransient    = "Transient"
+)
+
+// A Notification is an SES notification, as found in the Message of an SNS notification.
+type Notification struct {
+	// NotificationType is TypeBounce, TypeComplaint or TypeDelivery. For events published
+	// through a configuration set's SNS event destination, it is set from eventType.
+	NotificationType string `json:"notificationType"`
+
+	// EventType is set instead of NotificationType for events published through a
+	// configuration set.
+	EventType string `json:"eventType,omitempty"`
+
+	Mail Mail `json:"mail"`
+
+	// Exactly one of Bounce, Complaint and Delivery is set, depending on NotificationType.
+	Bounce    *Bounce    `json:"bounce,omitempty"`
+	Complaint *Complaint `json:"complaint,omitempty"`
+	Delivery  *Delivery  `json:"delivery,omitempty"`
+}
+
+// Mail describes the original message that a notification is about.
+type Mail struct {
+	Timestamp        time.Time           `json:"timestamp"`
+	MessageID        string              `json:"messageId"`
+	Source           string              `json:"source"`
+	SourceARN        string              `json:"sourceArn"`
+	SourceIP         string              `json:"sourceIp"`
+	SendingAccountID string              `json:"sendingAccountId"`
+	Destination      []string            `json:"destination"`
+	HeadersTruncated bool                `json:"headersTruncated"`
+	Headers          []Header            `json:"headers"`
+	CommonHeaders    CommonHeaders       `json:"commonHeaders"`
+	Tags             map[string][]string `json:"tags"`
+}
+
+type Header struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type CommonHeaders struct {
+	From       []string `json:"from"`
+	To         []string `json:"to"`
+	ReturnPath string   `json:"returnPath"`
+	MessageID  string   `json:"messageId"`
+	Date       string   `json:"date"`
+	Subject    string   `json:"subject"`
+}
+
+type Bounce struct {
+	// BounceType is one of the BounceType constants.
+	BounceType        string             `json:"bounceType"`
+	BounceSubType     string             `json:"bounceSubType"`
+	BouncedRecipients []BouncedRecipient `json:"bouncedRecipients"`
+	Timestamp         time.Time          `json:"timestamp"`
+	FeedbackID        string             `json:"feedbackId"`
+	RemoteMTAIP       string             `json:"remoteMtaIp"`
+	ReportingMTA      string             `json:"reportingMTA"`
+}
+
+type BouncedRecipient struct {
+	EmailAddress   string `json:"emailAddress"`
+	Action         string `json:"action"`
+	Status         string `json:"status"`
+	DiagnosticCode string `json:"diagnosticCode"`
+}
+
+type Complaint struct {
+	ComplainedRecipients  []ComplainedRecipient `json:"complainedRecipients"`
+	Timestamp             time.Time             `json:"timestamp"`
+	FeedbackID            string                `json:"feedbackId"`
+	ComplaintSubType      string                `json:"complaintSubType"`
+	UserAgent             string                `json:"userAgent"`
+	ComplaintFeedbackType string                `json:"complaintFeedbackType"`
+	ArrivalDate           time.Time             `json:"arrivalDate"`
+}
+
+type ComplainedRecipient struct {
+	EmailAddress string `json:"emailAddress"`
+}
+
+type Delivery struct {
+	Timestamp            time.Time `json:"timestamp"`
+	ProcessingTimeMillis int64     `json:"processingTimeMillis"`
+	Recipients           []string  `json:"recipients"`
+	SMTPResponse         string    `json:"smtpResponse"`
+	RemoteMTAIP          string    `json:"remoteMtaIp"`
+	ReportingMTA         string    `json:"reportingMTA"`
+}
+
+// Parse parses an SES notification from the Message of an SNS notification.
+func Parse(message string) (*Notification, error) {
+	var n Notification
+	if err := json.Unmarshal([]byte(message), &n); err != nil {
+		return nil, err
+	}
+	if n.NotificationType == "" {
+		n.NotificationType = n.EventType
+	}
+	return &n, nil
+}
@@ -0,0 +1,71 @@
+package notifications
+
+import "testing"
+
+const bounceNotification = `{
+  "notificationType": "Bounce",
+  "bounce": {
+    "bounceType": "Permanent",
+    "bounceSubType": "General",
+    "bouncedRecipients": [
+      {
+        "emailAddress": "jane@example.com",
+        "action": "failed",
+        "status": "5.1.1",
+        "diagnosticCode": "smtp; 550 5.1.1 user unknown"
+      }
+    ],
+    "timestamp": "2016-01-27T14:59:38.237Z",
+    "feedbackId": "00000138111222aa-33322211-cccc-cccc-cccc-ddddaaaa068a-000000",
+    "remoteMtaIp": "127.0.2.0",
+    "reportingMTA": "dsn; a8-70.smtp-out.amazonses.com"
+  },
+  "mail": {
+    "timestamp": "2016-01-27T14:59:38.237Z",
+    "source": "john@example.com",
+    "sourceArn": "arn:aws:ses:us-east-1:888888888888:identity/example.com",
+    "sourceIp": "127.0.3.0",
+    "sendingAccountId": "123456789012",
+    "messageId": "00000138111222aa-33322211-cccc-cccc-cccc-ddddaaaa0680-000000",
+    "destination": ["jane@example.com"],
+    "headersTruncated": false,
+    "headers": [{"name": "From", "value": "\"John Doe\" <john@example.com>"}],
+    "commonHeaders": {
+      "from": ["John Doe <john@example.com>"],
+      "to": ["Jane Doe <jane@example.com>"],
+      "subject": "Hello"
+    }
+  }
+}`
+
+func TestParseBounce(t *testing.T) {
+	n, err := Parse(bounceNotification)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.NotificationType != TypeBounce || n.Bounce == nil || n.Complaint != nil {
+		t.Fatalf("got %+v", n)
+	}
+	if n.Bounce.BounceType != BounceTypePermanent || len(n.Bounce.BouncedRecipients) != 1 {
+		t.Errorf("got bounce %+v", n.Bounce)
+	}
+	if r := n.Bounce.BouncedRecipients[0]; r.EmailAddress != "jane@example.com" || r.DiagnosticCode != "smtp; 550 5.1.1 user unknown" {
+		t.Errorf("got bounced recipient %+v", r)
+	}
+	if n.Bounce.FeedbackID != "00000138111222aa-33322211-cccc-cccc-cccc-ddddaaaa068a-000000" {
+		t.Errorf("got feedbackId %q", n.Bounce.FeedbackID)
+	}
+	if n.Mail.MessageID != "00000138111222aa-33322211-cccc-cccc-cccc-ddddaaaa0680-000000" || n.Mail.CommonHeaders.Subject != "Hello" {
+		t.Errorf("got mail %+v", n.Mail)
+	}
+}
+
+func TestParseEvent(t *testing.T) {
+	n, err := Parse(`{"eventType": "Complaint", "complaint": {"complainedRecipients": [{"emailAddress": "jane@example.com"}], "complaintFeedbackType": "abuse"}, "mail": {"messageId": "id"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.NotificationType != TypeComplaint || n.Complaint == nil || n.Complaint.ComplainedRecipients[0].EmailAddress != "jane@example.com" {
+		t.Errorf("got %+v", n)
+	}
+}
@@ -0,0 +1,61 @@
+package notifications
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A SeenStore records the IDs of the SNS messages that a Handler has handled, so that messages
+// SNS delivers more than once are handled once. Implementations backed by a shared database
+// deduplicate across processes.
+type SeenStore interface {
+	// MarkSeen atomically records id, and reports whether it was already recorded.
+	MarkSeen(ctx context.Context, id string) (seen bool, err error)
+
+	// Forget removes id, after handling its message failed, so that the redelivered message is
+	// handled.
+	Forget(ctx context.Context, id string) error
+}
+
+// MemorySeenStore is a SeenStore that keeps message IDs in memory for a time. It is safe for
+// concurrent use.
+type MemorySeenStore struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	lastPrune time.Time
+}
+
+// NewMemorySeenStore returns a MemorySeenStore that remembers message IDs for ttl. SNS retries
+// a delivery for up to an hour by default, so ttl should be at least that long.
+func NewMemorySeenStore(ttl time.Duration) *MemorySeenStore {
+	return &MemorySeenStore{ttl: ttl, seen: make(map[string]time.Time)}
+}
+
+func (s *MemorySeenStore) MarkSeen(ctx context.Context, id string) (bool, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.lastPrune) > s.ttl {
+		for k, t := range s.seen {
+			if now.Sub(t) >= s.ttl {
+				delete(s.seen, k)
+			}
+		}
+		s.lastPrune = now
+	}
+	if t, ok := s.seen[id]; ok && now.Sub(t) < s.ttl {
+		return true, nil
+	}
+	s.seen[id] = now
+	return false, nil
+}
+
+func (s *MemorySeenStore) Forget(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.seen, id)
+	return nil
+}
@@ -0,0 +1,57 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHandlerSeen(t *testing.T) {
+	v, sign := testSigner(t)
+	bounces := 0
+	fail := true
+	h := &Handler{
+		Verifier: v,
+		Seen:     NewMemorySeenStore(time.Hour),
+		OnBounce: func(ctx context.Context, n *Notification) error {
+			bounces++
+			if fail {
+				return errors.New("suppression list unavailable")
+			}
+			return nil
+		},
+	}
+	m := &SNSMessage{Type: SNSTypeNotification, MessageID: "1", TopicARN: "arn:aws:sns:us-east-1:123456789012:bounces", Message: bounceNotification, Timestamp: "2016-01-27T14:59:38.237Z"}
+	sign(m)
+
+	// A failed delivery is handled again when SNS redelivers it, and then not again.
+	for i, want := range []int{http.StatusInternalServerError, http.StatusOK, http.StatusOK} {
+		if code := post(h, m); code != want {
+			t.Errorf("delivery %d: got status %d, want %d", i+1, code, want)
+		}
+		fail = false
+	}
+	if bounces != 2 {
+		t.Errorf("got %d bounces handled, want 2", bounces)
+	}
+}
+
+func TestMemorySeenStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemorySeenStore(50 * time.Millisecond)
+	if seen, _ := s.MarkSeen(ctx, "a"); seen {
+		t.Error("new ID reported as seen")
+	}
+	if seen, _ := s.MarkSeen(ctx, "a"); !seen {
+		t.Error("repeated ID not reported as seen")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if seen, _ := s.MarkSeen(ctx, "a"); seen {
+		t.Error("ID reported as seen after the TTL")
+	}
+	if n := len(s.seen); n != 1 {
+		t.Errorf("got %d IDs kept, want 1 after pruning", n)
+	}
+}
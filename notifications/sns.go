@@ -0,0 +1,198 @@
+package notifications
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// SNS message types.
+const (
+	SNSTypeNotification             = "Notification"
+	SNSTypeSubscriptionConfirmation = "SubscriptionConfirmation"
+	SNSTypeUnsubscribeConfirmation  = "UnsubscribeConfirmation"
+)
+
+// An SNSMessage is the JSON document that Amazon SNS posts to HTTP(S) subscribers.
+type SNSMessage struct {
+	Type             string
+	MessageID        string `json:"MessageId"`
+	Token            string `json:",omitempty"`
+	TopicARN         string `json:"TopicArn"`
+	Subject          string `json:",omitempty"`
+	Message          string
+	Timestamp        string
+	SignatureVersion string
+	Signature        string
+	SigningCertURL   string
+	SubscribeURL     string `json:",omitempty"`
+	UnsubscribeURL   string `json:",omitempty"`
+}
+
+// maxSNSMessageSize is larger than the largest SNS message (256 KB) to allow for the envelope.
+const maxSNSMessageSize = 512 << 10
+
+// ParseSNS parses an SNS message from r.
+func ParseSNS(r io.Reader) (*SNSMessage, error) {
+	var m SNSMessage
+	if err := json.NewDecoder(io.LimitReader(r, maxSNSMessageSize)).Decode(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// stringToSign returns the canonical string that m's signature is computed over.
+func (m *SNSMessage) stringToSign() string {
+	var keys []string
+	if m.Type == SNSTypeNotification {
+		keys = []string{"Message", m.Message, "MessageId", m.MessageID}
+		if m.Subject != "" {
+			keys = append(keys, "Subject", m.Subject)
+		}
+		keys = append(keys, "Timestamp", m.Timestamp, "TopicArn", m.TopicARN, "Type", m.Type)
+	} else {
+		keys = []string{"Message", m.Message, "MessageId", m.MessageID, "SubscribeURL", m.SubscribeURL,
+			"Timestamp", m.Timestamp, "Token", m.Token, "TopicArn", m.TopicARN, "Type", m.Type}
+	}
+	var s string
+	for i := 0; i < len(keys); i += 2 {
+		s += keys[i] + "\n" + keys[i+1] + "\n"
+	}
+	return s
+}
+
+// ErrInvalidSignature is returned by Verifier.Verify when a message's signature doesn't match.
+var ErrInvalidSignature = errors.New("notifications: invalid SNS message signature")
+
+// snsCertHost matches the hosts that SNS signing certificates are served from.
+var snsCertHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// A Verifier verifies the signatures of SNS messages. It fetches the signing certificates from
+// SNS and caches them. It is safe for concurrent use.
+//
+// A Verifier can be used without a Handler, to authenticate SNS messages received through
+// another framework: decode the message with ParseSNS and pass it to Verify.
+type Verifier struct {
+	// Client is used to fetch signing certificates. If nil, a client with a 10 second timeout
+	// is used.
+	Client *http.Client
+
+	// allowCertURL, if set, overrides the check that signing certificates are served by SNS.
+	// It is used in tests.
+	allowCertURL func(*url.URL) bool
+
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+// DefaultVerifier is the Verifier used by a Handler that has none.
+var DefaultVerifier = &Verifier{}
+
+var defaultCertClient = &http.Client{Timeout: 10 * time.Second}
+
+// Verify checks that m was signed by Amazon SNS.
+func (v *Verifier) Verify(m *SNSMessage) error {
+	var hash crypto.Hash
+	switch m.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return fmt.Errorf("notifications: unsupported SNS signature version %q", m.SignatureVersion)
+	}
+	sig, err := base64.StdEncoding.DecodeString(m.Signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	cert, err := v.cert(m.SigningCertURL)
+	if err != nil {
+		return err
+	}
+	pub, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("notifications: SNS signing certificate has no RSA public key")
+	}
+
+	var digest []byte
+	if hash == crypto.SHA1 {
+		d := sha1.Sum([]byte(m.stringToSign()))
+		digest = d[:]
+	} else {
+		d := sha256.Sum256([]byte(m.stringToSign()))
+		digest = d[:]
+	}
+	if err := rsa.VerifyPKCS1v15(pub, hash, digest, sig); err != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// cert returns the certificate at certURL, fetching it if it isn't cached.
+func (v *Verifier) cert(certURL string) (*x509.Certificate, error) {
+	u, err := url.Parse(certURL)
+	if err != nil {
+		return nil, err
+	}
+	if v.allowCertURL != nil {
+		if !v.allowCertURL(u) {
+			return nil, fmt.Errorf("notifications: signing certificate URL %q not allowed", certURL)
+		}
+	} else if u.Scheme != "https" || !snsCertHost.MatchString(u.Host) {
+		return nil, fmt.Errorf("notifications: signing certificate URL %q is not an SNS URL", certURL)
+	}
+
+	v.mu.Lock()
+	cert := v.certs[certURL]
+	v.mu.Unlock()
+	if cert != nil {
+		return cert, nil
+	}
+
+	client := v.Client
+	if client == nil {
+		client = defaultCertClient
+	}
+	resp, err := client.Get(certURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("notifications: fetching signing certificate %s: status %d", certURL, resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(body)
+	if block == nil {
+		return nil, fmt.Errorf("notifications: signing certificate %s is not PEM encoded", certURL)
+	}
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	if v.certs == nil {
+		v.certs = make(map[string]*x509.Certificate)
+	}
+	v.certs[certURL] = cert
+	v.mu.Unlock()
+	return cert, nil
+}
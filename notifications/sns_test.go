@@ -0,0 +1,141 @@
+package notifications
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSigner returns a Verifier that trusts a test signing certificate, and a function that
+// signs messages with it.
+func testSigner(t *testing.T) (*Verifier, func(*SNSMessage)) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(certPEM)
+	}))
+	t.Cleanup(srv.Close)
+	srvURL, _ := url.Parse(srv.URL)
+
+	v := &Verifier{
+		Client:       srv.Client(),
+		allowCertURL: func(u *url.URL) bool { return u.Host == srvURL.Host },
+	}
+	sign := func(m *SNSMessage) {
+		m.SignatureVersion = "2"
+		m.SigningCertURL = srv.URL + "/SimpleNotificationService.pem"
+		digest := sha256.Sum256([]byte(m.stringToSign()))
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Signature = base64.StdEncoding.EncodeToString(sig)
+	}
+	return v, sign
+}
+
+func TestVerify(t *testing.T) {
+	v, sign := testSigner(t)
+	m := &SNSMessage{
+		Type:      SNSTypeNotification,
+		MessageID: "22b80b92-fdea-4c2c-8f9d-bdfb0c7bf324",
+		TopicARN:  "arn:aws:sns:us-west-2:123456789012:MyTopic",
+		Subject:   "My First Message",
+		Message:   "Hello world!",
+		Timestamp: "2012-05-02T00:54:06.655Z",
+	}
+	sign(m)
+	if err := v.Verify(m); err != nil {
+		t.Fatal(err)
+	}
+
+	m.Message = "Tampered"
+	if err := v.Verify(m); err != ErrInvalidSignature {
+		t.Errorf("tampered message: got %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestVerifyCertURL(t *testing.T) {
+	for _, certURL := range []string{
+		"http://sns.us-east-1.amazonaws.com/cert.pem",
+		"https://sns.us-east-1.amazonaws.com.evil.com/cert.pem",
+		"https://evil.com/sns.us-east-1.amazonaws.com/cert.pem",
+	} {
+		m := &SNSMessage{Type: SNSTypeNotification, SignatureVersion: "1", Signature: "AAAA", SigningCertURL: certURL}
+		err := (&Verifier{}).Verify(m)
+		if err == nil || !strings.Contains(err.Error(), "not an SNS URL") {
+			t.Errorf("%s: got %v, want certificate URL to be rejected", certURL, err)
+		}
+	}
+}
+
+func TestParseSNS(t *testing.T) {
+	m, err := ParseSNS(strings.NewReader(`{
+  "Type" : "SubscriptionConfirmation",
+  "MessageId" : "165545c9-2a5c-472c-8df2-7ff2be2b3b1b",
+  "Token" : "2336412f37",
+  "TopicArn" : "arn:aws:sns:us-west-2:123456789012:MyTopic",
+  "Message" : "You have chosen to subscribe to the topic.",
+  "SubscribeURL" : "https://sns.us-west-2.amazonaws.com/?Action=ConfirmSubscription",
+  "Timestamp" : "2012-04-26T20:45:04.751Z",
+  "SignatureVersion" : "1",
+  "Signature" : "EXAMPLEpH+DcEwjAPg8O9mY8dReBSwksfg2S7WKQcikcNKWLQjwu6A4VbeS0QHVCkhRS7fUQvi2egU3N858fiTDN6bkkOxYDVrY0Ad8L10Hs3zH81mtnPk5uvvolIC1CXGu43obcgFxeL3khZl8IKvO61GWB6jI9b5+gLPoBc1Q=",
+  "SigningCertURL" : "https://sns.us-west-2.amazonaws.com/SimpleNotificationService-f3ecfb7224c7233fe7bb5f59f96de52f.pem"
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Type != SNSTypeSubscriptionConfirmation || m.Token != "2336412f37" || m.SubscribeURL == "" {
+		t.Errorf("got %+v", m)
+	}
+	want := "Message\nYou have chosen to subscribe to the topic.\nMessageId\n165545c9-2a5c-472c-8df2-7ff2be2b3b1b\n" +
+		"SubscribeURL\nhttps://sns.us-west-2.amazonaws.com/?Action=ConfirmSubscription\nTimestamp\n2012-04-26T20:45:04.751Z\n" +
+		"Token\n2336412f37\nTopicArn\narn:aws:sns:us-west-2:123456789012:MyTopic\nType\nSubscriptionConfirmation\n"
+	if s := m.stringToSign(); s != want {
+		t.Errorf("got string to sign %q, want %q", s, want)
+	}
+}
+
+// A Verifier authenticates SNS messages received outside of a Handler, here in a plain
+// http.HandlerFunc.
+func ExampleVerifier() {
+	v := &Verifier{}
+	http.HandleFunc("/sns", func(w http.ResponseWriter, r *http.Request) {
+		m, err := ParseSNS(r.Body)
+		if err != nil {
+			http.Error(w, "malformed SNS message", http.StatusBadRequest)
+			return
+		}
+		if err := v.Verify(m); err != nil {
+			http.Error(w, "invalid signature", http.StatusForbidden)
+			return
+		}
+		// Handle m.Message.
+	})
+}
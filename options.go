@@ -0,0 +1,162 @@
+package ses
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// A SendOption sets an optional parameter on a send call (SendEmail, SendEmailHTML or
+// SendRawEmail and their Context variants).
+type SendOption func(*sendOptions)
+
+// sendOptions holds the optional parameters of a send call.
+type sendOptions struct {
+	configurationSet string
+	tags             []MessageTag
+	idempotencyKey   string
+
+	sourceArn     string
+	fromArn       string
+	returnPathArn string
+}
+
+// MessageTag is a name/value pair attached to a message. Tags are included in the sending events
+// published through a configuration set, so events can be segmented by campaign, tenant, etc.
+type MessageTag struct {
+	Name  string
+	Value string
+}
+
+// WithConfigurationSet sends the message using the named configuration set, so that its
+// sending events are published to the configuration set's event destinations.
+func WithConfigurationSet(name string) SendOption {
+	return func(o *sendOptions) { o.configurationSet = name }
+}
+
+// WithTag attaches the message tag name=value to the message. It may be given more than once to
+// attach several tags.
+func WithTag(name, value string) SendOption {
+	return func(o *sendOptions) { o.tags = append(o.tags, MessageTag{Name: name, Value: value}) }
+}
+
+// WithSourceArn sends the message using the sending authorization of the identity with the
+// given ARN, which may belong to another account whose identity policy (see PutIdentityPolicy)
+// permits this account to send as it. The message's sender must belong to that identity.
+func WithSourceArn(arn string) SendOption {
+	return func(o *sendOptions) { o.sourceArn = arn }
+}
+
+// WithFromArn is like WithSourceArn, but authorizes the From header of a raw message. It is
+// ignored by SendEmail and SendEmailHTML.
+func WithFromArn(arn string) SendOption {
+	return func(o *sendOptions) { o.fromArn = arn }
+}
+
+// WithReturnPathArn is like WithSourceArn, but authorizes the return path (the address bounces
+// and complaints are sent to).
+func WithReturnPathArn(arn string) SendOption {
+	return func(o *sendOptions) { o.returnPathArn = arn }
+}
+
+// addTo adds the parameters for o to data.
+func (o *sendOptions) addTo(data url.Values) {
+	if o.configurationSet != "" {
+		data.Set("ConfigurationSetName", o.configurationSet)
+	}
+	for i, tag := range o.tags {
+		data.Set(fmt.Sprintf("Tags.member.%d.Name", i+1), tag.Name)
+		data.Set(fmt.Sprintf("Tags.member.%d.Value", i+1), tag.Value)
+	}
+	if o.sourceArn != "" {
+		data.Set("SourceArn", o.sourceArn)
+	}
+	if o.fromArn != "" && data.Get("Action") == "SendRawEmail" {
+		data.Set("FromArn", o.fromArn)
+	}
+	if o.returnPathArn != "" {
+		data.Set("ReturnPathArn", o.returnPathArn)
+	}
+}
+
+// send applies opts to data and performs the send request.
+func (c *Config) send(ctx context.Context, data url.Values, opts []SendOption) (string, error) {
+	return c.sendRaw(ctx, data, nil, opts)
+}
+
+// sendRaw is like send, but streams the raw message returned by raw; see postRaw.
+func (c *Config) sendRaw(ctx context.Context, data url.Values, raw func() (io.Reader, error), opts []SendOption) (string, error) {
+	var o sendOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.addTo(data)
+	// The message is identified for the DuplicateGuard before its envelope sender is rotated.
+	var dupKey []byte
+	if c.DuplicateGuard != nil && raw == nil {
+		dupKey = []byte(data.Encode())
+	}
+	if p := c.ReturnPathPool; p != nil && data.Get("Action") == "SendRawEmail" && data.Get("Source") == "" {
+		if sender := p.Next(o.configurationSet); sender != "" {
+			data.Set("Source", sender)
+		}
+	}
+	if data.Get("Action") == "SendEmail" {
+		if err := c.lintSubject(data.Get("Message.Subject.Data"), &o); err != nil {
+			return "", err
+		}
+	}
+	if err := c.checkGate(ctx, &o); err != nil {
+		return "", err
+	}
+	if c.DryRun {
+		params, err := dryRunParams(data, raw)
+		if err != nil {
+			return "", err
+		}
+		action := data.Get("Action")
+		return sentResponse(action, c.dryRun(DryRunMessage{Action: action, Params: params})), nil
+	}
+
+	if id, ok, err := c.sentBefore(ctx, &o); ok || err != nil {
+		if err != nil {
+			return "", err
+		}
+		return sentResponse(data.Get("Action"), id), nil
+	}
+
+	res, err := c.guarded(dupKey, func() (string, error) { return c.rateLimitedPost(ctx, data, raw) })
+	if err != nil {
+		c.releaseKey(ctx, &o)
+		return res, err
+	}
+	c.recordSent(ctx, &o, messageID(res))
+	return res, nil
+}
+
+// guarded calls send, unless c.DuplicateGuard skips the message identified by key. A nil key
+// is not checked.
+func (c *Config) guarded(key []byte, send func() (string, error)) (string, error) {
+	g := c.DuplicateGuard
+	if g == nil || key == nil {
+		return send()
+	}
+	ok, forget := g.reserve(key)
+	if !ok {
+		return "", ErrDuplicate
+	}
+	res, err := send()
+	if err != nil {
+		forget()
+	}
+	return res, err
+}
+
+// rateLimitedPost waits for c.RateLimiter and performs the send request.
+func (c *Config) rateLimitedPost(ctx context.Context, data url.Values, raw func() (io.Reader, error)) (string, error) {
+	if err := c.waitForRate(ctx); err != nil {
+		return "", err
+	}
+	return c.postRaw(ctx, data, raw)
+}
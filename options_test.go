@@ -0,0 +1,52 @@
+package ses
+
+import "testing"
+
+func TestWithConfigurationSet(t *testing.T) {
+	c, form := testServer(t, `<SendEmailResponse/>`)
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b", WithConfigurationSet("marketing")); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"Action": "SendEmail", "ConfigurationSetName": "marketing"})
+
+	if _, err := c.SendRawEmail([]byte("raw"), WithConfigurationSet("transactional")); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"Action": "SendRawEmail", "ConfigurationSetName": "transactional"})
+
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := (*form)["ConfigurationSetName"]; ok {
+		t.Error("got ConfigurationSetName without WithConfigurationSet")
+	}
+}
+
+func TestWithTag(t *testing.T) {
+	c, form := testServer(t, `<SendRawEmailResponse/>`)
+	_, err := c.SendRawEmail([]byte("raw"), WithTag("campaign", "welcome"), WithTag("tenant", "acme"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{
+		"Tags.member.1.Name":  "campaign",
+		"Tags.member.1.Value": "welcome",
+		"Tags.member.2.Name":  "tenant",
+		"Tags.member.2.Value": "acme",
+	})
+}
+
+func TestWithSourceArn(t *testing.T) {
+	c, form := testServer(t, `<SendRawEmailResponse/>`)
+	const arn = "arn:aws:ses:us-east-1:123456789012:identity/example.com"
+	opts := []SendOption{WithSourceArn(arn), WithFromArn(arn), WithReturnPathArn(arn)}
+	if _, err := c.SendRawEmail([]byte("raw"), opts...); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"SourceArn": arn, "FromArn": arn, "ReturnPathArn": arn})
+
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b", opts...); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"SourceArn": arn, "FromArn": "", "ReturnPathArn": arn})
+}
@@ -0,0 +1,235 @@
+// Package otp emails one-time codes and validates them, with per-recipient rate limiting and
+// resend cooldowns.
+//
+// Codes are held in memory, so a Sender must be shared by everything that sends and validates
+// codes for the same recipients.
+package otp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+var (
+	// ErrCooldown is returned by Send when the previous code for the recipient was sent less
+	// than Cooldown ago.
+	ErrCooldown = errors.New("otp: code was sent too recently")
+
+	// ErrRateLimited is returned by Send when MaxPerHour codes have been sent to the
+	// recipient in the last hour.
+	ErrRateLimited = errors.New("otp: too many codes sent")
+
+	// ErrInvalidCode is returned by Validate when there is no pending code for the recipient
+	// or the code doesn't match.
+	ErrInvalidCode = errors.New("otp: invalid code")
+
+	// ErrExpired is returned by Validate when the recipient's code is older than TTL.
+	ErrExpired = errors.New("otp: code expired")
+
+	// ErrTooManyAttempts is returned by Validate when MaxAttempts wrong codes have been tried.
+	// The code is discarded and a new one must be sent.
+	ErrTooManyAttempts = errors.New("otp: too many attempts")
+)
+
+// timeNow is overridden in tests.
+var timeNow = time.Now
+
+// TemplateData is the data that a Sender's Template is executed with.
+type TemplateData struct {
+	Email   string
+	Code    string
+	Expires time.Time
+}
+
+// A Sender emails one-time codes and validates them. It is safe for concurrent use.
+type Sender struct {
+	SES ses.SenderAPI
+
+	From    string
+	Subject string
+
+	// Template renders the text body of the email from a TemplateData, for example:
+	//
+	//  Your code is {{.Code}}. It expires at {{.Expires}}.
+	Template *template.Template
+
+	// Digits is the length of the generated codes. If zero, 6 is used.
+	Digits int
+
+	// TTL is how long a code remains valid. If zero, 10 minutes is used.
+	TTL time.Duration
+
+	// Cooldown is the minimum time between codes sent to the same recipient. If zero, 1
+	// minute is used.
+	Cooldown time.Duration
+
+	// MaxPerHour is the maximum number of codes sent to the same recipient in an hour. If
+	// zero, 5 is used.
+	MaxPerHour int
+
+	// MaxAttempts is the number of wrong codes that may be tried before the code is
+	// discarded. If zero, 5 is used.
+	MaxAttempts int
+
+	mu         sync.Mutex
+	recipients map[string]*recipient
+	lastPrune  time.Time
+}
+
+type recipient struct {
+	hash     [sha256.Size]byte // of the pending code; zero if none
+	expires  time.Time
+	attempts int
+	sent     []time.Time // in the last hour
+}
+
+func orDefault(v, def int) int {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
+func orDefaultDuration(v, def time.Duration) time.Duration {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
+// key normalizes email so that differently cased addresses share limits.
+func key(email string) string { return strings.ToLower(strings.TrimSpace(email)) }
+
+// Send generates a new code for email and emails it. The new code replaces any pending one
+// once it has been sent; if sending fails, the pending code remains valid and the attempt
+// doesn't count against Cooldown or MaxPerHour.
+func (s *Sender) Send(ctx context.Context, email string) error {
+	code, err := generate(orDefault(s.Digits, 6))
+	if err != nil {
+		return err
+	}
+	now := timeNow()
+	expires := now.Add(orDefaultDuration(s.TTL, 10*time.Minute))
+	var body bytes.Buffer
+	if err := s.Template.Execute(&body, TemplateData{Email: email, Code: code, Expires: expires}); err != nil {
+		return err
+	}
+
+	// Reserve the send in r.sent while sending, so that concurrent sends to the same
+	// recipient are limited too.
+	s.mu.Lock()
+	s.prune(now)
+	if s.recipients == nil {
+		s.recipients = make(map[string]*recipient)
+	}
+	r := s.recipients[key(email)]
+	if r == nil {
+		r = &recipient{}
+		s.recipients[key(email)] = r
+	}
+	r.sent = recent(r.sent, now)
+	switch {
+	case len(r.sent) > 0 && now.Sub(r.sent[len(r.sent)-1]) < orDefaultDuration(s.Cooldown, time.Minute):
+		s.mu.Unlock()
+		return ErrCooldown
+	case len(r.sent) >= orDefault(s.MaxPerHour, 5):
+		s.mu.Unlock()
+		return ErrRateLimited
+	}
+	r.sent = append(r.sent, now)
+	s.mu.Unlock()
+
+	_, err = s.SES.SendEmailContext(ctx, s.From, email, s.Subject, body.String())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		for i := len(r.sent) - 1; i >= 0; i-- {
+			if r.sent[i].Equal(now) {
+				r.sent = append(r.sent[:i], r.sent[i+1:]...)
+				break
+			}
+		}
+		return err
+	}
+	r.hash = sha256.Sum256([]byte(code))
+	r.expires = expires
+	r.attempts = 0
+	return nil
+}
+
+// recent returns the times in sent that are less than an hour before now.
+func recent(sent []time.Time, now time.Time) []time.Time {
+	var r []time.Time
+	for _, t := range sent {
+		if now.Sub(t) < time.Hour {
+			r = append(r, t)
+		}
+	}
+	return r
+}
+
+// prune forgets the recipients that have no pending code and no sends in the last hour, at
+// most once a minute. s.mu must be held.
+func (s *Sender) prune(now time.Time) {
+	if now.Sub(s.lastPrune) < time.Minute {
+		return
+	}
+	s.lastPrune = now
+	for k, r := range s.recipients {
+		if (r.hash == [sha256.Size]byte{} || now.After(r.expires)) && len(recent(r.sent, now)) == 0 {
+			delete(s.recipients, k)
+		}
+	}
+}
+
+// Validate checks code against the pending code for email. A code can only be validated once.
+func (s *Sender) Validate(email, code string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(timeNow())
+
+	r := s.recipients[key(email)]
+	if r == nil || r.hash == ([sha256.Size]byte{}) {
+		return ErrInvalidCode
+	}
+	if timeNow().After(r.expires) {
+		r.hash = [sha256.Size]byte{}
+		return ErrExpired
+	}
+	h := sha256.Sum256([]byte(code))
+	if subtle.ConstantTimeCompare(h[:], r.hash[:]) != 1 {
+		r.attempts++
+		if r.attempts >= orDefault(s.MaxAttempts, 5) {
+			r.hash = [sha256.Size]byte{}
+			return ErrTooManyAttempts
+		}
+		return ErrInvalidCode
+	}
+	r.hash = [sha256.Size]byte{}
+	return nil
+}
+
+// generate returns a random numeric code of the given length.
+func generate(digits int) (string, error) {
+	b := make([]byte, digits)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		b[i] = byte('0' + n.Int64())
+	}
+	return string(b), nil
+}
@@ -0,0 +1,152 @@
+package otp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+func testSender(t *testing.T) (*Sender, func() string) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		body = r.Form.Get("Message.Body.Text.Data")
+		w.Write([]byte("<SendEmailResponse/>"))
+	}))
+	t.Cleanup(srv.Close)
+	s := &Sender{
+		SES:      &ses.Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET"},
+		From:     "noreply@example.com",
+		Subject:  "Your code",
+		Template: template.Must(template.New("").Parse("Your code is {{.Code}}.")),
+	}
+	code := func() string { return regexp.MustCompile(`\d+`).FindString(body) }
+	return s, code
+}
+
+func setTime(t *testing.T, now time.Time) {
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+}
+
+func TestSendValidate(t *testing.T) {
+	s, code := testSender(t)
+	if err := s.Send(context.Background(), "User@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if len(code()) != 6 {
+		t.Fatalf("got code %q, want 6 digits", code())
+	}
+	if err := s.Validate("user@example.com", "wrong"); err != ErrInvalidCode {
+		t.Errorf("wrong code: got %v, want %v", err, ErrInvalidCode)
+	}
+	if err := s.Validate("user@example.com", code()); err != nil {
+		t.Errorf("right code: got %v", err)
+	}
+	if err := s.Validate("user@example.com", code()); err != ErrInvalidCode {
+		t.Errorf("reused code: got %v, want %v", err, ErrInvalidCode)
+	}
+}
+
+func TestExpired(t *testing.T) {
+	s, code := testSender(t)
+	now := time.Now()
+	setTime(t, now)
+	if err := s.Send(context.Background(), "user@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	setTime(t, now.Add(11*time.Minute))
+	if err := s.Validate("user@example.com", code()); err != ErrExpired {
+		t.Errorf("got %v, want %v", err, ErrExpired)
+	}
+}
+
+func TestTooManyAttempts(t *testing.T) {
+	s, code := testSender(t)
+	s.MaxAttempts = 2
+	if err := s.Send(context.Background(), "user@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	s.Validate("user@example.com", "x")
+	if err := s.Validate("user@example.com", "x"); err != ErrTooManyAttempts {
+		t.Errorf("got %v, want %v", err, ErrTooManyAttempts)
+	}
+	if err := s.Validate("user@example.com", code()); err != ErrInvalidCode {
+		t.Errorf("code after too many attempts: got %v, want %v", err, ErrInvalidCode)
+	}
+}
+
+func TestCooldownAndRateLimit(t *testing.T) {
+	s, _ := testSender(t)
+	s.MaxPerHour = 2
+	now := time.Now()
+	setTime(t, now)
+	if err := s.Send(context.Background(), "user@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(context.Background(), "user@example.com"); err != ErrCooldown {
+		t.Errorf("got %v, want %v", err, ErrCooldown)
+	}
+	setTime(t, now.Add(2*time.Minute))
+	if err := s.Send(context.Background(), "user@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	setTime(t, now.Add(4*time.Minute))
+	if err := s.Send(context.Background(), "user@example.com"); err != ErrRateLimited {
+		t.Errorf("got %v, want %v", err, ErrRateLimited)
+	}
+	setTime(t, now.Add(61*time.Minute))
+	if err := s.Send(context.Background(), "user@example.com"); err != nil {
+		t.Errorf("after an hour: got %v", err)
+	}
+}
+
+func TestSendFailureKeepsCode(t *testing.T) {
+	s, code := testSender(t)
+	if err := s.Send(context.Background(), "user@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	first := code()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	good := s.SES
+	s.SES = &ses.Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET"}
+	setTime(t, time.Now().Add(2*time.Minute))
+	if err := s.Send(context.Background(), "user@example.com"); err == nil {
+		t.Fatal("got no error from a failed send")
+	}
+
+	// The failed send neither replaced the code nor started a cooldown.
+	if err := s.Validate("user@example.com", first); err != nil {
+		t.Errorf("first code after a failed send: got %v", err)
+	}
+	s.SES = good
+	if err := s.Send(context.Background(), "user@example.com"); err != nil {
+		t.Errorf("resend after a failed send: got %v", err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	s, _ := testSender(t)
+	now := time.Now()
+	setTime(t, now)
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		if err := s.Send(context.Background(), email); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setTime(t, now.Add(2*time.Hour))
+	s.Validate("c@example.com", "000000")
+	if len(s.recipients) != 0 {
+		t.Errorf("got %d recipients after their codes expired, want 0", len(s.recipients))
+	}
+}
@@ -0,0 +1,110 @@
+package ses
+
+import "context"
+
+// paginate calls page with the NextToken of each page, starting with "" for the first, until
+// page returns an error, returns false, or there are no more pages (page returns a next token
+// of "").
+func paginate(page func(nextToken string) (next string, more bool, err error)) error {
+	token := ""
+	for {
+		next, more, err := page(token)
+		if err != nil || !more || next == "" {
+			return err
+		}
+		token = next
+	}
+}
+
+// ListIdentitiesPages calls fn with each page of the identities returned by ListIdentities,
+// fetching the next page until fn returns false or there are no more pages.
+func (c *Config) ListIdentitiesPages(identityType string, maxItems int, fn func(ListIdentitiesResult) bool) error {
+	return c.ListIdentitiesPagesContext(context.Background(), identityType, maxItems, fn)
+}
+
+// ListIdentitiesPagesContext is like ListIdentitiesPages but uses ctx for the request and any
+// retries.
+func (c *Config) ListIdentitiesPagesContext(ctx context.Context, identityType string, maxItems int, fn func(ListIdentitiesResult) bool) error {
+	return paginate(func(token string) (string, bool, error) {
+		res, err := c.ListIdentitiesContext(ctx, identityType, maxItems, token)
+		if err != nil {
+			return "", false, err
+		}
+		return res.NextToken, fn(res), nil
+	})
+}
+
+// ListConfigurationSetsPages calls fn with each page of the configuration sets returned by
+// ListConfigurationSets, fetching the next page until fn returns false or there are no more
+// pages.
+func (c *Config) ListConfigurationSetsPages(maxItems int, fn func(ListConfigurationSetsResult) bool) error {
+	return c.ListConfigurationSetsPagesContext(context.Background(), maxItems, fn)
+}
+
+// ListConfigurationSetsPagesContext is like ListConfigurationSetsPages but uses ctx for the
+// request and any retries.
+func (c *Config) ListConfigurationSetsPagesContext(ctx context.Context, maxItems int, fn func(ListConfigurationSetsResult) bool) error {
+	return paginate(func(token string) (string, bool, error) {
+		res, err := c.ListConfigurationSetsContext(ctx, maxItems, token)
+		if err != nil {
+			return "", false, err
+		}
+		return res.NextToken, fn(res), nil
+	})
+}
+
+// ListCustomVerificationEmailTemplatesPages calls fn with each page of the templates returned
+// by ListCustomVerificationEmailTemplates, fetching the next page until fn returns false or
+// there are no more pages.
+func (c *Config) ListCustomVerificationEmailTemplatesPages(maxResults int, fn func(ListCustomVerificationEmailTemplatesResult) bool) error {
+	return c.ListCustomVerificationEmailTemplatesPagesContext(context.Background(), maxResults, fn)
+}
+
+// ListCustomVerificationEmailTemplatesPagesContext is like
+// ListCustomVerificationEmailTemplatesPages but uses ctx for the request and any retries.
+func (c *Config) ListCustomVerificationEmailTemplatesPagesContext(ctx context.Context, maxResults int, fn func(ListCustomVerificationEmailTemplatesResult) bool) error {
+	return paginate(func(token string) (string, bool, error) {
+		res, err := c.ListCustomVerificationEmailTemplatesContext(ctx, maxResults, token)
+		if err != nil {
+			return "", false, err
+		}
+		return res.NextToken, fn(res), nil
+	})
+}
+
+// ListSuppressedDestinationsPages calls fn with each page of the suppression list entries
+// returned by ListSuppressedDestinations, fetching the next page until fn returns false or there
+// are no more pages.
+func (c *Config) ListSuppressedDestinationsPages(filter SuppressedDestinationFilter, pageSize int, fn func(ListSuppressedDestinationsResult) bool) error {
+	return c.ListSuppressedDestinationsPagesContext(context.Background(), filter, pageSize, fn)
+}
+
+// ListSuppressedDestinationsPagesContext is like ListSuppressedDestinationsPages but uses ctx
+// for the request and any retries.
+func (c *Config) ListSuppressedDestinationsPagesContext(ctx context.Context, filter SuppressedDestinationFilter, pageSize int, fn func(ListSuppressedDestinationsResult) bool) error {
+	return paginate(func(token string) (string, bool, error) {
+		res, err := c.ListSuppressedDestinationsContext(ctx, filter, pageSize, token)
+		if err != nil {
+			return "", false, err
+		}
+		return res.NextToken, fn(res), nil
+	})
+}
+
+// ListContactsPages calls fn with each page of the contacts returned by ListContacts, fetching
+// the next page until fn returns false or there are no more pages.
+func (c *Config) ListContactsPages(listName string, filter ContactFilter, pageSize int, fn func(ListContactsResult) bool) error {
+	return c.ListContactsPagesContext(context.Background(), listName, filter, pageSize, fn)
+}
+
+// ListContactsPagesContext is like ListContactsPages but uses ctx for the request and any
+// retries.
+func (c *Config) ListContactsPagesContext(ctx context.Context, listName string, filter ContactFilter, pageSize int, fn func(ListContactsResult) bool) error {
+	return paginate(func(token string) (string, bool, error) {
+		res, err := c.ListContactsContext(ctx, listName, filter, pageSize, token)
+		if err != nil {
+			return "", false, err
+		}
+		return res.NextToken, fn(res), nil
+	})
+}
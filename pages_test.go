@@ -0,0 +1,65 @@
+package ses
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListIdentitiesPages(t *testing.T) {
+	var tokens []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		token := r.Form.Get("NextToken")
+		tokens = append(tokens, token)
+		next := map[string]string{"": "p2", "p2": "p3", "p3": ""}[token]
+		fmt.Fprintf(w, `<ListIdentitiesResponse><ListIdentitiesResult><Identities><member>id-%s</member></Identities><NextToken>%s</NextToken></ListIdentitiesResult></ListIdentitiesResponse>`, token, next)
+	}))
+	defer srv.Close()
+	c := &Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET"}
+
+	var ids []string
+	err := c.ListIdentitiesPages("", 1, func(res ListIdentitiesResult) bool {
+		ids = append(ids, res.Identities...)
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ids) != "[id- id-p2 id-p3]" {
+		t.Errorf("got identities %v", ids)
+	}
+
+	tokens = nil
+	err = c.ListIdentitiesPages("", 1, func(res ListIdentitiesResult) bool { return false })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 1 {
+		t.Errorf("fetched %d pages after fn returned false, want 1", len(tokens))
+	}
+}
+
+func TestListSuppressedDestinationsPages(t *testing.T) {
+	c := testV2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("NextToken") == "" {
+			w.Write([]byte(`{"SuppressedDestinationSummaries":[{"EmailAddress":"a@example.com"}],"NextToken":"p2"}`))
+			return
+		}
+		w.Write([]byte(`{"SuppressedDestinationSummaries":[{"EmailAddress":"b@example.com"}]}`))
+	})
+	var emails []string
+	err := c.ListSuppressedDestinationsPages(SuppressedDestinationFilter{}, 0, func(res ListSuppressedDestinationsResult) bool {
+		for _, d := range res.SuppressedDestinationSummaries {
+			emails = append(emails, d.EmailAddress)
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(emails) != "[a@example.com b@example.com]" {
+		t.Errorf("got %v", emails)
+	}
+}
@@ -0,0 +1,381 @@
+// Package queue provides a persistent outbound email queue. Messages are enqueued, optionally
+// for delivery at a later time, saved in a Store, and sent by a dispatcher that respects the
+// account's sending quota, the Config's rate limit and optional per-recipient-domain limits. With a FileStore, queued messages
+// survive process restarts.
+package queue
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"net/mail"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+// Message is a queued email. It is saved in the Store, so unlike ses.Message, its send options
+// are plain fields.
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Text    string
+	HTML    string `json:",omitempty"`
+
+	// Raw, if non-nil, is the complete message to send with SendRawEmail. The fields above are
+	// ignored.
+	Raw []byte `json:",omitempty"`
+
+	ConfigurationSet string           `json:",omitempty"`
+	Tags             []ses.MessageTag `json:",omitempty"`
+
+	// IdempotencyKey is passed to ses.WithIdempotencyKey. If empty, the item ID is used, so
+	// that with an IdempotencyStore on the Config, an item that was sent just before a crash
+	// is not sent again.
+	IdempotencyKey string `json:",omitempty"`
+
+	// Expires, if non-zero, is the time after which the message is dropped with
+	// ses.ErrMessageExpired instead of being sent.
+	Expires time.Time `json:",omitempty"`
+}
+
+// Item is a message in the queue.
+type Item struct {
+	ID      string
+	Message Message
+
+	// SendAt is when the message is next due to be sent.
+	SendAt time.Time
+
+	// Attempts is the number of failed attempts so far, and LastError the error of the last.
+	Attempts  int    `json:",omitempty"`
+	LastError string `json:",omitempty"`
+}
+
+// Result is the outcome of dispatching an item: it was sent (Err is nil), or it was dropped
+// because it failed permanently, expired, or ran out of attempts.
+type Result struct {
+	Item      Item
+	MessageID string
+	Err       error
+}
+
+// A Queue sends the items in its Store when they are due. Its methods are safe for concurrent
+// use. Items are sent while Run is running.
+type Queue struct {
+	SES   ses.SenderAPI
+	Store Store
+
+	// PollInterval is how often the Store is checked for due items. If 0, 1 second is used.
+	PollInterval time.Duration
+
+	// Workers is the number of messages sent concurrently. If less than 1, 1 is used.
+	Workers int
+
+	// MaxAttempts is the number of attempts made to send a message that fails with a
+	// transient error (such as throttling or a network error). If 0, 10 is used.
+	MaxAttempts int
+
+	// DomainLimits, if non-nil, limits the rate of sends to each recipient domain it has a
+	// limiter for, keyed by lowercase domain (such as {"yahoo.com": ses.NewRateLimiter(5, 1)}),
+	// since large mailbox providers throttle senders independently of SES's own limits. The
+	// rates may be changed with SetRate while the queue runs, for example to slow down after
+	// deferrals; a limiter with rate 0 doesn't limit. A message waiting for its domain's limiter
+	// occupies a worker, so Workers should be larger than the number of limited domains.
+	DomainLimits map[string]*ses.RateLimiter
+
+	// RetryDelay is the delay before the first retry of a failed message. It doubles with each
+	// retry, up to an hour. If 0, 1 minute is used.
+	RetryDelay time.Duration
+
+	// OnResult, if non-nil, is called with the result of each item that leaves the queue. It is
+	// called concurrently by the workers, so it must be safe for concurrent use.
+	OnResult func(Result)
+
+	// Logger, if non-nil, receives the errors from the Store, which are otherwise retried at the
+	// next poll without being reported.
+	Logger ses.Logger
+
+	once sync.Once
+	wake chan struct{}
+
+	mu        sync.Mutex
+	quota     ses.GetSendQuotaResult
+	quotaTime time.Time
+	sentSince int
+}
+
+func (q *Queue) init() {
+	q.once.Do(func() { q.wake = make(chan struct{}, 1) })
+}
+
+// Enqueue adds m to the queue, to be sent at sendAt or, if it is zero, as soon as possible. It
+// returns the ID of the new item.
+func (q *Queue) Enqueue(m Message, sendAt time.Time) (string, error) {
+	q.init()
+	b := make([]byte, 16)
+	rand.Read(b)
+	item := Item{ID: hex.EncodeToString(b), Message: m, SendAt: sendAt}
+	if item.SendAt.IsZero() {
+		item.SendAt = time.Now()
+	}
+	if err := q.Store.Put(item); err != nil {
+		return "", err
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return item.ID, nil
+}
+
+// Len returns the number of items in the queue.
+func (q *Queue) Len() (int, error) {
+	items, err := q.Store.List()
+	return len(items), err
+}
+
+// Run sends due items until ctx is done, and then returns ctx's error.
+func (q *Queue) Run(ctx context.Context) error {
+	q.init()
+	interval := q.PollInterval
+	if interval == 0 {
+		interval = time.Second
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		// If the store fails, try again at the next poll.
+		if err := q.dispatch(ctx); err != nil {
+			q.log(ses.LogError, "listing queue failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// dispatch sends the items that are due.
+func (q *Queue) dispatch(ctx context.Context) error {
+	items, err := q.Store.List()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var due []Item
+	for _, item := range items {
+		if !item.SendAt.After(now) {
+			due = append(due, item)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].SendAt.Before(due[j].SendAt) })
+
+	workers := q.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for _, item := range due {
+		if ctx.Err() != nil || !q.quotaAvailable(ctx) {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(item Item) {
+			defer func() { <-sem; wg.Done() }()
+			q.sendItem(ctx, item)
+		}(item)
+	}
+	wg.Wait()
+	return nil
+}
+
+// quotaAvailable reports whether the account's daily sending quota allows another send. The
+// quota is fetched at most once a minute, and sends since are counted against it.
+func (q *Queue) quotaAvailable(ctx context.Context) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if time.Since(q.quotaTime) > time.Minute {
+		quota, err := q.SES.GetSendQuotaContext(ctx)
+		if err != nil {
+			// Without a quota, let SES decide.
+			return true
+		}
+		q.quota, q.quotaTime, q.sentSince = quota, time.Now(), 0
+	}
+	if q.quota.Max24HourSend <= 0 || q.quota.SentLast24Hours+float64(q.sentSince) < q.quota.Max24HourSend {
+		q.sentSince++
+		return true
+	}
+	return false
+}
+
+// sendItem sends item and removes it from the store, or reschedules it after a transient
+// failure.
+func (q *Queue) sendItem(ctx context.Context, item Item) {
+	m := &item.Message
+	var res string
+	var err error
+	if !m.Expires.IsZero() && !time.Now().Before(m.Expires) {
+		err = ses.ErrMessageExpired
+	} else if err = q.waitForDomains(ctx, m); err == nil {
+		res, err = q.send(ctx, item.ID, m)
+	}
+	if err != nil && ctx.Err() != nil {
+		// Shutting down; leave the item for the next run.
+		return
+	}
+
+	maxAttempts := q.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = 10
+	}
+	if err != nil && transient(err) && item.Attempts+1 < maxAttempts {
+		item.Attempts++
+		item.LastError = err.Error()
+		item.SendAt = time.Now().Add(q.retryDelay(item.Attempts))
+		if perr := q.Store.Put(item); perr != nil {
+			q.log(ses.LogError, "rescheduling queue item failed", "id", item.ID, "error", perr)
+		}
+		return
+	}
+
+	if derr := q.Store.Delete(item.ID); derr != nil {
+		q.log(ses.LogError, "removing queue item failed", "id", item.ID, "error", derr)
+		if err == nil {
+			err = derr
+		}
+	}
+	if q.OnResult != nil {
+		q.OnResult(Result{Item: item, MessageID: messageID(res), Err: err})
+	}
+}
+
+// waitForDomains waits for the DomainLimits of m's recipient domains to allow a send.
+func (q *Queue) waitForDomains(ctx context.Context, m *Message) error {
+	if len(q.DomainLimits) == 0 {
+		return nil
+	}
+	for _, domain := range recipientDomains(m) {
+		if l := q.DomainLimits[domain]; l != nil {
+			if err := l.Wait(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// recipientDomains returns the distinct lowercase domains of m's recipients: To, or for a raw
+// message, the addresses in its To and Cc headers.
+func recipientDomains(m *Message) []string {
+	var addrs []string
+	if m.Raw == nil {
+		addrs = []string{m.To}
+	} else if msg, err := mail.ReadMessage(bytes.NewReader(m.Raw)); err == nil {
+		for _, h := range []string{"To", "Cc"} {
+			list, _ := msg.Header.AddressList(h)
+			for _, a := range list {
+				addrs = append(addrs, a.Address)
+			}
+		}
+	}
+	var domains []string
+	seen := make(map[string]bool)
+	for _, addr := range addrs {
+		if a, err := mail.ParseAddress(addr); err == nil {
+			addr = a.Address
+		}
+		i := strings.LastIndex(addr, "@")
+		if i < 0 {
+			continue
+		}
+		domain := strings.ToLower(addr[i+1:])
+		if !seen[domain] {
+			seen[domain] = true
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
+func (q *Queue) send(ctx context.Context, id string, m *Message) (string, error) {
+	var opts []ses.SendOption
+	if m.ConfigurationSet != "" {
+		opts = append(opts, ses.WithConfigurationSet(m.ConfigurationSet))
+	}
+	for _, tag := range m.Tags {
+		opts = append(opts, ses.WithTag(tag.Name, tag.Value))
+	}
+	key := m.IdempotencyKey
+	if key == "" {
+		key = id
+	}
+	opts = append(opts, ses.WithIdempotencyKey(key))
+
+	switch {
+	case m.Raw != nil:
+		return q.SES.SendRawEmailContext(ctx, m.Raw, opts...)
+	case m.HTML != "":
+		return q.SES.SendEmailHTMLContext(ctx, m.From, m.To, m.Subject, m.Text, m.HTML, opts...)
+	default:
+		return q.SES.SendEmailContext(ctx, m.From, m.To, m.Subject, m.Text, opts...)
+	}
+}
+
+func (q *Queue) log(level ses.LogLevel, msg string, keyvals ...interface{}) {
+	if q.Logger != nil {
+		q.Logger.Log(level, msg, keyvals...)
+	}
+}
+
+// retryDelay returns the delay before the given retry (1 for the first).
+func (q *Queue) retryDelay(retry int) time.Duration {
+	d := q.RetryDelay
+	if d == 0 {
+		d = time.Minute
+	}
+	for i := 1; i < retry && d < time.Hour; i++ {
+		d *= 2
+	}
+	if d > time.Hour {
+		d = time.Hour
+	}
+	return d
+}
+
+// transient reports whether err may succeed if the send is retried later.
+func transient(err error) bool {
+	var apiErr *ses.APIError
+	switch {
+	case errors.Is(err, ses.ErrValidation), errors.Is(err, ses.ErrMessageTooLarge), errors.Is(err, ses.ErrDuplicate), errors.Is(err, ses.ErrMessageExpired):
+		return false
+	case errors.Is(err, ses.ErrThrottled), errors.Is(err, ses.ErrQuotaExceeded):
+		return true
+	case errors.As(err, &apiErr):
+		return apiErr.StatusCode >= 500
+	}
+	return true
+}
+
+// messageID returns the MessageId in the response to a send call, or "" if there is none.
+func messageID(res string) string {
+	var v struct {
+		Result struct {
+			MessageID string `xml:"MessageId"`
+		} `xml:",any"`
+	}
+	xml.Unmarshal([]byte(res), &v)
+	return v.Result.MessageID
+}
@@ -0,0 +1,276 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+	"github.com/sourcegraph/go-ses/sestest"
+)
+
+func testQueue(t *testing.T, store Store) (*Queue, *sestest.Server, *[]Result) {
+	srv := sestest.NewServer()
+	t.Cleanup(srv.Close)
+	results := new([]Result)
+	q := &Queue{
+		SES:        srv.Config(),
+		Store:      store,
+		RetryDelay: time.Millisecond,
+		OnResult:   func(r Result) { *results = append(*results, r) },
+	}
+	return q, srv, results
+}
+
+var testMessage = Message{From: "a@example.com", To: "b@example.com", Subject: "s", Text: "b"}
+
+func TestQueue(t *testing.T) {
+	q, srv, results := testQueue(t, &MemoryStore{})
+	m := testMessage
+	m.ConfigurationSet = "cs"
+	m.Tags = []ses.MessageTag{{Name: "campaign", Value: "c1"}}
+	id, err := q.Enqueue(m, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Enqueue(testMessage, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	q.dispatch(context.Background())
+	msgs := srv.Messages()
+	if len(msgs) != 1 || msgs[0].ConfigurationSet != "cs" || msgs[0].Tags["campaign"] != "c1" {
+		t.Fatalf("got messages %+v, want the due message", msgs)
+	}
+	if len(*results) != 1 || (*results)[0].Item.ID != id || (*results)[0].MessageID != msgs[0].ID || (*results)[0].Err != nil {
+		t.Errorf("got results %+v", *results)
+	}
+	if n, _ := q.Len(); n != 1 {
+		t.Errorf("got Len %d, want 1 (the scheduled message)", n)
+	}
+}
+
+func TestQueueRetry(t *testing.T) {
+	q, srv, results := testQueue(t, &MemoryStore{})
+	q.Enqueue(testMessage, time.Time{})
+
+	// The quota check and the send are throttled.
+	srv.Throttle(2)
+	q.dispatch(context.Background())
+	items, _ := q.Store.List()
+	if len(items) != 1 || items[0].Attempts != 1 || items[0].LastError == "" {
+		t.Fatalf("got items %+v, want one rescheduled item", items)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	q.dispatch(context.Background())
+	if len(srv.Messages()) != 1 || len(*results) != 1 || (*results)[0].Err != nil {
+		t.Errorf("got %d messages and results %+v, want the message sent", len(srv.Messages()), *results)
+	}
+}
+
+func TestQueueMaxAttempts(t *testing.T) {
+	q, srv, results := testQueue(t, &MemoryStore{})
+	q.MaxAttempts = 1
+	q.Enqueue(testMessage, time.Time{})
+
+	srv.Throttle(2)
+	q.dispatch(context.Background())
+	var apiErr *ses.APIError
+	if len(*results) != 1 || !errors.As((*results)[0].Err, &apiErr) || apiErr.Code != "Throttling" {
+		t.Fatalf("got results %+v, want Throttling error", *results)
+	}
+	if n, _ := q.Len(); n != 0 {
+		t.Errorf("got Len %d, want 0", n)
+	}
+}
+
+func TestQueuePermanentFailure(t *testing.T) {
+	q, srv, results := testQueue(t, &MemoryStore{})
+	srv.RequireVerified(true)
+	q.Enqueue(testMessage, time.Time{})
+	expired := testMessage
+	expired.Expires = time.Now().Add(-time.Second)
+	q.Enqueue(expired, time.Time{})
+
+	q.dispatch(context.Background())
+	if len(*results) != 2 {
+		t.Fatalf("got results %+v, want 2", *results)
+	}
+	for _, r := range *results {
+		var apiErr *ses.APIError
+		if !errors.Is(r.Err, ses.ErrMessageExpired) && !(errors.As(r.Err, &apiErr) && apiErr.Code == "MessageRejected") {
+			t.Errorf("got error %v, want ErrMessageExpired or MessageRejected", r.Err)
+		}
+	}
+	if n, _ := q.Len(); n != 0 {
+		t.Errorf("got Len %d, want 0", n)
+	}
+}
+
+func TestQueueQuota(t *testing.T) {
+	q, srv, _ := testQueue(t, &MemoryStore{})
+	srv.SetQuota(1, 100)
+	q.Enqueue(testMessage, time.Time{})
+	q.Enqueue(testMessage, time.Time{})
+
+	q.dispatch(context.Background())
+	if len(srv.Messages()) != 1 {
+		t.Errorf("got %d messages, want 1", len(srv.Messages()))
+	}
+	if n, _ := q.Len(); n != 1 {
+		t.Errorf("got Len %d, want 1 (held until the quota allows)", n)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q, srv, _ := testQueue(t, &FileStore{Dir: dir})
+	raw := []byte("From: a@example.com\r\nTo: b@example.com\r\nSubject: s\r\n\r\nb")
+	if _, err := q.Enqueue(Message{Raw: raw}, time.Now().Add(50*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new store on the same directory, as after a restart, has the item.
+	q.Store = &FileStore{Dir: dir}
+	items, err := q.Store.List()
+	if err != nil || len(items) != 1 || string(items[0].Message.Raw) != string(raw) {
+		t.Fatalf("got items %+v, %v", items, err)
+	}
+
+	done := make(chan Result, 1)
+	q.OnResult = func(r Result) { done <- r }
+	q.PollInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx)
+	select {
+	case r := <-done:
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the message to be sent")
+	}
+	if msgs := srv.Messages(); len(msgs) != 1 || string(msgs[0].Raw) != string(raw) {
+		t.Fatalf("got messages %+v, want the raw message", msgs)
+	}
+	if n, _ := q.Len(); n != 0 {
+		t.Errorf("got Len %d, want 0", n)
+	}
+}
+
+// failingStore is a MemoryStore whose Delete fails.
+type failingStore struct {
+	MemoryStore
+}
+
+func (s *failingStore) Delete(id string) error { return errors.New("disk full") }
+
+func TestQueueStoreError(t *testing.T) {
+	q, _, results := testQueue(t, &failingStore{})
+	var logged []string
+	q.Logger = ses.LoggerFunc(func(level ses.LogLevel, msg string, keyvals ...interface{}) {
+		logged = append(logged, msg)
+	})
+	if _, err := q.Enqueue(testMessage, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.dispatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(*results) != 1 || (*results)[0].Err == nil {
+		t.Errorf("got results %+v, want the Delete error", *results)
+	}
+	if len(logged) != 1 || logged[0] != "removing queue item failed" {
+		t.Errorf("got log messages %q", logged)
+	}
+}
+
+func TestFileStoreCorrupt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var logged []string
+	s := &FileStore{Dir: dir, Logger: ses.LoggerFunc(func(level ses.LogLevel, msg string, keyvals ...interface{}) {
+		logged = append(logged, msg)
+	})}
+	if err := s.Put(Item{ID: "good", Message: testMessage}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "bad.json"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	items, err := s.List()
+	if err != nil || len(items) != 1 || items[0].ID != "good" {
+		t.Fatalf("got items %+v, %v, want only the good item", items, err)
+	}
+	if len(logged) != 1 {
+		t.Errorf("got log messages %q, want 1", logged)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".bad-bad.json")); err != nil {
+		t.Errorf("corrupt file was not set aside: %v", err)
+	}
+
+	// The file set aside is not listed or logged again.
+	if items, _ := s.List(); len(items) != 1 || len(logged) != 1 {
+		t.Errorf("got items %+v and log messages %q on second List", items, logged)
+	}
+}
+
+func TestQueueDomainLimits(t *testing.T) {
+	q, srv, _ := testQueue(t, &MemoryStore{})
+	var mu sync.Mutex
+	var results []Result
+	q.OnResult = func(r Result) {
+		mu.Lock()
+		results = append(results, r)
+		mu.Unlock()
+	}
+	q.Workers = 4
+	q.DomainLimits = map[string]*ses.RateLimiter{"slow.example.com": ses.NewRateLimiter(20, 1)}
+	for i := 0; i < 3; i++ {
+		m := testMessage
+		m.To = "user@Slow.Example.com"
+		if _, err := q.Enqueue(m, time.Time{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := q.Enqueue(testMessage, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if err := q.dispatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// The limiter allows one send at once and then one every 50ms.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 sends to the limited domain took %s, want at least 100ms", elapsed)
+	}
+	if len(results) != 4 || len(srv.Messages()) != 4 {
+		t.Errorf("got %d results and %d messages, want 4", len(results), len(srv.Messages()))
+	}
+}
+
+func TestRecipientDomains(t *testing.T) {
+	raw := []byte("To: A <a@One.example.com>, b@two.example.com\r\nCc: c@one.example.com\r\nSubject: s\r\n\r\nb")
+	got := recipientDomains(&Message{Raw: raw})
+	if want := []string{"one.example.com", "two.example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,146 @@
+package queue
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+// A Store saves the items of a Queue. Implementations must be safe for concurrent use.
+type Store interface {
+	// Put adds item, or replaces the item with the same ID.
+	Put(item Item) error
+
+	// Delete removes the item with the given ID, if there is one.
+	Delete(id string) error
+
+	// List returns all the items.
+	List() ([]Item, error)
+}
+
+// MemoryStore is a Store that keeps items in memory, so they are lost when the process exits.
+type MemoryStore struct {
+	mu    sync.Mutex
+	items map[string]Item
+}
+
+func (s *MemoryStore) Put(item Item) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.items == nil {
+		s.items = make(map[string]Item)
+	}
+	s.items[item.ID] = item
+	return nil
+}
+
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.items, id)
+	return nil
+}
+
+func (s *MemoryStore) List() ([]Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := make([]Item, 0, len(s.items))
+	for _, item := range s.items {
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// FileStore is a Store that saves each item as a JSON file in the directory Dir, which must
+// exist. Items are written atomically, so a crash leaves either the old or the new version.
+//
+// List skips files that can't be read. A file that can't be decoded is renamed with a ".bad-"
+// prefix, so that it is kept for inspection but no longer listed.
+type FileStore struct {
+	Dir string
+
+	// Logger, if non-nil, receives a message for each file that List skips.
+	Logger ses.Logger
+
+	mu sync.Mutex
+}
+
+func (s *FileStore) Put(item Item) error {
+	b, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := ioutil.TempFile(s.Dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), s.path(item.ID))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+func (s *FileStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(s.path(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) List() ([]Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	infos, err := ioutil.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var items []Item
+	for _, fi := range infos {
+		if !strings.HasSuffix(fi.Name(), ".json") || strings.HasPrefix(fi.Name(), ".") {
+			continue
+		}
+		name := filepath.Join(s.Dir, fi.Name())
+		b, err := ioutil.ReadFile(name)
+		if err != nil {
+			s.log("skipping unreadable queue file", "file", name, "error", err)
+			continue
+		}
+		var item Item
+		if err := json.Unmarshal(b, &item); err != nil {
+			s.log("skipping corrupt queue file", "file", name, "error", err)
+			os.Rename(name, filepath.Join(s.Dir, ".bad-"+fi.Name()))
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (s *FileStore) log(msg string, keyvals ...interface{}) {
+	if s.Logger != nil {
+		s.Logger.Log(ses.LogError, msg, keyvals...)
+	}
+}
+
+func (s *FileStore) path(id string) string {
+	return filepath.Join(s.Dir, filepath.Base(id)+".json")
+}
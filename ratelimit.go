@@ -0,0 +1,143 @@
+package ses
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rate of send calls made with a Config, using a token bucket. It is safe
+// for concurrent use, and a single RateLimiter may be shared by several Configs (for example,
+// copies of the same Config used by a pool of workers) to limit their combined rate.
+type RateLimiter struct {
+	discoverMu sync.Mutex // serializes discovery of the rate from GetSendQuota
+
+	mu       sync.Mutex
+	rate     float64 // tokens per second; 0 means not yet known
+	burst    float64
+	tokens   float64
+	last     time.Time
+	disabled bool
+}
+
+// NewRateLimiter returns a RateLimiter that allows rate sends per second with bursts of up to
+// burst sends. If burst is less than 1, it is 1. If rate is 0, the rate is discovered from the
+// MaxSendRate returned by GetSendQuota the first time the limiter is used by a Config.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// Rate returns the limiter's rate in sends per second, or 0 if it has not been discovered yet.
+func (l *RateLimiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// SetRate changes the limiter's rate to rate sends per second.
+func (l *RateLimiter) SetRate(rate float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(time.Now())
+	l.rate = rate
+}
+
+// SetBurst changes the maximum burst of the limiter to burst sends. If burst is less than 1,
+// it is 1.
+func (l *RateLimiter) SetBurst(burst int) {
+	if burst < 1 {
+		burst = 1
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(time.Now())
+	l.burst = float64(burst)
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// SetEnabled enables or disables the limiter. A disabled limiter doesn't block sends or
+// discover its rate. A new limiter is enabled.
+func (l *RateLimiter) SetEnabled(enabled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(time.Now())
+	l.disabled = !enabled
+}
+
+// enabled reports whether the limiter is enabled.
+func (l *RateLimiter) enabled() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return !l.disabled
+}
+
+// refill adds the tokens earned since the last refill. l.mu must be held.
+func (l *RateLimiter) refill(now time.Time) {
+	if !l.last.IsZero() && l.rate > 0 {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+}
+
+// Wait blocks until a send is allowed or ctx is done. A limiter whose rate is not yet known,
+// or that is disabled, doesn't block.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	if l.rate <= 0 || l.disabled {
+		l.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	l.refill(now)
+	l.tokens--
+	if l.tokens >= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	// Reserve the token now so that waiters are served in order, and give it back if ctx is
+	// done first.
+	d := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// waitForRate waits for c.RateLimiter (if any) to allow a send, discovering its rate from
+// GetSendQuota first if necessary.
+func (c *Config) waitForRate(ctx context.Context) error {
+	l := c.RateLimiter
+	if l == nil || !l.enabled() {
+		return nil
+	}
+	if l.Rate() == 0 {
+		l.discoverMu.Lock()
+		if l.Rate() == 0 {
+			q, err := c.GetSendQuotaContext(ctx)
+			if err != nil {
+				l.discoverMu.Unlock()
+				return err
+			}
+			l.SetRate(q.MaxSendRate)
+		}
+		l.discoverMu.Unlock()
+	}
+	return l.Wait(ctx)
+}
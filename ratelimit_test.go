@@ -0,0 +1,73 @@
+package ses
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := NewRateLimiter(100, 2)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.Wait(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 2 sends are allowed immediately by the burst, and the other 4 take 10ms each.
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("6 sends took %s, want at least 40ms", elapsed)
+	}
+}
+
+func TestRateLimiterContextCanceled(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRateLimiterDiscovery(t *testing.T) {
+	var mu sync.Mutex
+	actions := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		mu.Lock()
+		actions[r.Form.Get("Action")]++
+		mu.Unlock()
+		if r.Form.Get("Action") == "GetSendQuota" {
+			w.Write([]byte(`<GetSendQuotaResponse><GetSendQuotaResult><MaxSendRate>14</MaxSendRate></GetSendQuotaResult></GetSendQuotaResponse>`))
+			return
+		}
+		w.Write([]byte(`<SendEmailResponse/>`))
+	}))
+	defer srv.Close()
+
+	c := Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET", RateLimiter: NewRateLimiter(0, 10)}
+	for i := 0; i < 3; i++ {
+		if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if rate := c.RateLimiter.Rate(); rate != 14 {
+		t.Errorf("got rate %v, want 14", rate)
+	}
+	if actions["GetSendQuota"] != 1 || actions["SendEmail"] != 3 {
+		t.Errorf("got requests %v, want 1 GetSendQuota and 3 SendEmail", actions)
+	}
+}
@@ -0,0 +1,131 @@
+package ses
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+)
+
+// MaxRawMessageSize is the largest raw message, in bytes, that SendRawEmail accepts.
+const MaxRawMessageSize = 10 << 20
+
+// ErrMessageTooLarge is returned (possibly wrapped) for raw messages larger than
+// MaxRawMessageSize.
+var ErrMessageTooLarge = errors.New("ses: message too large")
+
+func messageTooLarge(size int64) error {
+	return fmt.Errorf("%w: %d bytes exceeds the limit of %d bytes", ErrMessageTooLarge, size, MaxRawMessageSize)
+}
+
+// SendRawEmailReader is like SendRawEmailContext, but reads the raw message from r and
+// streams it to SES as it is base64-encoded, without holding it in memory.
+//
+// If the size of the message can be determined in advance (r is a *bytes.Reader,
+// *strings.Reader, *bytes.Buffer or a regular *os.File), a message larger than
+// MaxRawMessageSize is rejected before any request is made. Otherwise the request is aborted
+// once the limit is exceeded. In both cases the error wraps ErrMessageTooLarge.
+//
+// Retries are only possible if r is an io.Seeker, in which case it is rewound to its
+// original offset before each attempt.
+func (c *Config) SendRawEmailReader(ctx context.Context, r io.Reader, opts ...SendOption) (string, error) {
+	if size, ok := readerSize(r); ok && size > MaxRawMessageSize {
+		return "", messageTooLarge(size)
+	}
+
+	sender := c
+	var raw func() (io.Reader, error)
+	if s, ok := r.(io.Seeker); ok {
+		start, err := s.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return "", err
+		}
+		raw = func() (io.Reader, error) {
+			_, err := s.Seek(start, io.SeekStart)
+			return r, err
+		}
+	} else {
+		noRetry := *c
+		noRetry.RetryPolicy = nil
+		sender = &noRetry
+		raw = func() (io.Reader, error) { return r, nil }
+	}
+
+	data := make(url.Values)
+	data.Add("Action", "SendRawEmail")
+
+	return sender.sendRaw(ctx, data, raw, opts)
+}
+
+// readerSize returns the number of bytes remaining in r, if that can be determined without
+// reading it.
+func readerSize(r io.Reader) (int64, bool) {
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len()), true
+	case *os.File:
+		fi, err := r.Stat()
+		if err != nil || !fi.Mode().IsRegular() {
+			return 0, false
+		}
+		off, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		return fi.Size() - off, true
+	}
+	return 0, false
+}
+
+// encodeRawMessage returns a reader of the form-encoded RawMessage.Data parameter (preceded by
+// "&") for the message read from r. The message is base64-encoded as it is read, and reading
+// fails with an error wrapping ErrMessageTooLarge if it exceeds MaxRawMessageSize.
+func encodeRawMessage(r io.Reader) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		w := bufio.NewWriter(pw)
+		w.WriteString("&RawMessage.Data=")
+		enc := base64.NewEncoder(base64.StdEncoding, formEscaper{w})
+		n, err := io.Copy(enc, io.LimitReader(r, MaxRawMessageSize+1))
+		if err == nil && n > MaxRawMessageSize {
+			err = fmt.Errorf("%w: exceeds the limit of %d bytes", ErrMessageTooLarge, MaxRawMessageSize)
+		}
+		if err == nil {
+			err = enc.Close()
+		}
+		if err == nil {
+			err = w.Flush()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// formEscaper form-encodes the base64 written to it. Only '+', '/' and '=' need escaping.
+type formEscaper struct {
+	w *bufio.Writer
+}
+
+func (e formEscaper) Write(p []byte) (int, error) {
+	for _, b := range p {
+		var err error
+		switch b {
+		case '+':
+			_, err = e.w.WriteString("%2B")
+		case '/':
+			_, err = e.w.WriteString("%2F")
+		case '=':
+			_, err = e.w.WriteString("%3D")
+		default:
+			err = e.w.WriteByte(b)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
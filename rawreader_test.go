@@ -0,0 +1,83 @@
+package ses
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendRawEmailReader(t *testing.T) {
+	c, form := testServer(t, `<SendRawEmailResponse/>`)
+	msg := "Subject: hi\r\n\r\n" + strings.Repeat("\xff\xfe\xfd binary data ", 1000)
+
+	// A plain io.Reader, so the size isn't known in advance.
+	r := io.MultiReader(strings.NewReader(msg))
+	if _, err := c.SendRawEmailReader(context.Background(), r, WithConfigurationSet("cs")); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"Action": "SendRawEmail", "ConfigurationSetName": "cs", "AWSAccessKeyId": "AKID"})
+	raw, err := base64.StdEncoding.DecodeString(form.Get("RawMessage.Data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != msg {
+		t.Errorf("got message of %d bytes, want %d bytes", len(raw), len(msg))
+	}
+}
+
+func TestSendRawEmailReaderRetry(t *testing.T) {
+	var n int
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		n++
+		bodies = append(bodies, r.Form.Get("RawMessage.Data"))
+		if n == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("<SendRawEmailResponse/>"))
+	}))
+	defer srv.Close()
+
+	c := Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET", RetryPolicy: &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}}
+	if _, err := c.SendRawEmailReader(context.Background(), strings.NewReader("Subject: hi\r\n\r\nbody")); err != nil {
+		t.Fatal(err)
+	}
+	if len(bodies) != 2 || bodies[0] != bodies[1] || bodies[1] == "" {
+		t.Errorf("got bodies %q, want the same message sent twice", bodies)
+	}
+}
+
+func TestSendRawEmailReaderTooLarge(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		io.Copy(ioutil.Discard, r.Body)
+	}))
+	defer srv.Close()
+	c := Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET"}
+	big := make([]byte, MaxRawMessageSize+1)
+
+	if _, err := c.SendRawEmailReader(context.Background(), bytes.NewReader(big)); !errors.Is(err, ErrMessageTooLarge) {
+		t.Errorf("sized reader: got %v, want %v", err, ErrMessageTooLarge)
+	}
+	if _, err := c.SendRawEmail(big); !errors.Is(err, ErrMessageTooLarge) {
+		t.Errorf("SendRawEmail: got %v, want %v", err, ErrMessageTooLarge)
+	}
+	if requests != 0 {
+		t.Errorf("got %d requests for messages of known size, want 0", requests)
+	}
+
+	if _, err := c.SendRawEmailReader(context.Background(), io.MultiReader(bytes.NewReader(big))); !errors.Is(err, ErrMessageTooLarge) {
+		t.Errorf("unsized reader: got %v, want %v", err, ErrMessageTooLarge)
+	}
+}
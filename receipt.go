@@ -0,0 +1,496 @@
+package ses
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ReceiptRuleSetMetadata describes a receipt rule set.
+type ReceiptRuleSetMetadata struct {
+	Name             string
+	CreatedTimestamp time.Time
+}
+
+// ReceiptRule specifies what SES does with the inbound mail for a set of recipients. Its actions
+// are performed in order.
+type ReceiptRule struct {
+	Name    string
+	Enabled bool
+
+	// TlsPolicy is "Require" to bounce mail not received over TLS, or "Optional" (the default).
+	TlsPolicy string
+
+	// Recipients are the addresses and domains the rule applies to. If empty, it applies to all
+	// recipients in the verified domains of the account.
+	Recipients []string `xml:"Recipients>member"`
+
+	Actions     []ReceiptAction `xml:"Actions>member"`
+	ScanEnabled bool
+}
+
+// ReceiptAction is an action of a receipt rule. Exactly one of its fields must be set.
+type ReceiptAction struct {
+	S3Action        *S3Action
+	SNSAction       *SNSAction
+	LambdaAction    *LambdaAction
+	BounceAction    *BounceAction
+	StopAction      *StopAction
+	AddHeaderAction *AddHeaderAction
+	WorkmailAction  *WorkmailAction
+}
+
+// S3Action saves the message to an S3 bucket, optionally notifying an SNS topic.
+type S3Action struct {
+	BucketName      string
+	ObjectKeyPrefix string
+	KmsKeyArn       string
+	TopicArn        string
+}
+
+// SNSAction publishes the message to an SNS topic.
+type SNSAction struct {
+	TopicArn string
+
+	// Encoding is "UTF-8" (the default) or "Base64".
+	Encoding string
+}
+
+// LambdaAction invokes a Lambda function, optionally notifying an SNS topic.
+type LambdaAction struct {
+	FunctionArn string
+
+	// InvocationType is "Event" (the default) to invoke the function asynchronously, or
+	// "RequestResponse" to wait for it, so that it can stop the rule set.
+	InvocationType string
+
+	TopicArn string
+}
+
+// BounceAction rejects the message with a bounce sent from Sender.
+type BounceAction struct {
+	SmtpReplyCode string
+	StatusCode    string
+	Message       string
+	Sender        string
+	TopicArn      string
+}
+
+// StopAction stops the evaluation of the rule set.
+type StopAction struct {
+	// Scope is "RuleSet".
+	Scope    string
+	TopicArn string
+}
+
+// AddHeaderAction adds a header to the message.
+type AddHeaderAction struct {
+	HeaderName  string
+	HeaderValue string
+}
+
+// WorkmailAction delivers the message to Amazon WorkMail.
+type WorkmailAction struct {
+	OrganizationArn string
+	TopicArn        string
+}
+
+// addTo adds the parameters for r to data, prefixed by prefix.
+func (r ReceiptRule) addTo(data url.Values, prefix string) {
+	data.Add(prefix+".Name", r.Name)
+	data.Add(prefix+".Enabled", strconv.FormatBool(r.Enabled))
+	data.Add(prefix+".ScanEnabled", strconv.FormatBool(r.ScanEnabled))
+	if r.TlsPolicy != "" {
+		data.Add(prefix+".TlsPolicy", r.TlsPolicy)
+	}
+	addMembers(data, prefix+".Recipients", r.Recipients)
+	for i, a := range r.Actions {
+		a.addTo(data, fmt.Sprintf("%s.Actions.member.%d", prefix, i+1))
+	}
+}
+
+// addTo adds the non-empty fields of the action set in a to data, prefixed by prefix.
+func (a ReceiptAction) addTo(data url.Values, prefix string) {
+	var action string
+	var fields map[string]string
+	switch {
+	case a.S3Action != nil:
+		action, fields = "S3Action", map[string]string{
+			"BucketName":      a.S3Action.BucketName,
+			"ObjectKeyPrefix": a.S3Action.ObjectKeyPrefix,
+			"KmsKeyArn":       a.S3Action.KmsKeyArn,
+			"TopicArn":        a.S3Action.TopicArn,
+		}
+	case a.SNSAction != nil:
+		action, fields = "SNSAction", map[string]string{
+			"TopicArn": a.SNSAction.TopicArn,
+			"Encoding": a.SNSAction.Encoding,
+		}
+	case a.LambdaAction != nil:
+		action, fields = "LambdaAction", map[string]string{
+			"FunctionArn":    a.LambdaAction.FunctionArn,
+			"InvocationType": a.LambdaAction.InvocationType,
+			"TopicArn":       a.LambdaAction.TopicArn,
+		}
+	case a.BounceAction != nil:
+		action, fields = "BounceAction", map[string]string{
+			"SmtpReplyCode": a.BounceAction.SmtpReplyCode,
+			"StatusCode":    a.BounceAction.StatusCode,
+			"Message":       a.BounceAction.Message,
+			"Sender":        a.BounceAction.Sender,
+			"TopicArn":      a.BounceAction.TopicArn,
+		}
+	case a.StopAction != nil:
+		action, fields = "StopAction", map[string]string{
+			"Scope":    a.StopAction.Scope,
+			"TopicArn": a.StopAction.TopicArn,
+		}
+	case a.AddHeaderAction != nil:
+		action, fields = "AddHeaderAction", map[string]string{
+			"HeaderName":  a.AddHeaderAction.HeaderName,
+			"HeaderValue": a.AddHeaderAction.HeaderValue,
+		}
+	case a.WorkmailAction != nil:
+		action, fields = "WorkmailAction", map[string]string{
+			"OrganizationArn": a.WorkmailAction.OrganizationArn,
+			"TopicArn":        a.WorkmailAction.TopicArn,
+		}
+	}
+	for k, v := range fields {
+		if v != "" {
+			data.Add(prefix+"."+action+"."+k, v)
+		}
+	}
+}
+
+// CreateReceiptRuleSet creates an empty receipt rule set.
+func (c *Config) CreateReceiptRuleSet(name string) error {
+	return c.CreateReceiptRuleSetContext(context.Background(), name)
+}
+
+// CreateReceiptRuleSetContext is like CreateReceiptRuleSet but uses ctx for the request and any
+// retries.
+func (c *Config) CreateReceiptRuleSetContext(ctx context.Context, name string) error {
+	data := make(url.Values)
+	data.Add("Action", "CreateReceiptRuleSet")
+	data.Add("RuleSetName", name)
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+// CloneReceiptRuleSet creates a receipt rule set with a copy of the rules of original.
+func (c *Config) CloneReceiptRuleSet(name, original string) error {
+	return c.CloneReceiptRuleSetContext(context.Background(), name, original)
+}
+
+// CloneReceiptRuleSetContext is like CloneReceiptRuleSet but uses ctx for the request and any
+// retries.
+func (c *Config) CloneReceiptRuleSetContext(ctx context.Context, name, original string) error {
+	data := make(url.Values)
+	data.Add("Action", "CloneReceiptRuleSet")
+	data.Add("RuleSetName", name)
+	data.Add("OriginalRuleSetName", original)
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+// DeleteReceiptRuleSet deletes a receipt rule set and its rules. The active rule set can't be
+// deleted.
+func (c *Config) DeleteReceiptRuleSet(name string) error {
+	return c.DeleteReceiptRuleSetContext(context.Background(), name)
+}
+
+// DeleteReceiptRuleSetContext is like DeleteReceiptRuleSet but uses ctx for the request and any
+// retries.
+func (c *Config) DeleteReceiptRuleSetContext(ctx context.Context, name string) error {
+	data := make(url.Values)
+	data.Add("Action", "DeleteReceiptRuleSet")
+	data.Add("RuleSetName", name)
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+type ListReceiptRuleSetsResult struct {
+	RuleSets []ReceiptRuleSetMetadata `xml:"RuleSets>member"`
+
+	// NextToken is passed to ListReceiptRuleSets to fetch the next page. It is empty on the
+	// last page.
+	NextToken string
+}
+
+type ListReceiptRuleSetsResponse struct {
+	ListReceiptRuleSetsResult ListReceiptRuleSetsResult
+}
+
+// ListReceiptRuleSets returns a page of the receipt rule sets for the account. nextToken is the
+// NextToken from the previous page, or empty for the first page.
+func (c *Config) ListReceiptRuleSets(nextToken string) (ListReceiptRuleSetsResult, error) {
+	return c.ListReceiptRuleSetsContext(context.Background(), nextToken)
+}
+
+// ListReceiptRuleSetsContext is like ListReceiptRuleSets but uses ctx for the request and any
+// retries.
+func (c *Config) ListReceiptRuleSetsContext(ctx context.Context, nextToken string) (ListReceiptRuleSetsResult, error) {
+	data := make(url.Values)
+	data.Add("Action", "ListReceiptRuleSets")
+	if nextToken != "" {
+		data.Add("NextToken", nextToken)
+	}
+
+	res := ListReceiptRuleSetsResponse{}
+	err := c.call(ctx, "GET", data, &res)
+	return res.ListReceiptRuleSetsResult, err
+}
+
+type DescribeReceiptRuleSetResult struct {
+	Metadata ReceiptRuleSetMetadata
+	Rules    []ReceiptRule `xml:"Rules>member"`
+}
+
+type DescribeReceiptRuleSetResponse struct {
+	DescribeReceiptRuleSetResult DescribeReceiptRuleSetResult
+}
+
+// DescribeReceiptRuleSet returns a receipt rule set and its rules, in order.
+func (c *Config) DescribeReceiptRuleSet(name string) (DescribeReceiptRuleSetResult, error) {
+	return c.DescribeReceiptRuleSetContext(context.Background(), name)
+}
+
+// DescribeReceiptRuleSetContext is like DescribeReceiptRuleSet but uses ctx for the request and
+// any retries.
+func (c *Config) DescribeReceiptRuleSetContext(ctx context.Context, name string) (DescribeReceiptRuleSetResult, error) {
+	data := make(url.Values)
+	data.Add("Action", "DescribeReceiptRuleSet")
+	data.Add("RuleSetName", name)
+
+	res := DescribeReceiptRuleSetResponse{}
+	err := c.call(ctx, "GET", data, &res)
+	return res.DescribeReceiptRuleSetResult, err
+}
+
+type DescribeActiveReceiptRuleSetResponse struct {
+	DescribeActiveReceiptRuleSetResult DescribeReceiptRuleSetResult
+}
+
+// DescribeActiveReceiptRuleSet returns the active receipt rule set and its rules. If no rule
+// set is active, the result is empty.
+func (c *Config) DescribeActiveReceiptRuleSet() (DescribeReceiptRuleSetResult, error) {
+	return c.DescribeActiveReceiptRuleSetContext(context.Background())
+}
+
+// DescribeActiveReceiptRuleSetContext is like DescribeActiveReceiptRuleSet but uses ctx for the
+// request and any retries.
+func (c *Config) DescribeActiveReceiptRuleSetContext(ctx context.Context) (DescribeReceiptRuleSetResult, error) {
+	data := make(url.Values)
+	data.Add("Action", "DescribeActiveReceiptRuleSet")
+
+	res := DescribeActiveReceiptRuleSetResponse{}
+	err := c.call(ctx, "GET", data, &res)
+	return res.DescribeActiveReceiptRuleSetResult, err
+}
+
+// SetActiveReceiptRuleSet makes the named receipt rule set the one applied to inbound mail. If
+// name is empty, no rule set is active and inbound mail is not received.
+func (c *Config) SetActiveReceiptRuleSet(name string) error {
+	return c.SetActiveReceiptRuleSetContext(context.Background(), name)
+}
+
+// SetActiveReceiptRuleSetContext is like SetActiveReceiptRuleSet but uses ctx for the request
+// and any retries.
+func (c *Config) SetActiveReceiptRuleSetContext(ctx context.Context, name string) error {
+	data := make(url.Values)
+	data.Add("Action", "SetActiveReceiptRuleSet")
+	if name != "" {
+		data.Add("RuleSetName", name)
+	}
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+// ReorderReceiptRuleSet reorders the rules of a receipt rule set. ruleNames must list all of
+// its rules.
+func (c *Config) ReorderReceiptRuleSet(ruleSet string, ruleNames []string) error {
+	return c.ReorderReceiptRuleSetContext(context.Background(), ruleSet, ruleNames)
+}
+
+// ReorderReceiptRuleSetContext is like ReorderReceiptRuleSet but uses ctx for the request and
+// any retries.
+func (c *Config) ReorderReceiptRuleSetContext(ctx context.Context, ruleSet string, ruleNames []string) error {
+	data := make(url.Values)
+	data.Add("Action", "ReorderReceiptRuleSet")
+	data.Add("RuleSetName", ruleSet)
+	addMembers(data, "RuleNames", ruleNames)
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+// CreateReceiptRule adds rule to a receipt rule set, after the rule named after, or first if
+// after is empty.
+func (c *Config) CreateReceiptRule(ruleSet string, rule ReceiptRule, after string) error {
+	return c.CreateReceiptRuleContext(context.Background(), ruleSet, rule, after)
+}
+
+// CreateReceiptRuleContext is like CreateReceiptRule but uses ctx for the request and any
+// retries.
+func (c *Config) CreateReceiptRuleContext(ctx context.Context, ruleSet string, rule ReceiptRule, after string) error {
+	data := make(url.Values)
+	data.Add("Action", "CreateReceiptRule")
+	data.Add("RuleSetName", ruleSet)
+	rule.addTo(data, "Rule")
+	if after != "" {
+		data.Add("After", after)
+	}
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+// UpdateReceiptRule replaces the rule of a receipt rule set that has the same name as rule.
+func (c *Config) UpdateReceiptRule(ruleSet string, rule ReceiptRule) error {
+	return c.UpdateReceiptRuleContext(context.Background(), ruleSet, rule)
+}
+
+// UpdateReceiptRuleContext is like UpdateReceiptRule but uses ctx for the request and any
+// retries.
+func (c *Config) UpdateReceiptRuleContext(ctx context.Context, ruleSet string, rule ReceiptRule) error {
+	data := make(url.Values)
+	data.Add("Action", "UpdateReceiptRule")
+	data.Add("RuleSetName", ruleSet)
+	rule.addTo(data, "Rule")
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+type DescribeReceiptRuleResult struct {
+	Rule ReceiptRule
+}
+
+type DescribeReceiptRuleResponse struct {
+	DescribeReceiptRuleResult DescribeReceiptRuleResult
+}
+
+// DescribeReceiptRule returns the named rule of a receipt rule set.
+func (c *Config) DescribeReceiptRule(ruleSet, name string) (ReceiptRule, error) {
+	return c.DescribeReceiptRuleContext(context.Background(), ruleSet, name)
+}
+
+// DescribeReceiptRuleContext is like DescribeReceiptRule but uses ctx for the request and any
+// retries.
+func (c *Config) DescribeReceiptRuleContext(ctx context.Context, ruleSet, name string) (ReceiptRule, error) {
+	data := make(url.Values)
+	data.Add("Action", "DescribeReceiptRule")
+	data.Add("RuleSetName", ruleSet)
+	data.Add("RuleName", name)
+
+	res := DescribeReceiptRuleResponse{}
+	err := c.call(ctx, "GET", data, &res)
+	return res.DescribeReceiptRuleResult.Rule, err
+}
+
+// SetReceiptRulePosition moves the named rule of a receipt rule set after the rule named after,
+// or first if after is empty.
+func (c *Config) SetReceiptRulePosition(ruleSet, name, after string) error {
+	return c.SetReceiptRulePositionContext(context.Background(), ruleSet, name, after)
+}
+
+// SetReceiptRulePositionContext is like SetReceiptRulePosition but uses ctx for the request and
+// any retries.
+func (c *Config) SetReceiptRulePositionContext(ctx context.Context, ruleSet, name, after string) error {
+	data := make(url.Values)
+	data.Add("Action", "SetReceiptRulePosition")
+	data.Add("RuleSetName", ruleSet)
+	data.Add("RuleName", name)
+	if after != "" {
+		data.Add("After", after)
+	}
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+// DeleteReceiptRule removes the named rule from a receipt rule set.
+func (c *Config) DeleteReceiptRule(ruleSet, name string) error {
+	return c.DeleteReceiptRuleContext(context.Background(), ruleSet, name)
+}
+
+// DeleteReceiptRuleContext is like DeleteReceiptRule but uses ctx for the request and any
+// retries.
+func (c *Config) DeleteReceiptRuleContext(ctx context.Context, ruleSet, name string) error {
+	data := make(url.Values)
+	data.Add("Action", "DeleteReceiptRule")
+	data.Add("RuleSetName", ruleSet)
+	data.Add("RuleName", name)
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+// ReceiptFilter allows or blocks inbound mail from a range of IP addresses, before any receipt
+// rule is applied.
+type ReceiptFilter struct {
+	Name     string
+	IpFilter ReceiptIpFilter
+}
+
+type ReceiptIpFilter struct {
+	// Policy is "Allow" or "Block".
+	Policy string
+
+	// Cidr is an IPv4 address or range in CIDR notation, such as "10.0.0.0/24".
+	Cidr string
+}
+
+// CreateReceiptFilter creates an IP address filter for inbound mail.
+func (c *Config) CreateReceiptFilter(filter ReceiptFilter) error {
+	return c.CreateReceiptFilterContext(context.Background(), filter)
+}
+
+// CreateReceiptFilterContext is like CreateReceiptFilter but uses ctx for the request and any
+// retries.
+func (c *Config) CreateReceiptFilterContext(ctx context.Context, filter ReceiptFilter) error {
+	data := make(url.Values)
+	data.Add("Action", "CreateReceiptFilter")
+	data.Add("Filter.Name", filter.Name)
+	data.Add("Filter.IpFilter.Policy", filter.IpFilter.Policy)
+	data.Add("Filter.IpFilter.Cidr", filter.IpFilter.Cidr)
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+// DeleteReceiptFilter deletes the named IP address filter.
+func (c *Config) DeleteReceiptFilter(name string) error {
+	return c.DeleteReceiptFilterContext(context.Background(), name)
+}
+
+// DeleteReceiptFilterContext is like DeleteReceiptFilter but uses ctx for the request and any
+// retries.
+func (c *Config) DeleteReceiptFilterContext(ctx context.Context, name string) error {
+	data := make(url.Values)
+	data.Add("Action", "DeleteReceiptFilter")
+	data.Add("FilterName", name)
+
+	return c.call(ctx, "POST", data, nil)
+}
+
+type ListReceiptFiltersResult struct {
+	Filters []ReceiptFilter `xml:"Filters>member"`
+}
+
+type ListReceiptFiltersResponse struct {
+	ListReceiptFiltersResult ListReceiptFiltersResult
+}
+
+// ListReceiptFilters returns the IP address filters for the account.
+func (c *Config) ListReceiptFilters() ([]ReceiptFilter, error) {
+	return c.ListReceiptFiltersContext(context.Background())
+}
+
+// ListReceiptFiltersContext is like ListReceiptFilters but uses ctx for the request and any
+// retries.
+func (c *Config) ListReceiptFiltersContext(ctx context.Context) ([]ReceiptFilter, error) {
+	data := make(url.Values)
+	data.Add("Action", "ListReceiptFilters")
+
+	res := ListReceiptFiltersResponse{}
+	err := c.call(ctx, "GET", data, &res)
+	return res.ListReceiptFiltersResult.Filters, err
+}
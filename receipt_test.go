@@ -0,0 +1,130 @@
+package ses
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCreateReceiptRule(t *testing.T) {
+	c, form := testServer(t, `<CreateReceiptRuleResponse/>`)
+	err := c.CreateReceiptRule("inbound", ReceiptRule{
+		Name:        "support",
+		Enabled:     true,
+		TlsPolicy:   "Require",
+		Recipients:  []string{"support@example.com"},
+		ScanEnabled: true,
+		Actions: []ReceiptAction{
+			{S3Action: &S3Action{BucketName: "mail", ObjectKeyPrefix: "support/"}},
+			{LambdaAction: &LambdaAction{FunctionArn: "arn:aws:lambda:us-east-1:123456789012:function:triage"}},
+			{StopAction: &StopAction{Scope: "RuleSet"}},
+		},
+	}, "spam")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{
+		"Action":                   "CreateReceiptRule",
+		"RuleSetName":              "inbound",
+		"After":                    "spam",
+		"Rule.Name":                "support",
+		"Rule.Enabled":             "true",
+		"Rule.TlsPolicy":           "Require",
+		"Rule.ScanEnabled":         "true",
+		"Rule.Recipients.member.1": "support@example.com",
+		"Rule.Actions.member.1.S3Action.BucketName":      "mail",
+		"Rule.Actions.member.1.S3Action.ObjectKeyPrefix": "support/",
+		"Rule.Actions.member.2.LambdaAction.FunctionArn": "arn:aws:lambda:us-east-1:123456789012:function:triage",
+		"Rule.Actions.member.3.StopAction.Scope":         "RuleSet",
+	})
+	if _, ok := (*form)["Rule.Actions.member.1.S3Action.KmsKeyArn"]; ok {
+		t.Error("got empty KmsKeyArn parameter")
+	}
+}
+
+func TestDescribeReceiptRuleSet(t *testing.T) {
+	c, form := testServer(t, `<DescribeReceiptRuleSetResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <DescribeReceiptRuleSetResult>
+    <Metadata>
+      <Name>inbound</Name>
+      <CreatedTimestamp>2016-07-15T16:25:59.607Z</CreatedTimestamp>
+    </Metadata>
+    <Rules>
+      <member>
+        <Name>support</Name>
+        <Enabled>true</Enabled>
+        <TlsPolicy>Optional</TlsPolicy>
+        <ScanEnabled>false</ScanEnabled>
+        <Recipients>
+          <member>support@example.com</member>
+        </Recipients>
+        <Actions>
+          <member>
+            <SNSAction>
+              <TopicArn>arn:aws:sns:us-east-1:123456789012:inbound</TopicArn>
+              <Encoding>UTF-8</Encoding>
+            </SNSAction>
+          </member>
+        </Actions>
+      </member>
+    </Rules>
+  </DescribeReceiptRuleSetResult>
+</DescribeReceiptRuleSetResponse>`)
+
+	res, err := c.DescribeReceiptRuleSet("inbound")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DescribeReceiptRuleSetResult{
+		Metadata: ReceiptRuleSetMetadata{Name: "inbound", CreatedTimestamp: time.Date(2016, 7, 15, 16, 25, 59, 607000000, time.UTC)},
+		Rules: []ReceiptRule{{
+			Name:       "support",
+			Enabled:    true,
+			TlsPolicy:  "Optional",
+			Recipients: []string{"support@example.com"},
+			Actions:    []ReceiptAction{{SNSAction: &SNSAction{TopicArn: "arn:aws:sns:us-east-1:123456789012:inbound", Encoding: "UTF-8"}}},
+		}},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+	checkForm(t, *form, map[string]string{"Action": "DescribeReceiptRuleSet", "RuleSetName": "inbound"})
+}
+
+func TestSetActiveReceiptRuleSet(t *testing.T) {
+	c, form := testServer(t, `<SetActiveReceiptRuleSetResponse/>`)
+	if err := c.SetActiveReceiptRuleSet(""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := (*form)["RuleSetName"]; ok {
+		t.Error("got RuleSetName parameter when deactivating")
+	}
+	if err := c.SetActiveReceiptRuleSet("inbound"); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"Action": "SetActiveReceiptRuleSet", "RuleSetName": "inbound"})
+}
+
+func TestListReceiptFilters(t *testing.T) {
+	c, _ := testServer(t, `<ListReceiptFiltersResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <ListReceiptFiltersResult>
+    <Filters>
+      <member>
+        <Name>office</Name>
+        <IpFilter>
+          <Policy>Allow</Policy>
+          <Cidr>10.0.0.0/24</Cidr>
+        </IpFilter>
+      </member>
+    </Filters>
+  </ListReceiptFiltersResult>
+</ListReceiptFiltersResponse>`)
+
+	filters, err := c.ListReceiptFilters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []ReceiptFilter{{Name: "office", IpFilter: ReceiptIpFilter{Policy: "Allow", Cidr: "10.0.0.0/24"}}}; !reflect.DeepEqual(filters, want) {
+		t.Errorf("got %+v, want %+v", filters, want)
+	}
+}
@@ -0,0 +1,76 @@
+package ses
+
+import (
+	"sort"
+	"time"
+)
+
+// SendRates are the totals of the GetSendStatistics data points in the period [Start, End).
+type SendRates struct {
+	Start, End time.Time
+
+	DeliveryAttempts int
+	Bounces          int
+	Complaints       int
+	Rejects          int
+}
+
+// BounceRate returns the fraction of delivery attempts that bounced, or 0 if there were none.
+func (r SendRates) BounceRate() float64 {
+	if r.DeliveryAttempts == 0 {
+		return 0
+	}
+	return float64(r.Bounces) / float64(r.DeliveryAttempts)
+}
+
+// ComplaintRate returns the fraction of delivery attempts that were reported as spam, or 0 if
+// there were none. SES may review an account whose complaint rate exceeds 0.1%.
+func (r SendRates) ComplaintRate() float64 {
+	if r.DeliveryAttempts == 0 {
+		return 0
+	}
+	return float64(r.Complaints) / float64(r.DeliveryAttempts)
+}
+
+func (r *SendRates) add(p SendDataPoint) {
+	r.DeliveryAttempts += p.DeliveryAttempts
+	r.Bounces += p.Bounces
+	r.Complaints += p.Complaints
+	r.Rejects += p.Rejects
+}
+
+// SumStatistics returns the totals of the data points (from GetSendStatistics) whose
+// timestamps are in [start, end). For example, the complaint rate of the last day is
+//
+//	ses.SumStatistics(points, now.Add(-24*time.Hour), now).ComplaintRate()
+func SumStatistics(points []SendDataPoint, start, end time.Time) SendRates {
+	r := SendRates{Start: start, End: end}
+	for _, p := range points {
+		if !p.Timestamp.Before(start) && p.Timestamp.Before(end) {
+			r.add(p)
+		}
+	}
+	return r
+}
+
+// RollUpStatistics groups the data points (from GetSendStatistics, which covers 15-minute
+// intervals) into consecutive windows of the given length, aligned to multiples of window since
+// the zero time, and returns the totals of each window that has data points, oldest first.
+func RollUpStatistics(points []SendDataPoint, window time.Duration) []SendRates {
+	byStart := make(map[time.Time]*SendRates)
+	for _, p := range points {
+		start := p.Timestamp.UTC().Truncate(window)
+		r := byStart[start]
+		if r == nil {
+			r = &SendRates{Start: start, End: start.Add(window)}
+			byStart[start] = r
+		}
+		r.add(p)
+	}
+	rates := make([]SendRates, 0, len(byStart))
+	for _, r := range byStart {
+		rates = append(rates, *r)
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Start.Before(rates[j].Start) })
+	return rates
+}
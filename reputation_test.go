@@ -0,0 +1,44 @@
+package ses
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testDataPoints = []SendDataPoint{
+	{DeliveryAttempts: 1000, Bounces: 20, Complaints: 1, Timestamp: time.Date(2024, 3, 1, 10, 15, 0, 0, time.UTC)},
+	{DeliveryAttempts: 500, Bounces: 5, Complaints: 2, Rejects: 1, Timestamp: time.Date(2024, 3, 1, 10, 45, 0, 0, time.UTC)},
+	{DeliveryAttempts: 500, Bounces: 25, Complaints: 0, Timestamp: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)},
+}
+
+func TestSumStatistics(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	r := SumStatistics(testDataPoints, start, start.Add(time.Hour))
+	want := SendRates{Start: start, End: start.Add(time.Hour), DeliveryAttempts: 1500, Bounces: 25, Complaints: 3, Rejects: 1}
+	if r != want {
+		t.Errorf("got %+v, want %+v", r, want)
+	}
+	if got := r.ComplaintRate(); got != 0.002 {
+		t.Errorf("got complaint rate %g, want 0.002", got)
+	}
+	if got, want := r.BounceRate(), 25.0/1500; got != want {
+		t.Errorf("got bounce rate %g, want %g", got, want)
+	}
+	if got := (SendRates{}).ComplaintRate(); got != 0 {
+		t.Errorf("got complaint rate %g for no attempts, want 0", got)
+	}
+}
+
+func TestRollUpStatistics(t *testing.T) {
+	rates := RollUpStatistics(testDataPoints, time.Hour)
+	nine := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	ten := nine.Add(time.Hour)
+	want := []SendRates{
+		{Start: nine, End: ten, DeliveryAttempts: 500, Bounces: 25},
+		{Start: ten, End: ten.Add(time.Hour), DeliveryAttempts: 1500, Bounces: 25, Complaints: 3, Rejects: 1},
+	}
+	if !reflect.DeepEqual(rates, want) {
+		t.Errorf("got %+v, want %+v", rates, want)
+	}
+}
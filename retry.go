@@ -0,0 +1,122 @@
+package ses
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy specifies how requests that fail because of throttling (errors matching
+// ErrThrottled) or a server error (HTTP 5xx) are retried. The delay before each retry grows
+// exponentially from BaseDelay up to MaxDelay.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first. Values less than 1
+	// are treated as 1.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. It doubles with each subsequent retry.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between attempts. If zero, the delay is not capped.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction (between 0 and 1) of each delay that is randomized, so that
+	// concurrent clients that are throttled together don't retry together. Values outside that
+	// range are clamped to it.
+	Jitter float64
+}
+
+// DefaultRetryPolicy is a reasonable retry policy for most senders.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+	Jitter:      0.5,
+}
+
+// RetryError is returned when a request made under a RetryPolicy fails. Err is the error from
+// the last attempt, or the context's error if the context was done while waiting to retry.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%s (after %d attempts)", e.Err, e.Attempts)
+}
+
+func (e *RetryError) Unwrap() error { return e.Err }
+
+// delay returns how long to wait before the given retry (1 for the first retry).
+func (p *RetryPolicy) delay(retry int) time.Duration {
+	d := p.BaseDelay
+	// Without a MaxDelay, stop doubling before d overflows.
+	for i := 1; i < retry && (p.MaxDelay == 0 || d < p.MaxDelay) && d <= math.MaxInt64/2; i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if jitter := math.Min(p.Jitter, 1); jitter > 0 {
+		d -= time.Duration(jitter * rand.Float64() * float64(d))
+	}
+	if d < 0 {
+		d = 0
+	}
+	return d
+}
+
+// retryable reports whether err is a throttling or server error that is worth retrying.
+func retryable(err error) bool {
+	e, ok := err.(*APIError)
+	if !ok {
+		return false
+	}
+	return errors.Is(e, ErrThrottled) || e.StatusCode >= 500
+}
+
+// retrying returns next wrapped to retry each request until it succeeds, fails with an error
+// that is not retryable, or c.RetryPolicy's attempts are exhausted. Failures are returned as a
+// *RetryError.
+func (c *Config) retrying(next RoundTripFunc) RoundTripFunc {
+	p := c.RetryPolicy
+	return func(req *http.Request) (*http.Response, error) {
+		ctx := req.Context()
+		info := GetRequestInfo(req)
+		for attempt := 1; ; attempt++ {
+			if attempt > 1 {
+				info.Attempt = attempt
+				req = withRequestInfo(req, info)
+				if req.GetBody != nil {
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, &RetryError{Attempts: attempt, Err: err}
+					}
+					req.Body = body
+				}
+			}
+			resp, err := next(req)
+			if err == nil {
+				if err = responseError(req, resp, bufferBody(resp)); err == nil {
+					return resp, nil
+				}
+			}
+			if attempt >= p.MaxAttempts || !retryable(err) {
+				return nil, &RetryError{Attempts: attempt, Err: err}
+			}
+
+			d := p.delay(attempt)
+			c.log(LogWarn, "retrying ses request", "request", info.ID, "attempt", attempt, "delay", d, "error", err)
+			t := time.NewTimer(d)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return nil, &RetryError{Attempts: attempt, Err: ctx.Err()}
+			case <-t.C:
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package ses
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const throttlingResponse = `<ErrorResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <Error>
+    <Type>Sender</Type>
+    <Code>Throttling</Code>
+    <Message>Maximum sending rate exceeded.</Message>
+  </Error>
+  <RequestId>a1b2c3</RequestId>
+</ErrorResponse>`
+
+func TestRetryThrottling(t *testing.T) {
+	var n int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		if n < 3 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(throttlingResponse))
+			return
+		}
+		w.Write([]byte("<SendEmailResponse/>"))
+	}))
+	defer srv.Close()
+
+	c := Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET", RetryPolicy: &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}}
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("got %d attempts, want 3", n)
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET", RetryPolicy: &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}}
+	_, err := c.GetSendQuota()
+	re, ok := err.(*RetryError)
+	if !ok {
+		t.Fatalf("got error %v, want *RetryError", err)
+	}
+	if re.Attempts != 2 {
+		t.Errorf("got %d attempts, want 2", re.Attempts)
+	}
+	if ae, ok := re.Err.(*APIError); !ok || ae.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got last error %v, want 503 *APIError", re.Err)
+	}
+}
+
+func TestRetryNotRetryable(t *testing.T) {
+	var n int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	c := Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET", RetryPolicy: &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}}
+	if _, err := c.SendRawEmail([]byte("x")); err == nil {
+		t.Fatal("got nil error")
+	}
+	if n != 1 {
+		t.Errorf("got %d attempts, want 1", n)
+	}
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	c := Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET", RetryPolicy: &RetryPolicy{MaxAttempts: 10, BaseDelay: time.Hour}}
+	_, err := c.SendEmailContext(ctx, "a@example.com", "b@example.com", "s", "b")
+	re, ok := err.(*RetryError)
+	if !ok || re.Err != context.DeadlineExceeded || re.Attempts != 1 {
+		t.Fatalf("got error %#v, want deadline exceeded after 1 attempt", err)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for retry, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 5: time.Second, 50: time.Second} {
+		if d := p.delay(retry); d != want {
+			t.Errorf("retry %d: got delay %s, want %s", retry, d, want)
+		}
+	}
+}
+
+func TestRetryPolicyDelayUncapped(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond}
+	prev := time.Duration(0)
+	for retry := 1; retry < 100; retry++ {
+		d := p.delay(retry)
+		if d < prev {
+			t.Fatalf("retry %d: got delay %s, less than the previous %s", retry, d, prev)
+		}
+		prev = d
+	}
+}
+
+func TestRetryPolicyDelayJitterRange(t *testing.T) {
+	for _, jitter := range []float64{-1, 5} {
+		p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, Jitter: jitter}
+		for retry := 1; retry < 10; retry++ {
+			for i := 0; i < 100; i++ {
+				if d := p.delay(retry); d < 0 || d > p.MaxDelay {
+					t.Fatalf("jitter %v, retry %d: got delay %s, want between 0 and %s", jitter, retry, d, p.MaxDelay)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package ses
+
+import "sync"
+
+// ReturnPathPool rotates the envelope sender of raw messages among verified identities, for
+// example addresses at several subdomains that each have their own MAIL FROM domain (see
+// SetIdentityMailFromDomain), so that bounces, and the reputation they carry, are separated
+// between classes of traffic. Set it as Config.ReturnPathPool.
+type ReturnPathPool struct {
+	// Senders maps a configuration set name (which identifies a stream or tenant) to the
+	// envelope senders its messages rotate among. The senders with key "" are used for
+	// messages without a configuration set or whose set has no senders of its own.
+	Senders map[string][]string
+
+	mu   sync.Mutex
+	next map[string]int
+}
+
+// Next returns the next envelope sender for a message sent using the named configuration set,
+// in round-robin order, or "" if there is none.
+func (p *ReturnPathPool) Next(configurationSet string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := configurationSet
+	senders := p.Senders[key]
+	if len(senders) == 0 {
+		key = ""
+		senders = p.Senders[key]
+	}
+	if len(senders) == 0 {
+		return ""
+	}
+	if p.next == nil {
+		p.next = make(map[string]int)
+	}
+	i := p.next[key] % len(senders)
+	p.next[key] = i + 1
+	return senders[i]
+}
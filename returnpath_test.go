@@ -0,0 +1,42 @@
+package ses
+
+import (
+	"context"
+	"testing"
+)
+
+func TestReturnPathPool(t *testing.T) {
+	p := &ReturnPathPool{Senders: map[string][]string{
+		"":          {"bounces@tx.example.com"},
+		"marketing": {"bounces@m1.example.com", "bounces@m2.example.com"},
+	}}
+	var got []string
+	for i := 0; i < 3; i++ {
+		got = append(got, p.Next("marketing"))
+	}
+	got = append(got, p.Next("other"), p.Next(""))
+	want := []string{"bounces@m1.example.com", "bounces@m2.example.com", "bounces@m1.example.com", "bounces@tx.example.com", "bounces@tx.example.com"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got senders %q, want %q", got, want)
+			break
+		}
+	}
+	if s := (&ReturnPathPool{}).Next("x"); s != "" {
+		t.Errorf("got %q from empty pool", s)
+	}
+}
+
+func TestReturnPathPoolSend(t *testing.T) {
+	c, form := testServer(t, `<SendRawEmailResponse/>`)
+	c.ReturnPathPool = &ReturnPathPool{Senders: map[string][]string{"marketing": {"bounces@m1.example.com"}}}
+	if _, err := c.SendRawEmail([]byte("raw"), WithConfigurationSet("marketing")); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"Source": "bounces@m1.example.com"})
+
+	if _, err := c.SendRawEmailEnvelope(context.Background(), "a@example.com", []string{"b@example.com"}, []byte("raw"), WithConfigurationSet("marketing")); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{"Source": "a@example.com"})
+}
@@ -0,0 +1,60 @@
+package ses
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTemplateRollout(t *testing.T) {
+	r := TemplateRollout{Current: "welcome-v1", Candidate: "welcome-v2", Percent: 10}
+	n := 0
+	for i := 0; i < 10000; i++ {
+		key := fmt.Sprintf("user%d@example.com", i)
+		template, _ := r.Choose(key)
+		if template == "welcome-v2" {
+			n++
+		}
+		if again, _ := r.Choose(key); again != template {
+			t.Fatalf("got %s, then %s for %s", template, again, key)
+		}
+	}
+	if n < 800 || n > 1200 {
+		t.Errorf("got %d of 10000 messages with the candidate, want about 1000", n)
+	}
+
+	for _, percent := range []float64{0, 100} {
+		r.Percent = percent
+		want := map[float64]string{0: "welcome-v1", 100: "welcome-v2"}[percent]
+		if template, _ := r.Choose("a@example.com"); template != want {
+			t.Errorf("at %g%%, got %s, want %s", percent, template, want)
+		}
+	}
+}
+
+func TestTemplateRolloutApply(t *testing.T) {
+	r := TemplateRollout{Current: "welcome-v1", Candidate: "welcome-v2", Percent: 100, TagName: "version"}
+	in := SendEmailInput{
+		Destination: &Destination{ToAddresses: []string{"b@example.com"}},
+		Content:     EmailContent{Template: &TemplateEmail{TemplateName: "welcome"}},
+	}
+	tag, err := r.Apply(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var o sendOptions
+	tag(&o)
+	if in.Content.Template.TemplateName != "welcome-v2" {
+		t.Errorf("got template %q", in.Content.Template.TemplateName)
+	}
+	if len(o.tags) != 1 || o.tags[0] != (MessageTag{Name: "version", Value: "welcome-v2"}) {
+		t.Errorf("got tags %+v", o.tags)
+	}
+}
+
+func TestTemplateRolloutApplyNoTemplate(t *testing.T) {
+	r := TemplateRollout{Current: "welcome-v1", Candidate: "welcome-v2"}
+	in := SendEmailInput{Content: EmailContent{Simple: &SimpleEmail{Subject: "Hi", Text: "Hello"}}}
+	if _, err := r.Apply(&in); err == nil {
+		t.Error("want error for a message without template content")
+	}
+}
@@ -0,0 +1,174 @@
+package ses
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// SenderPool chooses the From address of each message among several senders (for example,
+// addresses at different verified subdomains), weighting each by its recent bounce and
+// complaint rates, so that traffic eases off a sender whose reputation is degrading. Use one
+// pool per stream of traffic.
+//
+// The pool counts the messages it chooses senders for; the bounces and complaints are reported
+// to it with RecordBounce and RecordComplaint, typically from the bounce and complaint
+// notifications of the messages (see package notifications), whose Mail.Source is the sender.
+type SenderPool struct {
+	Senders []string
+
+	// Window is the period over which rates are computed. If 0, 24 hours is used.
+	Window time.Duration
+
+	// MaxBounceRate and MaxComplaintRate are the rates at which a sender's weight falls to 0.
+	// It falls linearly from 1 at a rate of 0. If 0, 5% and 0.1% (the rates at which SES may
+	// review an account) are used.
+	MaxBounceRate    float64
+	MaxComplaintRate float64
+
+	// MinSends is the number of messages in the window below which a sender is given full
+	// weight, so that a few early bounces don't drain it. If 0, 100 is used.
+	MinSends int
+
+	mu      sync.Mutex
+	buckets map[string][]SendRates // hourly counts per sender, oldest first
+}
+
+// Choose returns a sender chosen at random in proportion to the senders' weights, and counts a
+// message sent by it. If every sender has weight 0, the one whose rates are least over the
+// limits is returned. It returns "" if the pool has no senders.
+func (p *SenderPool) Choose() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.Senders) == 0 {
+		return ""
+	}
+	now := time.Now()
+	weights := make([]float64, len(p.Senders))
+	var total float64
+	for i, s := range p.Senders {
+		weights[i] = p.weight(p.rates(s, now))
+		total += weights[i]
+	}
+
+	chosen := 0
+	if total > 0 {
+		x := rand.Float64() * total
+		for i, w := range weights {
+			if x -= w; x < 0 || i == len(weights)-1 {
+				chosen = i
+				break
+			}
+		}
+	} else {
+		best := -1.0
+		for i, s := range p.Senders {
+			r := p.rates(s, now)
+			if badness := r.BounceRate()/p.maxBounceRate() + r.ComplaintRate()/p.maxComplaintRate(); best < 0 || badness < best {
+				chosen, best = i, badness
+			}
+		}
+	}
+	sender := p.Senders[chosen]
+	p.bucket(sender, now).DeliveryAttempts++
+	return sender
+}
+
+// RecordBounce counts a bounce of a message sent by sender.
+func (p *SenderPool) RecordBounce(sender string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bucket(sender, time.Now()).Bounces++
+}
+
+// RecordComplaint counts a complaint about a message sent by sender.
+func (p *SenderPool) RecordComplaint(sender string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bucket(sender, time.Now()).Complaints++
+}
+
+// Rates returns the counts for sender over the window.
+func (p *SenderPool) Rates(sender string) SendRates {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.rates(sender, time.Now())
+}
+
+// Weight returns the weight of sender, between 0 and 1.
+func (p *SenderPool) Weight(sender string) float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.weight(p.rates(sender, time.Now()))
+}
+
+func (p *SenderPool) weight(r SendRates) float64 {
+	minSends := p.MinSends
+	if minSends == 0 {
+		minSends = 100
+	}
+	if r.DeliveryAttempts < minSends {
+		return 1
+	}
+	w := 1 - r.BounceRate()/p.maxBounceRate()
+	if c := 1 - r.ComplaintRate()/p.maxComplaintRate(); c < w {
+		w = c
+	}
+	if w < 0 {
+		return 0
+	}
+	return w
+}
+
+func (p *SenderPool) maxBounceRate() float64 {
+	if p.MaxBounceRate == 0 {
+		return 0.05
+	}
+	return p.MaxBounceRate
+}
+
+func (p *SenderPool) maxComplaintRate() float64 {
+	if p.MaxComplaintRate == 0 {
+		return 0.001
+	}
+	return p.MaxComplaintRate
+}
+
+func (p *SenderPool) window() time.Duration {
+	if p.Window == 0 {
+		return 24 * time.Hour
+	}
+	return p.Window
+}
+
+// rates returns the counts for sender in the window ending at now. p.mu must be held.
+func (p *SenderPool) rates(sender string, now time.Time) SendRates {
+	r := SendRates{Start: now.Add(-p.window()), End: now}
+	for _, b := range p.buckets[sender] {
+		if b.End.After(r.Start) {
+			r.DeliveryAttempts += b.DeliveryAttempts
+			r.Bounces += b.Bounces
+			r.Complaints += b.Complaints
+		}
+	}
+	return r
+}
+
+// bucket returns the hourly bucket of sender for now, dropping buckets that have left the
+// window. p.mu must be held.
+func (p *SenderPool) bucket(sender string, now time.Time) *SendRates {
+	if p.buckets == nil {
+		p.buckets = make(map[string][]SendRates)
+	}
+	bs := p.buckets[sender]
+	start := now.Add(-p.window())
+	for len(bs) > 0 && !bs[0].End.After(start) {
+		bs = bs[1:]
+	}
+	hour := now.Truncate(time.Hour)
+	if len(bs) == 0 || !bs[len(bs)-1].Start.Equal(hour) {
+		bs = append(bs, SendRates{Start: hour, End: hour.Add(time.Hour)})
+	}
+	p.buckets[sender] = bs
+	return &bs[len(bs)-1]
+}
@@ -0,0 +1,57 @@
+package ses
+
+import "testing"
+
+func TestSenderPool(t *testing.T) {
+	p := &SenderPool{Senders: []string{"a@s1.example.com", "a@s2.example.com"}, MinSends: 10}
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		counts[p.Choose()]++
+	}
+	if counts["a@s1.example.com"] < 400 || counts["a@s2.example.com"] < 400 {
+		t.Fatalf("got counts %v, want an even split between healthy senders", counts)
+	}
+
+	// s2's bounce rate reaches 4%, 80% of the limit, so it gets a fifth of the weight of s1.
+	n := counts["a@s2.example.com"]
+	for i := 0; i < n*4/100; i++ {
+		p.RecordBounce("a@s2.example.com")
+	}
+	if w := p.Weight("a@s2.example.com"); w < 0.15 || w > 0.25 {
+		t.Errorf("got weight %g, want about 0.2", w)
+	}
+	counts = make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		counts[p.Choose()]++
+	}
+	if counts["a@s2.example.com"] > 400 {
+		t.Errorf("got counts %v, want most traffic on s1", counts)
+	}
+
+	// With both senders over the complaint limit, the less bad one is chosen.
+	for i := 0; i < 10; i++ {
+		p.RecordComplaint("a@s1.example.com")
+		p.RecordComplaint("a@s2.example.com")
+	}
+	if w := p.Weight("a@s1.example.com"); w != 0 {
+		t.Errorf("got weight %g, want 0", w)
+	}
+	if s := p.Choose(); s != "a@s1.example.com" {
+		t.Errorf("got %s, want the sender with more sends per complaint", s)
+	}
+	if r := p.Rates("a@s1.example.com"); r.Complaints != 10 || r.DeliveryAttempts < 1000 {
+		t.Errorf("got rates %+v", r)
+	}
+}
+
+func TestSenderPoolMinSends(t *testing.T) {
+	p := &SenderPool{Senders: []string{"a@example.com"}}
+	p.Choose()
+	p.RecordBounce("a@example.com")
+	if w := p.Weight("a@example.com"); w != 1 {
+		t.Errorf("got weight %g, want 1 below MinSends", w)
+	}
+	if s := (&SenderPool{}).Choose(); s != "" {
+		t.Errorf("got %q from empty pool", s)
+	}
+}
@@ -0,0 +1,112 @@
+package ses
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/mail"
+	"strings"
+)
+
+// Sendmail sends the RFC 5322 message read from r with SendRawEmail, like "sendmail -t": the
+// envelope recipients are the addresses in its To, Cc and Bcc headers plus any given
+// recipients, and the Bcc headers are removed from the message before it is sent. The envelope
+// sender is the address in the message's Sender or else From header.
+func (c *Config) Sendmail(ctx context.Context, r io.Reader, recipients ...string) (string, error) {
+	return c.SendmailFrom(ctx, "", r, recipients...)
+}
+
+// SendmailFrom is like Sendmail, but uses from as the envelope sender (like "sendmail -f") if
+// it is non-empty.
+func (c *Config) SendmailFrom(ctx context.Context, from string, r io.Reader, recipients ...string) (string, error) {
+	raw, err := ioutil.ReadAll(io.LimitReader(r, MaxRawMessageSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(raw) > MaxRawMessageSize {
+		return "", messageTooLarge(int64(len(raw)))
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return "", err
+	}
+
+	if from == "" {
+		if from, err = envelopeSender(msg.Header); err != nil {
+			return "", err
+		}
+	}
+	to := append([]string(nil), recipients...)
+	for _, h := range []string{"To", "Cc", "Bcc"} {
+		addrs, err := msg.Header.AddressList(h)
+		if err == mail.ErrHeaderNotPresent {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		for _, a := range addrs {
+			to = append(to, a.Address)
+		}
+	}
+	if len(to) == 0 {
+		return "", errors.New("ses: message has no recipients")
+	}
+
+	return c.SendRawEmailEnvelope(ctx, from, to, removeHeader(raw, "Bcc"))
+}
+
+func envelopeSender(h mail.Header) (string, error) {
+	for _, name := range []string{"Sender", "From"} {
+		if h.Get(name) == "" {
+			continue
+		}
+		addrs, err := h.AddressList(name)
+		if err != nil {
+			return "", err
+		}
+		if len(addrs) > 0 {
+			return addrs[0].Address, nil
+		}
+	}
+	return "", errors.New("ses: message has no From header")
+}
+
+// removeHeader returns raw with all occurrences of the named header field (including their
+// continuation lines) removed from its header section.
+func removeHeader(raw []byte, name string) []byte {
+	var out bytes.Buffer
+	removing := false
+	rest := raw
+	for len(rest) > 0 {
+		i := bytes.IndexByte(rest, '\n')
+		var line []byte
+		if i < 0 {
+			line, rest = rest, nil
+		} else {
+			line, rest = rest[:i+1], rest[i+1:]
+		}
+
+		trimmed := bytes.TrimRight(line, "\r\n")
+		if len(trimmed) == 0 {
+			// End of the header section.
+			out.Write(line)
+			out.Write(rest)
+			break
+		}
+		if trimmed[0] == ' ' || trimmed[0] == '\t' {
+			// Continuation of the previous field.
+			if !removing {
+				out.Write(line)
+			}
+			continue
+		}
+		colon := bytes.IndexByte(trimmed, ':')
+		removing = colon > 0 && strings.EqualFold(strings.TrimSpace(string(trimmed[:colon])), name)
+		if !removing {
+			out.Write(line)
+		}
+	}
+	return out.Bytes()
+}
@@ -0,0 +1,62 @@
+package ses
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+const sendmailMessage = "From: Alice <alice@example.com>\r\n" +
+	"To: Bob <bob@example.com>, carol@example.com\r\n" +
+	"Bcc: dave@example.com,\r\n" +
+	" erin@example.com\r\n" +
+	"Subject: Hi\r\n" +
+	"\r\n" +
+	"Bcc: this is the body, not a header\r\n"
+
+func TestSendmail(t *testing.T) {
+	c, form := testServer(t, `<SendRawEmailResponse/>`)
+	if _, err := c.Sendmail(context.Background(), strings.NewReader(sendmailMessage), "frank@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{
+		"Action":                "SendRawEmail",
+		"Source":                "alice@example.com",
+		"Destinations.member.1": "frank@example.com",
+		"Destinations.member.2": "bob@example.com",
+		"Destinations.member.3": "carol@example.com",
+		"Destinations.member.4": "dave@example.com",
+		"Destinations.member.5": "erin@example.com",
+	})
+
+	raw, err := base64.StdEncoding.DecodeString(form.Get("RawMessage.Data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "From: Alice <alice@example.com>\r\n" +
+		"To: Bob <bob@example.com>, carol@example.com\r\n" +
+		"Subject: Hi\r\n" +
+		"\r\n" +
+		"Bcc: this is the body, not a header\r\n"
+	if string(raw) != want {
+		t.Errorf("got message %q, want %q", raw, want)
+	}
+}
+
+func TestSendmailFrom(t *testing.T) {
+	c, form := testServer(t, `<SendRawEmailResponse/>`)
+	if _, err := c.SendmailFrom(context.Background(), "bounces@example.com", strings.NewReader(sendmailMessage)); err != nil {
+		t.Fatal(err)
+	}
+	if got := form.Get("Source"); got != "bounces@example.com" {
+		t.Errorf("got Source %q, want bounces@example.com", got)
+	}
+}
+
+func TestSendmailNoRecipients(t *testing.T) {
+	c, _ := testServer(t, `<SendRawEmailResponse/>`)
+	if _, err := c.Sendmail(context.Background(), strings.NewReader("From: alice@example.com\r\n\r\nbody")); err == nil {
+		t.Fatal("got nil error for message without recipients")
+	}
+}
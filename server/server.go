@@ -0,0 +1,291 @@
+// Package server exposes a ses.SenderAPI as a JSON-over-HTTP service, so that programs in any
+// language can send email through one egress service built on package ses. The endpoints are:
+//
+//	POST /send        {"from", "to", "subject", "text", "html", "configurationSet", "tags", "idempotencyKey"}
+//	POST /send-raw    {"from", "to": [...], "raw": "<base64>", "configurationSet", "tags", "idempotencyKey"}
+//	GET  /quota       the result of GetSendQuota
+//	GET  /statistics  the result of GetSendStatistics
+//
+// If the Server has a Queue, messages can also be queued for later delivery:
+//
+//	POST /enqueue     {"from", "to", "subject", "text", "html", "sendAt", "expires", "configurationSet", "tags", "idempotencyKey"}
+//	GET  /queue       {"queued": <number of queued messages>}
+//
+// Sends respond with {"messageId": "..."}, and /enqueue with {"id": "..."}, the ID of the queue
+// item. Errors respond with {"error": "...", "code": "..."} and a status reflecting the cause:
+// 400 for invalid messages, 409 for duplicates, 429 for throttling, 503 for disabled sending
+// and 502 for other SES errors.
+//
+// Only JSON over HTTP is served. A gRPC interface is out of scope, since it would add the gRPC
+// and protobuf modules as dependencies of this package.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+	"github.com/sourcegraph/go-ses/queue"
+)
+
+// ErrUnauthorized is returned by authentication hooks that reject a request.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// A Server handles the HTTP API. A nil Authenticate allows all requests, so a Server should be
+// given one unless it is only reachable from trusted clients.
+type Server struct {
+	SES ses.SenderAPI
+
+	// Authenticate, if non-nil, is called before each request is handled. If it returns an
+	// error, the request is rejected with a 401 response.
+	Authenticate func(r *http.Request) error
+
+	// Queue, if non-nil, serves /enqueue and /queue. Its Run method must be running for queued
+	// messages to be sent.
+	Queue *queue.Queue
+}
+
+// BearerToken returns an authentication hook that accepts requests with the header
+// "Authorization: Bearer <token>".
+func BearerToken(token string) func(r *http.Request) error {
+	return func(r *http.Request) error {
+		h := r.Header.Get("Authorization")
+		if !strings.HasPrefix(h, "Bearer ") {
+			return ErrUnauthorized
+		}
+		got := strings.TrimPrefix(h, "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			return ErrUnauthorized
+		}
+		return nil
+	}
+}
+
+// SendRequest is the body of a /send request.
+type SendRequest struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html,omitempty"`
+	Options
+}
+
+// SendRawRequest is the body of a /send-raw request. From and To are the envelope sender and
+// recipients; if empty, those in the message headers are used.
+type SendRawRequest struct {
+	From string   `json:"from,omitempty"`
+	To   []string `json:"to,omitempty"`
+	Raw  []byte   `json:"raw"`
+	Options
+}
+
+// EnqueueRequest is the body of an /enqueue request. SendAt, if non-zero, delays the message
+// until then, and Expires, if non-zero, drops it if it hasn't been sent by then.
+type EnqueueRequest struct {
+	SendRequest
+	SendAt  time.Time `json:"sendAt,omitempty"`
+	Expires time.Time `json:"expires,omitempty"`
+}
+
+// Options are the optional send parameters of SendRequest and SendRawRequest.
+type Options struct {
+	ConfigurationSet string            `json:"configurationSet,omitempty"`
+	Tags             map[string]string `json:"tags,omitempty"`
+	IdempotencyKey   string            `json:"idempotencyKey,omitempty"`
+}
+
+func (o *Options) sendOptions() []ses.SendOption {
+	var opts []ses.SendOption
+	if o.ConfigurationSet != "" {
+		opts = append(opts, ses.WithConfigurationSet(o.ConfigurationSet))
+	}
+	for _, tag := range o.tags() {
+		opts = append(opts, ses.WithTag(tag.Name, tag.Value))
+	}
+	if o.IdempotencyKey != "" {
+		opts = append(opts, ses.WithIdempotencyKey(o.IdempotencyKey))
+	}
+	return opts
+}
+
+// tags returns o.Tags sorted by name, so that identical requests are sent identically (and are
+// recognized by a DuplicateGuard).
+func (o *Options) tags() []ses.MessageTag {
+	names := make([]string, 0, len(o.Tags))
+	for name := range o.Tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var tags []ses.MessageTag
+	for _, name := range names {
+		tags = append(tags, ses.MessageTag{Name: name, Value: o.Tags[name]})
+	}
+	return tags
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.Authenticate != nil {
+		if err := s.Authenticate(r); err != nil {
+			writeJSON(w, http.StatusUnauthorized, errorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	ctx := r.Context()
+	switch {
+	case r.URL.Path == "/send" && r.Method == "POST":
+		var req SendRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		s.respond(w, func() (string, error) {
+			if req.HTML != "" {
+				return s.SES.SendEmailHTMLContext(ctx, req.From, req.To, req.Subject, req.Text, req.HTML, req.sendOptions()...)
+			}
+			return s.SES.SendEmailContext(ctx, req.From, req.To, req.Subject, req.Text, req.sendOptions()...)
+		})
+
+	case r.URL.Path == "/send-raw" && r.Method == "POST":
+		var req SendRawRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		s.respond(w, func() (string, error) {
+			if req.From == "" && len(req.To) == 0 {
+				return s.SES.SendRawEmailContext(ctx, req.Raw, req.sendOptions()...)
+			}
+			return s.SES.SendRawEmailEnvelope(ctx, req.From, req.To, req.Raw, req.sendOptions()...)
+		})
+
+	case r.URL.Path == "/quota" && r.Method == "GET":
+		q, err := s.SES.GetSendQuotaContext(ctx)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, q)
+
+	case r.URL.Path == "/statistics" && r.Method == "GET":
+		points, err := s.SES.GetSendStatisticsContext(ctx)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, points)
+
+	case r.URL.Path == "/enqueue" && r.Method == "POST" && s.Queue != nil:
+		var req EnqueueRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		for field, addr := range map[string]string{"from": req.From, "to": req.To} {
+			if err := ses.ValidateAddress(addr); err != nil {
+				err.(*ses.ValidationError).Field = field
+				writeError(w, err)
+				return
+			}
+		}
+		m := queue.Message{
+			From:             req.From,
+			To:               req.To,
+			Subject:          req.Subject,
+			Text:             req.Text,
+			HTML:             req.HTML,
+			ConfigurationSet: req.ConfigurationSet,
+			IdempotencyKey:   req.IdempotencyKey,
+			Tags:             req.tags(),
+			Expires:          req.Expires,
+		}
+		id, err := s.Queue.Enqueue(m, req.SendAt)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, map[string]string{"id": id})
+
+	case r.URL.Path == "/queue" && r.Method == "GET" && s.Queue != nil:
+		n, err := s.Queue.Len()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"queued": n})
+
+	default:
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "not found"})
+	}
+}
+
+// respond performs send and writes its message ID or error.
+func (s *Server) respond(w http.ResponseWriter, send func() (string, error)) {
+	res, err := send()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var v struct {
+		Result struct {
+			MessageID string `xml:"MessageId"`
+		} `xml:",any"`
+	}
+	xml.Unmarshal([]byte(res), &v)
+	writeJSON(w, http.StatusOK, struct {
+		MessageID string `json:"messageId"`
+	}{v.Result.MessageID})
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+}
+
+// writeError writes err with a status code reflecting its cause.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	resp := errorResponse{Error: err.Error()}
+	var apiErr *ses.APIError
+	if errors.As(err, &apiErr) {
+		resp.Code = apiErr.Code
+		status = http.StatusBadGateway
+	}
+	switch {
+	case errors.Is(err, ses.ErrValidation):
+		status = http.StatusBadRequest
+	case errors.Is(err, ses.ErrMessageTooLarge):
+		status = http.StatusRequestEntityTooLarge
+	case errors.Is(err, ses.ErrDuplicate):
+		status = http.StatusConflict
+	case errors.Is(err, ses.ErrSendingDisabled):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, ses.ErrThrottled), errors.Is(err, ses.ErrQuotaExceeded):
+		status = http.StatusTooManyRequests
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusGatewayTimeout
+	}
+	writeJSON(w, status, resp)
+}
+
+// decode decodes the JSON body of r into v, writing a 400 response and returning false if it is
+// malformed.
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, 2*ses.MaxRawMessageSize)
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "malformed request: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+	"github.com/sourcegraph/go-ses/queue"
+	"github.com/sourcegraph/go-ses/sestest"
+)
+
+func TestServer(t *testing.T) {
+	fake := sestest.NewServer()
+	defer fake.Close()
+	srv := httptest.NewServer(&Server{SES: fake.Config(), Authenticate: BearerToken("secret")})
+	defer srv.Close()
+
+	do := func(method, path, token, body string) (int, map[string]interface{}) {
+		req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var v map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&v)
+		return resp.StatusCode, v
+	}
+
+	if status, _ := do("POST", "/send", "wrong", `{}`); status != http.StatusUnauthorized {
+		t.Errorf("bad token: got status %d", status)
+	}
+	req, _ := http.NewRequest("POST", srv.URL+"/send", strings.NewReader(`{}`))
+	req.Header.Set("Authorization", "secret")
+	if resp, err := http.DefaultClient.Do(req); err != nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("token without Bearer scheme: got %v, %v", resp, err)
+	}
+
+	status, v := do("POST", "/send", "secret", `{"from": "a@example.com", "to": "b@example.com", "subject": "Hi", "text": "Hello", "tags": {"campaign": "welcome"}}`)
+	if status != http.StatusOK || v["messageId"] == "" {
+		t.Errorf("send: got %d %v", status, v)
+	}
+	msgs := fake.Messages()
+	if len(msgs) != 1 || msgs[0].To[0] != "b@example.com" || msgs[0].Tags["campaign"] != "welcome" {
+		t.Errorf("got messages %+v", msgs)
+	}
+
+	// "raw" is base64 for "Subject: Raw\r\n\r\nbody".
+	status, v = do("POST", "/send-raw", "secret", `{"from": "a@example.com", "to": ["c@example.com"], "raw": "U3ViamVjdDogUmF3DQoNCmJvZHk="}`)
+	if status != http.StatusOK {
+		t.Errorf("send-raw: got %d %v", status, v)
+	}
+
+	if status, v := do("POST", "/send", "secret", `{"from": "a@example.com", "to": "nobody", "subject": "Hi", "text": "Hello"}`); status != http.StatusBadRequest {
+		t.Errorf("invalid recipient: got %d %v", status, v)
+	}
+
+	fake.Throttle(1)
+	if status, v := do("POST", "/send", "secret", `{"from": "a@example.com", "to": "b@example.com", "subject": "Hi", "text": "Hello"}`); status != http.StatusTooManyRequests || v["code"] != "Throttling" {
+		t.Errorf("throttled: got %d %v", status, v)
+	}
+
+	status, v = do("GET", "/quota", "secret", "")
+	if status != http.StatusOK || v["SentLast24Hours"] != 2.0 {
+		t.Errorf("quota: got %d %v", status, v)
+	}
+}
+
+func TestServerQueue(t *testing.T) {
+	fake := sestest.NewServer()
+	defer fake.Close()
+	q := &queue.Queue{SES: fake.Config(), Store: &queue.MemoryStore{}}
+	srv := httptest.NewServer(&Server{SES: fake.Config(), Queue: q})
+	defer srv.Close()
+
+	do := func(method, path, body string) (int, map[string]interface{}) {
+		req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var v map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&v)
+		return resp.StatusCode, v
+	}
+
+	status, v := do("POST", "/enqueue", `{"from": "a@example.com", "to": "b@example.com", "subject": "Hi", "text": "Hello", "sendAt": "2100-01-01T00:00:00Z", "tags": {"campaign": "welcome"}}`)
+	if status != http.StatusAccepted || v["id"] == "" {
+		t.Errorf("enqueue: got %d %v", status, v)
+	}
+	if status, v := do("POST", "/enqueue", `{"from": "a@example.com", "to": "nobody", "subject": "Hi", "text": "Hello"}`); status != http.StatusBadRequest {
+		t.Errorf("invalid recipient: got %d %v", status, v)
+	}
+	if status, v := do("GET", "/queue", ""); status != http.StatusOK || v["queued"] != 1.0 {
+		t.Errorf("queue: got %d %v", status, v)
+	}
+
+	items, _ := q.Store.List()
+	if len(items) != 1 || items[0].Message.To != "b@example.com" || items[0].Message.Tags[0].Value != "welcome" {
+		t.Errorf("got items %+v", items)
+	}
+}
+
+func TestServerDuplicateTags(t *testing.T) {
+	fake := sestest.NewServer()
+	defer fake.Close()
+	c := fake.Config()
+	c.DuplicateGuard = ses.NewDuplicateGuard(time.Minute)
+	srv := httptest.NewServer(&Server{SES: c})
+	defer srv.Close()
+
+	body := `{"from": "a@example.com", "to": "b@example.com", "subject": "Hi", "text": "Hello", "tags": {"a": "1", "b": "2", "c": "3", "d": "4", "e": "5"}}`
+	var statuses []int
+	for i := 0; i < 2; i++ {
+		resp, err := http.Post(srv.URL+"/send", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		statuses = append(statuses, resp.StatusCode)
+	}
+	if statuses[0] != http.StatusOK || statuses[1] != http.StatusConflict {
+		t.Errorf("got statuses %v, want 200 then 409 for the duplicate", statuses)
+	}
+	if n := len(fake.Messages()); n != 1 {
+		t.Errorf("got %d messages, want 1", n)
+	}
+}
@@ -5,16 +5,17 @@
 package ses
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/xml"
-	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
 	"time"
 )
@@ -27,10 +28,89 @@ type Config struct {
 	// SecretAccessKey is your Amazon AWS secret key.
 	SecretAccessKey string
 
+	// SecurityToken is the AWS session token, required when using temporary credentials (for
+	// example, from STS AssumeRole, SSO or a Lambda execution role). It is sent in the
+	// X-Amz-Security-Token header.
 	SecurityToken string
 
-	// Endpoint
+	// Credentials, if non-nil, provides the credentials used to sign requests instead of
+	// AccessKeyID, SecretAccessKey and SecurityToken. If it is nil and AccessKeyID is empty,
+	// DefaultCredentials is used.
+	Credentials CredentialsProvider
+
+	// Region is the AWS region to send requests to, e.g. "us-east-1" or "eu-west-1". If
+	// empty, DefaultRegion is used.
+	Region string
+
+	// Endpoint, if set, is the URL that requests are sent to instead of the endpoint for
+	// Region, e.g. "http://localhost:4566" for a local SES emulator.
 	Endpoint string
+
+	// RetryPolicy, if non-nil, specifies how requests that fail because of throttling or a
+	// server error are retried. If nil, requests are attempted only once.
+	RetryPolicy *RetryPolicy
+
+	// RateLimiter, if non-nil, limits the rate of send calls (SendEmail, SendEmailHTML and
+	// SendRawEmail), blocking them until they are allowed.
+	RateLimiter *RateLimiter
+
+	// DuplicateGuard, if non-nil, makes send calls that repeat a recently sent message fail
+	// with ErrDuplicate instead of sending it again.
+	DuplicateGuard *DuplicateGuard
+
+	// Gate, if non-nil, is consulted before each send, which fails with ErrSendingDisabled if
+	// the Gate disables the message's configuration set or one of its tags.
+	Gate Gate
+
+	// ReturnPathPool, if non-nil, chooses the envelope sender of messages sent with
+	// SendRawEmail (and SendRawEmailEnvelope with an empty sender) from the pool's senders for
+	// the message's configuration set.
+	ReturnPathPool *ReturnPathPool
+
+	// IdempotencyStore, if non-nil, records the message IDs of sends made with
+	// WithIdempotencyKey, so that they aren't sent again.
+	IdempotencyStore IdempotencyStore
+
+	// DryRun, if true, makes send calls succeed without sending, returning a synthetic message
+	// ID. Validation and the Gate still apply. Each short-circuited send is passed to
+	// OnDryRun, if it is non-nil.
+	DryRun   bool
+	OnDryRun func(DryRunMessage)
+
+	// SkipValidation disables the checks of addresses, subjects and bodies that send calls make
+	// before sending, which fail with a *ValidationError.
+	SkipValidation bool
+
+	// SubjectLinters, if non-nil, check the subjects of messages sent with SendEmail,
+	// SendEmailHTML and SendEmailV2. It is keyed by configuration set; the linter with key ""
+	// applies to messages without a configuration set or whose set has no linter of its own.
+	SubjectLinters map[string]*SubjectLinter
+
+	// Logger, if non-nil, receives log messages about requests and their errors. If nil,
+	// nothing is logged.
+	Logger Logger
+
+	// Metrics, if non-nil, is notified of the start and end of every request attempt.
+	Metrics Metrics
+
+	// LogRequests, if true, includes the request parameters and response bodies in the
+	// LogDebug messages sent to Logger.
+	LogRequests bool
+
+	// Middleware wraps the sending of every request attempt, the first outermost. It runs after
+	// retries, logging and signing, just before the request is sent, so it sees the request as
+	// sent: it may add headers (such as tracing headers), audit the request, or sign it
+	// differently. A middleware that changes a signed part of the request must sign it again.
+	Middleware []Middleware
+}
+
+// EnvConfig takes the credentials from the environment variables $AWS_ACCESS_KEY_ID and
+// $AWS_SECRET_KEY (see EnvProvider), the region from $AWS_REGION or $AWS_DEFAULT_REGION, and
+// the endpoint override, if any, from $AWS_SES_ENDPOINT.
+var EnvConfig = Config{
+	Credentials: EnvProvider{},
+	Region:      firstEnv("AWS_REGION", "AWS_DEFAULT_REGION"),
+	Endpoint:    os.Getenv("AWS_SES_ENDPOINT"),
 }
 
 type GetSendQuotaResult struct {
@@ -59,19 +139,36 @@ type GetSendStatisticsResponse struct {
 	GetSendStatisticsResult GetSendStatisticsResult
 }
 
-func (c *Config) SendEmail(from, to, subject, body string) (string, error) {
+func (c *Config) SendEmail(from, to, subject, body string, opts ...SendOption) (string, error) {
+	return c.SendEmailContext(context.Background(), from, to, subject, body, opts...)
+}
+
+// SendEmailContext is like SendEmail but uses ctx for the request and any retries.
+func (c *Config) SendEmailContext(ctx context.Context, from, to, subject, body string, opts ...SendOption) (string, error) {
+	if err := c.validateMessage(from, []string{to}, subject, body, ""); err != nil {
+		return "", err
+	}
+
 	data := make(url.Values)
 	data.Add("Action", "SendEmail")
 	data.Add("Source", from)
 	data.Add("Destination.ToAddresses.member.1", to)
 	data.Add("Message.Subject.Data", subject)
 	data.Add("Message.Body.Text.Data", body)
-	data.Add("AWSAccessKeyId", c.AccessKeyID)
 
-	return sesPost(data, c.AccessKeyID, c.SecretAccessKey, c.SecurityToken, c.Endpoint)
+	return c.send(ctx, data, opts)
+}
+
+func (c *Config) SendEmailHTML(from, to, subject, bodyText, bodyHTML string, opts ...SendOption) (string, error) {
+	return c.SendEmailHTMLContext(context.Background(), from, to, subject, bodyText, bodyHTML, opts...)
 }
 
-func (c *Config) SendEmailHTML(from, to, subject, bodyText, bodyHTML string) (string, error) {
+// SendEmailHTMLContext is like SendEmailHTML but uses ctx for the request and any retries.
+func (c *Config) SendEmailHTMLContext(ctx context.Context, from, to, subject, bodyText, bodyHTML string, opts ...SendOption) (string, error) {
+	if err := c.validateMessage(from, []string{to}, subject, bodyText, bodyHTML); err != nil {
+		return "", err
+	}
+
 	data := make(url.Values)
 	data.Add("Action", "SendEmail")
 	data.Add("Source", from)
@@ -79,26 +176,59 @@ func (c *Config) SendEmailHTML(from, to, subject, bodyText, bodyHTML string) (st
 	data.Add("Message.Subject.Data", subject)
 	data.Add("Message.Body.Text.Data", bodyText)
 	data.Add("Message.Body.Html.Data", bodyHTML)
-	data.Add("AWSAccessKeyId", c.AccessKeyID)
 
-	return sesPost(data, c.AccessKeyID, c.SecretAccessKey, c.SecurityToken, c.Endpoint)
+	return c.send(ctx, data, opts)
 }
 
-func (c *Config) SendRawEmail(raw []byte) (string, error) {
+func (c *Config) SendRawEmail(raw []byte, opts ...SendOption) (string, error) {
+	return c.SendRawEmailContext(context.Background(), raw, opts...)
+}
+
+// SendRawEmailContext is like SendRawEmail but uses ctx for the request and any retries.
+func (c *Config) SendRawEmailContext(ctx context.Context, raw []byte, opts ...SendOption) (string, error) {
+	if len(raw) > MaxRawMessageSize {
+		return "", messageTooLarge(int64(len(raw)))
+	}
+
 	data := make(url.Values)
 	data.Add("Action", "SendRawEmail")
 	data.Add("RawMessage.Data", base64.StdEncoding.EncodeToString(raw))
-	data.Add("AWSAccessKeyId", c.AccessKeyID)
 
-	return sesPost(data, c.AccessKeyID, c.SecretAccessKey, c.SecurityToken, c.Endpoint)
+	return c.send(ctx, data, opts)
+}
+
+// SendRawEmailEnvelope is like SendRawEmailContext, but sends raw to the envelope recipients
+// to from the envelope sender from, instead of to the recipients and from the sender in its
+// headers.
+func (c *Config) SendRawEmailEnvelope(ctx context.Context, from string, to []string, raw []byte, opts ...SendOption) (string, error) {
+	if len(raw) > MaxRawMessageSize {
+		return "", messageTooLarge(int64(len(raw)))
+	}
+	if err := c.validateEnvelope(from, to); err != nil {
+		return "", err
+	}
+
+	data := make(url.Values)
+	data.Add("Action", "SendRawEmail")
+	if from != "" {
+		data.Add("Source", from)
+	}
+	addMembers(data, "Destinations", to)
+	data.Add("RawMessage.Data", base64.StdEncoding.EncodeToString(raw))
+
+	return c.send(ctx, data, opts)
 }
 
 func (c *Config) GetSendQuota() (GetSendQuotaResult, error) {
+	return c.GetSendQuotaContext(context.Background())
+}
+
+// GetSendQuotaContext is like GetSendQuota but uses ctx for the request and any retries.
+func (c *Config) GetSendQuotaContext(ctx context.Context) (GetSendQuotaResult, error) {
 	data := make(url.Values)
 	data.Add("Action", "GetSendQuota")
-	data.Add("AWSAccessKeyId", c.AccessKeyID)
 
-	body, err := sesGet(data, c.AccessKeyID, c.SecretAccessKey, c.SecurityToken, c.Endpoint)
+	body, err := c.get(ctx, data)
 	if err != nil {
 		return GetSendQuotaResult{}, err
 	}
@@ -109,11 +239,16 @@ func (c *Config) GetSendQuota() (GetSendQuotaResult, error) {
 }
 
 func (c *Config) GetSendStatistics() ([]SendDataPoint, error) {
+	return c.GetSendStatisticsContext(context.Background())
+}
+
+// GetSendStatisticsContext is like GetSendStatistics but uses ctx for the request and any
+// retries.
+func (c *Config) GetSendStatisticsContext(ctx context.Context) ([]SendDataPoint, error) {
 	data := make(url.Values)
 	data.Add("Action", "GetSendStatistics")
-	data.Add("AWSAccessKeyId", c.AccessKeyID)
 
-	body, err := sesGet(data, c.AccessKeyID, c.SecretAccessKey, c.SecurityToken, c.Endpoint)
+	body, err := c.get(ctx, data)
 	if err != nil {
 		return []SendDataPoint{}, err
 	}
@@ -125,95 +260,171 @@ func (c *Config) GetSendStatistics() ([]SendDataPoint, error) {
 	return res.GetSendStatisticsResult.SendDataPoints, err
 }
 
-func authorizationHeader(date, accessKeyID, secretAccessKey string) []string {
-	h := hmac.New(sha256.New, []uint8(secretAccessKey))
-	h.Write([]uint8(date))
-	signature := base64.StdEncoding.EncodeToString(h.Sum(nil))
-	auth := fmt.Sprintf("AWS3-HTTPS AWSAccessKeyId=%s, Algorithm=HmacSHA256, Signature=%s", accessKeyID, signature)
-	return []string{auth}
+// credentials returns the credentials to sign requests with.
+func (c *Config) credentials(ctx context.Context) (Credentials, error) {
+	switch {
+	case c.Credentials != nil:
+		return c.Credentials.Retrieve(ctx)
+	case c.AccessKeyID != "":
+		return Credentials{AccessKeyID: c.AccessKeyID, SecretAccessKey: c.SecretAccessKey, SecurityToken: c.SecurityToken}, nil
+	default:
+		return DefaultCredentials.Retrieve(ctx)
+	}
 }
 
-func sesGet(data url.Values, accessKeyID, secretAccessKey, securityToken, endpoint string) (string, error) {
-	urlstr := fmt.Sprintf("%s?%s", endpoint, data.Encode())
-	endpointURL, _ := url.Parse(urlstr)
-	headers := map[string][]string{}
-
-	now := time.Now().UTC()
-	// date format: "Tue, 25 May 2010 21:20:27 +0000"
-	date := now.Format("Mon, 02 Jan 2006 15:04:05 -0700")
-	headers["Date"] = []string{date}
-
-	h := hmac.New(sha256.New, []uint8(secretAccessKey))
-	h.Write([]uint8(date))
-	signature := base64.StdEncoding.EncodeToString(h.Sum(nil))
-	auth := fmt.Sprintf("AWS3-HTTPS AWSAccessKeyId=%s, Algorithm=HmacSHA256, Signature=%s", accessKeyID, signature)
-	headers["X-Amzn-Authorization"] = []string{auth}
-	if securityToken != "" {
-		headers["X-Amz-Security-Token"] = []string{securityToken}
+// get performs a GET request for the action in data.
+func (c *Config) get(ctx context.Context, data url.Values) (string, error) {
+	endpoint, err := c.endpoint()
+	if err != nil {
+		return "", err
 	}
-
-	req := http.Request{
-		URL:        endpointURL,
-		Method:     "GET",
-		ProtoMajor: 1,
-		ProtoMinor: 1,
-		Close:      true,
-		Header:     headers,
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint+"?"+data.Encode(), nil)
+	if err != nil {
+		return "", err
 	}
+	req.Close = true
+	return c.do(req, RequestInfo{ID: newRequestID(), Action: data.Get("Action"), params: data})
+}
+
+// post performs a POST request for the action in data.
+func (c *Config) post(ctx context.Context, data url.Values) (string, error) {
+	return c.postRaw(ctx, data, nil)
+}
 
-	r, err := http.DefaultClient.Do(&req)
+// postRaw is like post, but if raw is non-nil, the message read from the reader it returns is
+// streamed as the RawMessage.Data parameter (see encodeRawMessage). raw is called once per
+// attempt.
+func (c *Config) postRaw(ctx context.Context, data url.Values, raw func() (io.Reader, error)) (string, error) {
+	endpoint, err := c.endpoint()
 	if err != nil {
-		log.Printf("http error: %s", err)
 		return "", err
 	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	encoded := data.Encode()
+	req.GetBody = func() (io.ReadCloser, error) {
+		body := ioutil.NopCloser(strings.NewReader(encoded))
+		if raw == nil {
+			return body, nil
+		}
+		r, err := raw()
+		if err != nil {
+			return nil, err
+		}
+		return prependBody(encoded, func() (io.ReadCloser, error) { return encodeRawMessage(r), nil })()
+	}
+	if raw == nil {
+		req.ContentLength = int64(len(encoded))
+	}
+	return c.do(req, RequestInfo{ID: newRequestID(), Action: data.Get("Action"), params: data})
+}
 
-	resultbody, _ := ioutil.ReadAll(r.Body)
-	r.Body.Close()
-
-	if r.StatusCode != 200 {
-		log.Printf("error, status = %d", r.StatusCode)
+// call performs a request for the action in data using method ("GET" or "POST") and, if v is
+// non-nil, unmarshals the XML response into v.
+func (c *Config) call(ctx context.Context, method string, data url.Values, v interface{}) error {
+	var body string
+	var err error
+	if method == "GET" {
+		body, err = c.get(ctx, data)
+	} else {
+		body, err = c.post(ctx, data)
+	}
+	if err != nil || v == nil {
+		return err
+	}
+	return xml.Unmarshal([]byte(body), v)
+}
 
-		log.Printf("error response: %s", resultbody)
-		return "", errors.New(string(resultbody))
+// addMembers adds values to data as the list parameter prefix.member.1, prefix.member.2, etc.
+func addMembers(data url.Values, prefix string, values []string) {
+	for i, v := range values {
+		data.Add(fmt.Sprintf("%s.member.%d", prefix, i+1), v)
 	}
+}
 
-	return string(resultbody), nil
+// APIError is returned when Amazon SES responds with a non-200 status code. The Type, Code,
+// Message and RequestID fields are parsed from the XML error response, if present.
+type APIError struct {
+	StatusCode int    `xml:"-"`
+	Type       string `xml:"Error>Type"`
+	Code       string `xml:"Error>Code"`
+	Message    string `xml:"Error>Message"`
+	RequestID  string `xml:"RequestId"`
+
+	// Body is the raw response body.
+	Body string `xml:"-"`
 }
 
-func sesPost(data url.Values, accessKeyID, secretAccessKey, securityToken, endpoint string) (string, error) {
-	body := strings.NewReader(data.Encode())
-	req, err := http.NewRequest("POST", endpoint, body)
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+func newAPIError(statusCode int, body []byte) *APIError {
+	e := &APIError{}
+	xml.Unmarshal(body, e)
+	e.StatusCode = statusCode
+	e.Body = string(body)
+	return e
+}
 
-	now := time.Now().UTC()
-	// date format: "Tue, 25 May 2010 21:20:27 +0000"
-	date := now.Format("Mon, 02 Jan 2006 15:04:05 -0700")
-	req.Header.Set("Date", date)
+func (e *APIError) Error() string {
+	return fmt.Sprintf("error code %d. response: %s", e.StatusCode, e.Body)
+}
 
+func authorizationHeader(date, accessKeyID, secretAccessKey string) []string {
 	h := hmac.New(sha256.New, []uint8(secretAccessKey))
 	h.Write([]uint8(date))
 	signature := base64.StdEncoding.EncodeToString(h.Sum(nil))
 	auth := fmt.Sprintf("AWS3-HTTPS AWSAccessKeyId=%s, Algorithm=HmacSHA256, Signature=%s", accessKeyID, signature)
-	req.Header.Set("X-Amzn-Authorization", auth)
+	return []string{auth}
+}
 
-	r, err := http.DefaultClient.Do(req)
-	if err != nil {
-		log.Printf("http error: %s", err)
-		return "", err
+// signing returns next wrapped to sign each request with c's credentials: SESv2 requests with
+// Signature Version 4, and others with AWS3-HTTPS.
+func (c *Config) signing(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		creds, err := c.credentials(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		if GetRequestInfo(req).v2 {
+			body, err := requestBody(req)
+			if err != nil {
+				return nil, err
+			}
+			signV4(req, body, creds, c.region(), "ses", time.Now())
+		} else {
+			signV3(req, creds)
+		}
+		return next(req)
 	}
+}
 
-	resultbody, _ := ioutil.ReadAll(r.Body)
-	r.Body.Close()
-
-	if r.StatusCode != 200 {
-		log.Printf("error, status = %d", r.StatusCode)
-
-		log.Printf("error response: %s", resultbody)
-		return "", errors.New(fmt.Sprintf("error code %d. response: %s", r.StatusCode, resultbody))
+// signV3 signs req with AWS3-HTTPS, and adds the AWSAccessKeyId parameter to its query (for a
+// GET) or its body.
+func signV3(req *http.Request, creds Credentials) {
+	// date format: "Tue, 25 May 2010 21:20:27 +0000"
+	date := time.Now().UTC().Format("Mon, 02 Jan 2006 15:04:05 -0700")
+	req.Header.Set("Date", date)
+	req.Header["X-Amzn-Authorization"] = authorizationHeader(date, creds.AccessKeyID, creds.SecretAccessKey)
+	if creds.SecurityToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SecurityToken)
 	}
 
-	return string(resultbody), nil
+	param := "AWSAccessKeyId=" + url.QueryEscape(creds.AccessKeyID) + "&"
+	if req.Method == "GET" {
+		req.URL.RawQuery = param + req.URL.RawQuery
+		return
+	}
+	body := req.Body
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(strings.NewReader(param), body), body}
+	if req.ContentLength > 0 {
+		req.ContentLength += int64(len(param))
+	}
+	if req.GetBody != nil {
+		req.GetBody = prependBody(param, req.GetBody)
+	}
 }
@@ -1,9 +1,13 @@
 package ses
 
 import (
+	"context"
     "encoding/base64"
 	"flag"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 )
 
@@ -75,3 +79,62 @@ This is an <b>html email</b>.
 <br/>
 <img src="http://placehold.it/600x200/">
 `
+
+func TestSecurityToken(t *testing.T) {
+	var tokens []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("X-Amz-Security-Token"))
+		w.Write([]byte("<SendEmailResponse/>"))
+	}))
+	defer srv.Close()
+
+	c := Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET", SecurityToken: "TOKEN"}
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetSendQuota(); err != nil {
+		t.Fatal(err)
+	}
+	for _, token := range tokens {
+		if token != "TOKEN" {
+			t.Errorf("got X-Amz-Security-Token %q, want %q", token, "TOKEN")
+		}
+	}
+}
+
+// testServer starts a fake SES endpoint that responds to every request with response and
+// records the parameters of the last request in *form. The server is closed when the test ends.
+func testServer(t *testing.T, response string) (c *Config, form *url.Values) {
+	form = new(url.Values)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		*form = r.Form
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return &Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET"}, form
+}
+
+// checkForm reports an error for each of the want parameters that is not set in form.
+func checkForm(t *testing.T, form url.Values, want map[string]string) {
+	t.Helper()
+	for k, v := range want {
+		if got := form.Get(k); got != v {
+			t.Errorf("got %s %q, want %q", k, got, v)
+		}
+	}
+}
+
+func TestSendRawEmailEnvelope(t *testing.T) {
+	c, form := testServer(t, `<SendRawEmailResponse/>`)
+	if _, err := c.SendRawEmailEnvelope(context.Background(), "a@example.com", []string{"b@example.com", "c@example.com"}, []byte("raw")); err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, *form, map[string]string{
+		"Action":                "SendRawEmail",
+		"Source":                "a@example.com",
+		"Destinations.member.1": "b@example.com",
+		"Destinations.member.2": "c@example.com",
+		"RawMessage.Data":       base64.StdEncoding.EncodeToString([]byte("raw")),
+	})
+}
@@ -0,0 +1,356 @@
+// Package sestest provides an in-memory fake of the Amazon SES API for testing code that uses
+// package ses. The fake records the messages sent to it, and can simulate throttling, the
+// sending quota and identity verification:
+//
+//	srv := sestest.NewServer()
+//	defer srv.Close()
+//	c := srv.Config()
+//	c.SendEmail("a@example.com", "b@example.com", "Hello", "Hi!")
+//	msgs := srv.Messages() // msgs[0].To == []string{"b@example.com"}
+package sestest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+// Message is a message sent to a Server.
+type Message struct {
+	// ID is the SES message ID returned for the message.
+	ID string
+
+	// Action is "SendEmail" or "SendRawEmail".
+	Action string
+
+	// Source is the sender. For SendRawEmail without an envelope sender, it is taken from the
+	// From header.
+	Source string
+
+	// To holds the recipients. For SendRawEmail, they are the envelope destinations or, if
+	// there are none, the To and Cc header addresses.
+	To []string
+
+	// Subject, Text and HTML are set for SendEmail. Subject is also set for SendRawEmail,
+	// from the Subject header.
+	Subject string
+	Text    string
+	HTML    string
+
+	// Raw is the raw message of a SendRawEmail.
+	Raw []byte
+
+	ConfigurationSet string
+	Tags             map[string]string
+}
+
+// A Server is a fake SES endpoint backed by an httptest.Server. It is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the server, to be used as a Config's Endpoint.
+	URL string
+
+	srv *httptest.Server
+
+	mu              sync.Mutex
+	messages        []Message
+	quota           ses.GetSendQuotaResult
+	throttle        int
+	identities      map[string]string // identity -> verification status
+	requireVerified bool
+	seq             int
+}
+
+// NewServer starts and returns a new Server. Its quota is 50000 messages per day at 100
+// messages per second, which it reports but doesn't enforce except for the daily total. The
+// caller must call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		quota:      ses.GetSendQuotaResult{Max24HourSend: 50000, MaxSendRate: 100},
+		identities: make(map[string]string),
+	}
+	s.srv = httptest.NewServer(s)
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Config returns a Config that sends requests to the server with dummy credentials.
+func (s *Server) Config() *ses.Config {
+	return &ses.Config{Endpoint: s.URL, AccessKeyID: "AKIDSESTEST", SecretAccessKey: "sestest"}
+}
+
+// Messages returns the messages sent to the server so far, in the order they were sent.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// SetQuota sets the sending quota reported by GetSendQuota. Sends fail with a Throttling error
+// once max24HourSend messages have been sent.
+func (s *Server) SetQuota(max24HourSend, maxSendRate float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.quota.Max24HourSend = max24HourSend
+	s.quota.MaxSendRate = maxSendRate
+}
+
+// Throttle makes the next n requests fail with a Throttling error.
+func (s *Server) Throttle(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.throttle = n
+}
+
+// SetVerificationStatus sets the verification status of an email address or domain identity,
+// such as ses.VerificationStatusSuccess, adding the identity if necessary.
+func (s *Server) SetVerificationStatus(identity, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.identities[identity] = status
+}
+
+// RequireVerified makes sends fail with a MessageRejected error, as in the SES sandbox, unless
+// the sender's address or domain has been verified (see SetVerificationStatus).
+func (s *Server) RequireVerified(require bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requireVerified = require
+}
+
+// apiError is an SES error response.
+type apiError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	requestID := fmt.Sprintf("00000000-0000-0000-0000-%012d", s.seq)
+
+	var result string
+	var err *apiError
+	if r.ParseForm() != nil || r.Header.Get("X-Amzn-Authorization") == "" {
+		err = &apiError{http.StatusForbidden, "MissingAuthenticationToken", "Request is missing Authentication Token"}
+	} else if s.throttle > 0 {
+		s.throttle--
+		err = &apiError{http.StatusBadRequest, "Throttling", "Maximum sending rate exceeded."}
+	} else {
+		result, err = s.handle(r.Form.Get("Action"), r.Form)
+	}
+
+	w.Header().Set("Content-Type", "text/xml")
+	if err != nil {
+		w.WriteHeader(err.status)
+		fmt.Fprintf(w, `<ErrorResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <Error>
+    <Type>Sender</Type>
+    <Code>%s</Code>
+    <Message>%s</Message>
+  </Error>
+  <RequestId>%s</RequestId>
+</ErrorResponse>`, escape(err.code), escape(err.message), requestID)
+		return
+	}
+	action := r.Form.Get("Action")
+	fmt.Fprintf(w, `<%sResponse xmlns="http://ses.amazonaws.com/doc/2010-12-01/">
+  <%sResult>%s</%sResult>
+  <ResponseMetadata>
+    <RequestId>%s</RequestId>
+  </ResponseMetadata>
+</%sResponse>`, action, action, result, action, requestID, action)
+}
+
+// handle performs action and returns the contents of its result element. s.mu must be held.
+func (s *Server) handle(action string, form url.Values) (string, *apiError) {
+	switch action {
+	case "SendEmail", "SendRawEmail":
+		return s.send(action, form)
+
+	case "GetSendQuota":
+		q := s.quota
+		q.SentLast24Hours = float64(len(s.messages))
+		return fmt.Sprintf("<SentLast24Hours>%g</SentLast24Hours><Max24HourSend>%g</Max24HourSend><MaxSendRate>%g</MaxSendRate>",
+			q.SentLast24Hours, q.Max24HourSend, q.MaxSendRate), nil
+
+	case "GetSendStatistics":
+		if len(s.messages) == 0 {
+			return "<SendDataPoints/>", nil
+		}
+		return fmt.Sprintf("<SendDataPoints><member><DeliveryAttempts>%d</DeliveryAttempts><Bounces>0</Bounces><Complaints>0</Complaints><Rejects>0</Rejects><Timestamp>%s</Timestamp></member></SendDataPoints>",
+			len(s.messages), time.Now().UTC().Truncate(15*time.Minute).Format(time.RFC3339)), nil
+
+	case "VerifyEmailIdentity":
+		s.addIdentity(form.Get("EmailAddress"))
+		return "", nil
+
+	case "VerifyDomainIdentity":
+		domain := form.Get("Domain")
+		s.addIdentity(domain)
+		return "<VerificationToken>" + escape(verificationToken(domain)) + "</VerificationToken>", nil
+
+	case "DeleteIdentity":
+		delete(s.identities, form.Get("Identity"))
+		return "", nil
+
+	case "ListIdentities":
+		var ids []string
+		for id := range s.identities {
+			isEmail := strings.Contains(id, "@")
+			switch form.Get("IdentityType") {
+			case ses.IdentityTypeEmailAddress:
+				if !isEmail {
+					continue
+				}
+			case ses.IdentityTypeDomain:
+				if isEmail {
+					continue
+				}
+			}
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		var b strings.Builder
+		b.WriteString("<Identities>")
+		for _, id := range ids {
+			b.WriteString("<member>" + escape(id) + "</member>")
+		}
+		b.WriteString("</Identities>")
+		return b.String(), nil
+
+	case "GetIdentityVerificationAttributes":
+		var b strings.Builder
+		b.WriteString("<VerificationAttributes>")
+		for _, id := range members(form, "Identities") {
+			status, ok := s.identities[id]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(&b, "<entry><key>%s</key><value><VerificationStatus>%s</VerificationStatus>", escape(id), status)
+			if !strings.Contains(id, "@") {
+				fmt.Fprintf(&b, "<VerificationToken>%s</VerificationToken>", escape(verificationToken(id)))
+			}
+			b.WriteString("</value></entry>")
+		}
+		b.WriteString("</VerificationAttributes>")
+		return b.String(), nil
+	}
+	return "", &apiError{http.StatusBadRequest, "InvalidAction", fmt.Sprintf("The action %s is not valid for this web service.", action)}
+}
+
+// addIdentity adds a pending identity, unless it is already known. s.mu must be held.
+func (s *Server) addIdentity(identity string) {
+	if _, ok := s.identities[identity]; !ok {
+		s.identities[identity] = ses.VerificationStatusPending
+	}
+}
+
+// send records the message sent by a SendEmail or SendRawEmail request. s.mu must be held.
+func (s *Server) send(action string, form url.Values) (string, *apiError) {
+	m := Message{
+		Action:           action,
+		Source:           form.Get("Source"),
+		Subject:          form.Get("Message.Subject.Data"),
+		Text:             form.Get("Message.Body.Text.Data"),
+		HTML:             form.Get("Message.Body.Html.Data"),
+		ConfigurationSet: form.Get("ConfigurationSetName"),
+	}
+	if action == "SendEmail" {
+		m.To = members(form, "Destination.ToAddresses")
+	} else {
+		raw, err := base64.StdEncoding.DecodeString(form.Get("RawMessage.Data"))
+		if err != nil {
+			return "", &apiError{http.StatusBadRequest, "InvalidParameterValue", "Invalid base64 in RawMessage.Data."}
+		}
+		m.Raw = raw
+		m.To = members(form, "Destinations")
+		msg, err := mail.ReadMessage(bytes.NewReader(raw))
+		if err != nil {
+			return "", &apiError{http.StatusBadRequest, "MessageRejected", "Could not parse the raw message."}
+		}
+		m.Subject = msg.Header.Get("Subject")
+		if m.Source == "" {
+			m.Source = msg.Header.Get("From")
+		}
+		if len(m.To) == 0 {
+			for _, h := range []string{"To", "Cc"} {
+				addrs, _ := msg.Header.AddressList(h)
+				for _, a := range addrs {
+					m.To = append(m.To, a.Address)
+				}
+			}
+		}
+	}
+	for i := 1; form.Get(fmt.Sprintf("Tags.member.%d.Name", i)) != ""; i++ {
+		if m.Tags == nil {
+			m.Tags = make(map[string]string)
+		}
+		m.Tags[form.Get(fmt.Sprintf("Tags.member.%d.Name", i))] = form.Get(fmt.Sprintf("Tags.member.%d.Value", i))
+	}
+
+	if len(m.To) == 0 {
+		return "", &apiError{http.StatusBadRequest, "InvalidParameterValue", "Missing final '@domain'"}
+	}
+	if float64(len(s.messages)) >= s.quota.Max24HourSend {
+		return "", &apiError{http.StatusBadRequest, "Throttling", "Daily message quota exceeded."}
+	}
+	if s.requireVerified && !s.verified(m.Source) {
+		return "", &apiError{http.StatusBadRequest, "MessageRejected", "Email address is not verified. The following identities failed the check: " + m.Source}
+	}
+
+	m.ID = fmt.Sprintf("0100sestest%08d-000000", len(s.messages)+1)
+	s.messages = append(s.messages, m)
+	return "<MessageId>" + m.ID + "</MessageId>", nil
+}
+
+// verified reports whether the address or domain of source has been verified. s.mu must be
+// held.
+func (s *Server) verified(source string) bool {
+	a, err := mail.ParseAddress(source)
+	if err != nil {
+		return false
+	}
+	domain := a.Address[strings.LastIndex(a.Address, "@")+1:]
+	return s.identities[a.Address] == ses.VerificationStatusSuccess || s.identities[domain] == ses.VerificationStatusSuccess
+}
+
+// members returns the values of the list parameter prefix.member.N.
+func members(form url.Values, prefix string) []string {
+	var values []string
+	for i := 1; ; i++ {
+		v, ok := form[fmt.Sprintf("%s.member.%d", prefix, i)]
+		if !ok {
+			return values
+		}
+		values = append(values, v[0])
+	}
+}
+
+// verificationToken returns the fake verification token of domain.
+func verificationToken(domain string) string {
+	return base64.StdEncoding.EncodeToString([]byte("sestest:" + domain))
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package sestest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+func TestSend(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Config()
+
+	if _, err := c.SendEmailHTML("a@example.com", "b@example.com", "Hello", "Hi!", "<p>Hi!</p>", ses.WithConfigurationSet("cs"), ses.WithTag("campaign", "welcome")); err != nil {
+		t.Fatal(err)
+	}
+	raw := []byte("From: c@example.com\r\nTo: d@example.com\r\nCc: e@example.com\r\nSubject: Raw\r\n\r\nbody")
+	if _, err := c.SendRawEmail(raw); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := srv.Messages()
+	if len(msgs) != 2 {
+		t.Fatalf("got %d messages, want 2", len(msgs))
+	}
+	if m := msgs[0]; m.Source != "a@example.com" || len(m.To) != 1 || m.To[0] != "b@example.com" || m.HTML != "<p>Hi!</p>" || m.ConfigurationSet != "cs" || m.Tags["campaign"] != "welcome" {
+		t.Errorf("got %+v", m)
+	}
+	if m := msgs[1]; m.Source != "c@example.com" || len(m.To) != 2 || m.Subject != "Raw" || string(m.Raw) != string(raw) {
+		t.Errorf("got %+v", m)
+	}
+
+	q, err := c.GetSendQuota()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.SentLast24Hours != 2 {
+		t.Errorf("got SentLast24Hours %g, want 2", q.SentLast24Hours)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Config()
+
+	srv.Throttle(1)
+	_, err := c.SendEmail("a@example.com", "b@example.com", "s", "b")
+	var apiErr *ses.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "Throttling" {
+		t.Fatalf("got %v, want Throttling error", err)
+	}
+
+	srv.Throttle(1)
+	c.RetryPolicy = &ses.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b"); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+
+	srv.SetQuota(1, 1)
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b"); !errors.As(err, &apiErr) || apiErr.Message != "Daily message quota exceeded." {
+		t.Errorf("got %v, want daily quota error", err)
+	}
+}
+
+func TestVerification(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Config()
+	srv.RequireVerified(true)
+
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b"); err == nil {
+		t.Fatal("unverified sender: want error")
+	}
+
+	if _, err := c.VerifyDomainIdentity("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	attrs, err := c.GetIdentityVerificationAttributes("example.com", "unknown.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attrs) != 1 || attrs["example.com"].VerificationStatus != ses.VerificationStatusPending || attrs["example.com"].VerificationToken == "" {
+		t.Errorf("got %+v", attrs)
+	}
+
+	srv.SetVerificationStatus("example.com", ses.VerificationStatusSuccess)
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "s", "b"); err != nil {
+		t.Errorf("verified domain: %v", err)
+	}
+
+	if err := c.VerifyEmailIdentity("x@other.com"); err != nil {
+		t.Fatal(err)
+	}
+	res, err := c.ListIdentities(ses.IdentityTypeEmailAddress, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Identities) != 1 || res.Identities[0] != "x@other.com" {
+		t.Errorf("got identities %v", res.Identities)
+	}
+}
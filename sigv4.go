@@ -0,0 +1,162 @@
+package ses
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// signV4 signs req, whose body is body, with AWS Signature Version 4 for service in region. It
+// sets the X-Amz-Date, X-Amz-Security-Token (if creds has one) and Authorization headers.
+func signV4(req *http.Request, body []byte, creds Credentials, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SecurityToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SecurityToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	date := now.Format("20060102")
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := signingKeys.get(creds.SecretAccessKey, date, region, service)
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// deriveSigningKey returns the Signature Version 4 signing key for the secret access key
+// secret on date (formatted as 20060102) for service in region.
+func deriveSigningKey(secret, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+// maxSigningKeys bounds the size of a signingKeyCache, in case credentials rotate often.
+const maxSigningKeys = 64
+
+// signingKeyCache caches derived signing keys, which change only daily, so that the four
+// HMACs of deriving a key aren't repeated for every request.
+type signingKeyCache struct {
+	mu   sync.Mutex
+	keys map[signingKeyID][]byte
+}
+
+type signingKeyID struct {
+	secret, date, region, service string
+}
+
+var signingKeys signingKeyCache
+
+// get returns the signing key for the given parameters, deriving it if it isn't cached. Keys
+// for other dates are evicted when a new key is derived.
+func (c *signingKeyCache) get(secret, date, region, service string) []byte {
+	id := signingKeyID{secret, date, region, service}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if key, ok := c.keys[id]; ok {
+		return key
+	}
+	if c.keys == nil || len(c.keys) >= maxSigningKeys {
+		c.keys = make(map[signingKeyID][]byte)
+	}
+	for k := range c.keys {
+		if k.date != date {
+			delete(c.keys, k)
+		}
+	}
+	key := deriveSigningKey(secret, date, region, service)
+	c.keys[id] = key
+	return key
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// canonicalURI returns the canonical path of u. As for all services other than S3, each
+// segment of the already-escaped path is escaped again.
+func canonicalURI(u *url.URL) string {
+	p := u.EscapedPath()
+	if p == "" {
+		return "/"
+	}
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = awsEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery returns the canonical form of the query parameters q.
+func canonicalQuery(q url.Values) string {
+	var params []string
+	for k, vs := range q {
+		for _, v := range vs {
+			params = append(params, awsEscape(k)+"="+awsEscape(v))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// awsEscape percent-encodes every byte of s except the unreserved characters A-Z, a-z, 0-9,
+// '-', '.', '_' and '~', as required by Signature Version 4.
+func awsEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,81 @@
+package ses
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestSignV4(t *testing.T) {
+	// The get-vanilla case from the AWS Signature Version 4 test suite.
+	req, _ := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+	creds := Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signV4(req, nil, creds, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("got Authorization %q, want %q", got, want)
+	}
+	if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+		t.Errorf("got X-Amz-Date %q", got)
+	}
+}
+
+func TestCanonicalURI(t *testing.T) {
+	u := &url.URL{Path: "/v2/email/suppression/addresses/a+b@example.com", RawPath: "/v2/email/suppression/addresses/a%2Bb%40example.com"}
+	if got, want := canonicalURI(u), "/v2/email/suppression/addresses/a%252Bb%2540example.com"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSigningKeyCache(t *testing.T) {
+	var c signingKeyCache
+	key := c.get("secret", "20150830", "us-east-1", "ses")
+	if want := deriveSigningKey("secret", "20150830", "us-east-1", "ses"); string(key) != string(want) {
+		t.Errorf("got key %x, want %x", key, want)
+	}
+	if again := c.get("secret", "20150830", "us-east-1", "ses"); &again[0] != &key[0] {
+		t.Error("key was derived again")
+	}
+	c.get("secret", "20150830", "eu-west-1", "ses")
+	c.get("secret", "20150831", "us-east-1", "ses")
+	if len(c.keys) != 1 {
+		t.Errorf("got %d cached keys, want only the new date's", len(c.keys))
+	}
+	for i := 0; i < 2*maxSigningKeys; i++ {
+		c.get(fmt.Sprint("secret", i), "20150831", "us-east-1", "ses")
+	}
+	if len(c.keys) > maxSigningKeys {
+		t.Errorf("got %d cached keys, want at most %d", len(c.keys), maxSigningKeys)
+	}
+}
+
+func BenchmarkSignV4(b *testing.B) {
+	creds := Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	body := []byte(`{"FromEmailAddress":"a@example.com","Destination":{"ToAddresses":["b@example.com"]}}`)
+	now := time.Now()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		req, _ := http.NewRequest("POST", "https://email.us-east-1.amazonaws.com/v2/email/outbound-emails", nil)
+		req.Header.Set("Content-Type", "application/json")
+		signV4(req, body, creds, "us-east-1", "ses", now)
+	}
+}
+
+func BenchmarkDeriveSigningKey(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		deriveSigningKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20150830", "us-east-1", "ses")
+	}
+}
+
+func BenchmarkSignV3(b *testing.B) {
+	creds := Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		req, _ := http.NewRequest("GET", "https://email.us-east-1.amazonaws.com/?Action=GetSendQuota", nil)
+		signV3(req, creds)
+	}
+}
@@ -0,0 +1,32 @@
+package ses
+
+import "strings"
+
+// Addresses of the SES mailbox simulator, which accepts mail without affecting the account's
+// sending reputation and responds with the named outcome. Sends to them count against the
+// sending quota.
+const (
+	SimulatorSuccess         = "success@simulator.amazonses.com"
+	SimulatorBounce          = "bounce@simulator.amazonses.com"
+	SimulatorOutOfTheOffice  = "ooto@simulator.amazonses.com"
+	SimulatorComplaint       = "complaint@simulator.amazonses.com"
+	SimulatorSuppressionList = "suppressionlist@simulator.amazonses.com"
+)
+
+const simulatorDomain = "@simulator.amazonses.com"
+
+// SimulatorAddress returns the mailbox simulator address with a label, such as
+// "bounce+order-1234@simulator.amazonses.com" for SimulatorBounce and "order-1234", so that
+// the notifications for different test messages can be told apart.
+func SimulatorAddress(simulator, label string) string {
+	if label == "" {
+		return simulator
+	}
+	return strings.TrimSuffix(simulator, simulatorDomain) + "+" + label + simulatorDomain
+}
+
+// IsSimulatorAddress reports whether addr is a mailbox simulator address (with or without a
+// label).
+func IsSimulatorAddress(addr string) bool {
+	return strings.HasSuffix(strings.ToLower(addr), simulatorDomain)
+}
@@ -0,0 +1,12 @@
+package ses
+
+import "testing"
+
+func TestSimulatorAddress(t *testing.T) {
+	if got, want := SimulatorAddress(SimulatorComplaint, "order-1"), "complaint+order-1@simulator.amazonses.com"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if !IsSimulatorAddress(SimulatorAddress(SimulatorSuccess, "x")) || IsSimulatorAddress("success@example.com") {
+		t.Error("IsSimulatorAddress misclassified an address")
+	}
+}
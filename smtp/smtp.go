@@ -0,0 +1,58 @@
+// Package smtp is a drop-in replacement for the SendMail function of net/smtp that sends mail
+// through the Amazon SES API instead of an SMTP server. Code that uses net/smtp like this:
+//
+//	auth := smtp.PlainAuth("", user, password, "mail.example.com")
+//	err := smtp.SendMail("mail.example.com:25", auth, from, to, msg)
+//
+// can be switched to SES by importing this package in place of net/smtp. The SMTP server
+// address and authentication are ignored; the message is sent using Config, which by default
+// takes its credentials and region from the environment (see ses.EnvConfig). To use a
+// different Config, create a Client with NewClient and call its SendMail method instead.
+package smtp
+
+import (
+	"context"
+	"net/smtp"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+// Auth is net/smtp's Auth, so that values created with PlainAuth or CRAMMD5Auth (or net/smtp's)
+// can be passed to SendMail. They are ignored.
+type Auth = smtp.Auth
+
+// PlainAuth is net/smtp's PlainAuth.
+func PlainAuth(identity, username, password, host string) Auth {
+	return smtp.PlainAuth(identity, username, password, host)
+}
+
+// CRAMMD5Auth is net/smtp's CRAMMD5Auth.
+func CRAMMD5Auth(username, secret string) Auth {
+	return smtp.CRAMMD5Auth(username, secret)
+}
+
+// Config is the SES configuration used by the package-level SendMail.
+var Config = &ses.EnvConfig
+
+// A Client sends mail through SES with a particular Config.
+type Client struct {
+	Config *ses.Config
+}
+
+// NewClient returns a Client that sends mail using c.
+func NewClient(c *ses.Config) *Client {
+	return &Client{Config: c}
+}
+
+// SendMail has the same signature as net/smtp's SendMail. It sends msg, which must be an RFC
+// 5322 message including its headers, from the envelope sender from to the envelope
+// recipients to, using c.Config. addr and a are ignored.
+func (c *Client) SendMail(addr string, a Auth, from string, to []string, msg []byte) error {
+	_, err := c.Config.SendRawEmailEnvelope(context.Background(), from, to, msg)
+	return err
+}
+
+// SendMail is like Client.SendMail, using Config.
+func SendMail(addr string, a Auth, from string, to []string, msg []byte) error {
+	return NewClient(Config).SendMail(addr, a, from, to, msg)
+}
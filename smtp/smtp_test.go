@@ -0,0 +1,42 @@
+package smtp
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/sourcegraph/go-ses"
+)
+
+func TestSendMail(t *testing.T) {
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.Form
+		w.Write([]byte("<SendRawEmailResponse/>"))
+	}))
+	defer srv.Close()
+
+	old := Config
+	defer func() { Config = old }()
+	Config = &ses.Config{Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "SECRET"}
+
+	msg := []byte("Subject: hi\r\n\r\nbody")
+	auth := PlainAuth("", "user", "password", "mail.example.com")
+	if err := SendMail("mail.example.com:25", auth, "a@example.com", []string{"b@example.com", "c@example.com"}, msg); err != nil {
+		t.Fatal(err)
+	}
+	for k, want := range map[string]string{
+		"Action":                "SendRawEmail",
+		"Source":                "a@example.com",
+		"Destinations.member.1": "b@example.com",
+		"Destinations.member.2": "c@example.com",
+		"RawMessage.Data":       base64.StdEncoding.EncodeToString(msg),
+	} {
+		if got := form.Get(k); got != want {
+			t.Errorf("got %s %q, want %q", k, got, want)
+		}
+	}
+}
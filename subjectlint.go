@@ -0,0 +1,128 @@
+package ses
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SubjectIssue is a problem found by a SubjectLinter. Rule is "length", "caps",
+// "punctuation" or "phrase".
+type SubjectIssue struct {
+	Rule    string
+	Message string
+}
+
+func (i SubjectIssue) String() string { return i.Rule + ": " + i.Message }
+
+// A SubjectLinter checks subject lines for traits that hurt deliverability. Rules whose limit is
+// zero are not checked. Lint can be called directly, for example from a test of campaign
+// copy; a Config also applies the linters in its SubjectLinters to the messages it sends.
+type SubjectLinter struct {
+	// MaxLength is the maximum length of a subject, in characters.
+	MaxLength int
+
+	// MaxCapsRatio is the maximum fraction of the letters of a subject that may be upper case.
+	// It is checked for subjects with at least 8 letters.
+	MaxCapsRatio float64
+
+	// MaxPunctuation is the maximum number of exclamation and question marks in a subject.
+	// Repeated marks, such as "!!" or "?!", are always reported when it is set.
+	MaxPunctuation int
+
+	// SpamPhrases are phrases, matched case-insensitively, that spam filters associate with
+	// spam.
+	SpamPhrases []string
+
+	// Block, if true, makes a Config fail sends whose subjects have issues with a
+	// *ValidationError. Otherwise, the issues are logged as warnings.
+	Block bool
+}
+
+// DefaultSpamPhrases are phrases commonly weighted by spam filters.
+var DefaultSpamPhrases = []string{
+	"100% free", "act now", "buy now", "cash bonus", "click here", "double your", "earn money",
+	"free gift", "guaranteed", "limited time", "make money", "no obligation", "risk free",
+	"risk-free", "urgent", "winner", "you have been selected", "$$$",
+}
+
+// DefaultSubjectLinter is a SubjectLinter with reasonable limits, which warns of issues.
+var DefaultSubjectLinter = SubjectLinter{
+	MaxLength:      78,
+	MaxCapsRatio:   0.5,
+	MaxPunctuation: 2,
+	SpamPhrases:    DefaultSpamPhrases,
+}
+
+// Lint returns the issues of subject, or nil if it has none.
+func (l *SubjectLinter) Lint(subject string) []SubjectIssue {
+	var issues []SubjectIssue
+	add := func(rule, format string, args ...interface{}) {
+		issues = append(issues, SubjectIssue{rule, fmt.Sprintf(format, args...)})
+	}
+
+	if n := len([]rune(subject)); l.MaxLength > 0 && n > l.MaxLength {
+		add("length", "%d characters, more than %d", n, l.MaxLength)
+	}
+
+	if l.MaxCapsRatio > 0 {
+		var letters, upper int
+		for _, r := range subject {
+			if unicode.IsLetter(r) {
+				letters++
+				if unicode.IsUpper(r) {
+					upper++
+				}
+			}
+		}
+		if ratio := float64(upper) / float64(letters); letters >= 8 && ratio > l.MaxCapsRatio {
+			add("caps", "%.0f%% of letters are upper case", ratio*100)
+		}
+	}
+
+	if l.MaxPunctuation > 0 {
+		n := strings.Count(subject, "!") + strings.Count(subject, "?")
+		if n > l.MaxPunctuation {
+			add("punctuation", "%d exclamation or question marks, more than %d", n, l.MaxPunctuation)
+		}
+		for _, run := range []string{"!!", "??", "?!", "!?"} {
+			if strings.Contains(subject, run) {
+				add("punctuation", "repeated marks %q", run)
+				break
+			}
+		}
+	}
+
+	lower := strings.ToLower(subject)
+	for _, phrase := range l.SpamPhrases {
+		if strings.Contains(lower, strings.ToLower(phrase)) {
+			add("phrase", "contains %q", phrase)
+		}
+	}
+	return issues
+}
+
+// lintSubject applies the SubjectLinter for the configuration set of o to subject. It returns a
+// *ValidationError if the linter blocks subjects with issues, and otherwise logs them.
+func (c *Config) lintSubject(subject string, o *sendOptions) error {
+	l, ok := c.SubjectLinters[o.configurationSet]
+	if !ok {
+		l = c.SubjectLinters[""]
+	}
+	if l == nil {
+		return nil
+	}
+	issues := l.Lint(subject)
+	if len(issues) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(issues))
+	for i, issue := range issues {
+		msgs[i] = issue.String()
+	}
+	if l.Block {
+		return &ValidationError{"subject", subject, strings.Join(msgs, "; ")}
+	}
+	c.log(LogWarn, "subject lint", "subject", subject, "configuration_set", o.configurationSet, "issues", strings.Join(msgs, "; "))
+	return nil
+}
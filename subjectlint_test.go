@@ -0,0 +1,60 @@
+package ses
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSubjectLinter(t *testing.T) {
+	l := DefaultSubjectLinter
+	tests := []struct {
+		subject string
+		rules   []string
+	}{
+		{"Your March invoice", nil},
+		{"Welcome to Example, Ann", nil},
+		{"HUGE SAVINGS ON EVERYTHING", []string{"caps"}},
+		{"Don't miss out!!", []string{"punctuation"}},
+		{"Really? Yes! Really? Yes!", []string{"punctuation"}},
+		{"Click here to claim your free gift", []string{"phrase", "phrase"}},
+		{"This subject line is much too long to be displayed in full by most mail clients today", []string{"length"}},
+		{"NASA", nil},
+	}
+	for _, tt := range tests {
+		var rules []string
+		for _, issue := range l.Lint(tt.subject) {
+			rules = append(rules, issue.Rule)
+		}
+		if !reflect.DeepEqual(rules, tt.rules) {
+			t.Errorf("%q: got rules %v, want %v", tt.subject, rules, tt.rules)
+		}
+	}
+}
+
+func TestSubjectLintersBlock(t *testing.T) {
+	c, form := testServer(t, `<SendEmailResponse/>`)
+	c.SubjectLinters = map[string]*SubjectLinter{
+		"":          {SpamPhrases: []string{"act now"}},
+		"marketing": {SpamPhrases: []string{"act now"}, Block: true},
+	}
+	var warned bool
+	c.Logger = LoggerFunc(func(level LogLevel, msg string, keyvals ...interface{}) {
+		if msg == "subject lint" {
+			warned = true
+		}
+	})
+
+	if _, err := c.SendEmail("a@example.com", "b@example.com", "Act now", "b"); err != nil || !warned {
+		t.Fatalf("got %v, warned %v; want the send to succeed with a warning", err, warned)
+	}
+	*form = nil
+	_, err := c.SendEmail("a@example.com", "b@example.com", "Act now", "b", WithConfigurationSet("marketing"))
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Field != "subject" {
+		t.Fatalf("got %v, want subject ValidationError", err)
+	}
+	if *form != nil {
+		t.Error("blocked message was sent")
+	}
+}
@@ -0,0 +1,91 @@
+package ses
+
+import (
+	"mime"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ClientWidth is the number of subject characters a mail client shows in its message list.
+type ClientWidth struct {
+	Client string
+	Width  int
+}
+
+// ClientWidths are approximate subject widths of common mail clients. They vary with screen
+// size and settings, so treat previews as a guide.
+var ClientWidths = []ClientWidth{
+	{"Gmail (web)", 70},
+	{"Gmail (Android)", 40},
+	{"Apple Mail (iOS)", 41},
+	{"Outlook (web)", 55},
+}
+
+// SubjectPreview shows how a subject is sent and displayed.
+type SubjectPreview struct {
+	Subject string
+
+	// Encoded is the subject as sent in the Subject header: unchanged if it is printable
+	// ASCII, and otherwise as RFC 2047 encoded-words.
+	Encoded string
+
+	// Length is the number of characters in the subject, counting an emoji written as several
+	// code points (with skin tone modifiers, joiners or variation selectors) as one.
+	Length int
+
+	// Truncated is the subject as shown by each of the clients in ClientWidths, keyed by
+	// client name, ending in "…" if it is cut off.
+	Truncated map[string]string
+}
+
+// PreviewSubject returns the preview of subject.
+func PreviewSubject(subject string) SubjectPreview {
+	p := SubjectPreview{
+		Subject:   subject,
+		Encoded:   mime.BEncoding.Encode("UTF-8", subject),
+		Truncated: make(map[string]string),
+	}
+	chars := subjectChars(subject)
+	p.Length = len(chars)
+	for _, cw := range ClientWidths {
+		p.Truncated[cw.Client] = truncateChars(subject, chars, cw.Width)
+	}
+	return p
+}
+
+// subjectChars returns the byte offsets at which the displayed characters of s start.
+func subjectChars(s string) []int {
+	var starts []int
+	joined := false
+	for i, r := range s {
+		// Joiners, combining marks, variation selectors and skin tone modifiers extend the
+		// previous character, as does the character after a joiner.
+		extends := r == '\u200d' || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) ||
+			(r >= 0xfe00 && r <= 0xfe0f) || (r >= 0x1f3fb && r <= 0x1f3ff)
+		if len(starts) == 0 || !(extends || joined) {
+			starts = append(starts, i)
+		}
+		joined = r == '\u200d'
+	}
+	return starts
+}
+
+// truncateChars returns s, whose characters start at chars, cut to width characters with the
+// last replaced by "…" if it is longer.
+func truncateChars(s string, chars []int, width int) string {
+	if len(chars) <= width {
+		return s
+	}
+	if width < 1 {
+		return ""
+	}
+	cut := s[:chars[width-1]]
+	for len(cut) > 0 {
+		r, size := utf8.DecodeLastRuneInString(cut)
+		if r != ' ' {
+			break
+		}
+		cut = cut[:len(cut)-size]
+	}
+	return cut + "…"
+}
@@ -0,0 +1,31 @@
+package ses
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPreviewSubject(t *testing.T) {
+	p := PreviewSubject("Your invoice")
+	if p.Encoded != "Your invoice" || p.Length != 12 {
+		t.Errorf("got %+v", p)
+	}
+
+	subject := "Sale 🎉 ends soon 👍🏽 shop 👨‍👩‍👧 now"
+	p = PreviewSubject(subject)
+	if !strings.HasPrefix(p.Encoded, "=?UTF-8?b?") {
+		t.Errorf("got encoded %q, want RFC 2047 encoded-words", p.Encoded)
+	}
+	if p.Length != 29 {
+		t.Errorf("got length %d, want 29", p.Length)
+	}
+	if got := p.Truncated["Gmail (web)"]; got != subject {
+		t.Errorf("got Gmail preview %q, want whole subject", got)
+	}
+
+	ClientWidths = append(ClientWidths, ClientWidth{"narrow", 20})
+	defer func() { ClientWidths = ClientWidths[:len(ClientWidths)-1] }()
+	if got, want := PreviewSubject(subject).Truncated["narrow"], "Sale 🎉 ends soon 👍🏽…"; got != want {
+		t.Errorf("got narrow preview %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+package ses
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Reasons an address is on the account suppression list.
+const (
+	SuppressionReasonBounce    = "BOUNCE"
+	SuppressionReasonComplaint = "COMPLAINT"
+)
+
+// SuppressedDestination is an address on the account-level suppression list, which SES doesn't
+// send to.
+type SuppressedDestination struct {
+	EmailAddress string
+
+	// Reason is SuppressionReasonBounce or SuppressionReasonComplaint.
+	Reason         string
+	LastUpdateTime time.Time
+
+	// Attributes identify the message and feedback that caused the address to be suppressed,
+	// if SES suppressed it automatically.
+	Attributes struct {
+		MessageID  string `json:"MessageId"`
+		FeedbackID string `json:"FeedbackId"`
+	}
+}
+
+func (d *SuppressedDestination) UnmarshalJSON(b []byte) error {
+	type plain SuppressedDestination
+	v := struct {
+		*plain
+		LastUpdateTime v2Time
+	}{plain: (*plain)(d)}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	d.LastUpdateTime = time.Time(v.LastUpdateTime)
+	return nil
+}
+
+// PutSuppressedDestination adds email to the account suppression list for reason
+// (SuppressionReasonBounce or SuppressionReasonComplaint).
+func (c *Config) PutSuppressedDestination(email, reason string) error {
+	return c.PutSuppressedDestinationContext(context.Background(), email, reason)
+}
+
+// PutSuppressedDestinationContext is like PutSuppressedDestination but uses ctx for the request
+// and any retries.
+func (c *Config) PutSuppressedDestinationContext(ctx context.Context, email, reason string) error {
+	in := struct{ EmailAddress, Reason string }{email, reason}
+	return c.callV2(ctx, "PutSuppressedDestination", "PUT", "/suppression/addresses", nil, in, nil)
+}
+
+// GetSuppressedDestination returns the suppression list entry for email. If email is not on the
+// list, the error is an *APIError with the code "NotFoundException".
+func (c *Config) GetSuppressedDestination(email string) (SuppressedDestination, error) {
+	return c.GetSuppressedDestinationContext(context.Background(), email)
+}
+
+// GetSuppressedDestinationContext is like GetSuppressedDestination but uses ctx for the request
+// and any retries.
+func (c *Config) GetSuppressedDestinationContext(ctx context.Context, email string) (SuppressedDestination, error) {
+	var res struct{ SuppressedDestination SuppressedDestination }
+	err := c.callV2(ctx, "GetSuppressedDestination", "GET", "/suppression/addresses/"+awsEscape(email), nil, nil, &res)
+	return res.SuppressedDestination, err
+}
+
+// DeleteSuppressedDestination removes email from the account suppression list, so that SES
+// sends to it again.
+func (c *Config) DeleteSuppressedDestination(email string) error {
+	return c.DeleteSuppressedDestinationContext(context.Background(), email)
+}
+
+// DeleteSuppressedDestinationContext is like DeleteSuppressedDestination but uses ctx for the
+// request and any retries.
+func (c *Config) DeleteSuppressedDestinationContext(ctx context.Context, email string) error {
+	return c.callV2(ctx, "DeleteSuppressedDestination", "DELETE", "/suppression/addresses/"+awsEscape(email), nil, nil, nil)
+}
+
+// SuppressedDestinationFilter selects the entries returned by ListSuppressedDestinations. The
+// zero value selects all entries.
+type SuppressedDestinationFilter struct {
+	// Reasons, if non-empty, selects entries suppressed for one of these reasons.
+	Reasons []string
+
+	// StartDate and EndDate, if non-zero, select entries last updated in that range.
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+type ListSuppressedDestinationsResult struct {
+	// SuppressedDestinationSummaries don't include Attributes.
+	SuppressedDestinationSummaries []SuppressedDestination
+
+	// NextToken is passed to ListSuppressedDestinations to fetch the next page. It is empty on
+	// the last page.
+	NextToken string
+}
+
+// ListSuppressedDestinations returns a page of the account suppression list entries selected
+// by filter. pageSize limits the page size (0 means the SES default), and nextToken is the
+// NextToken from the previous page, or empty for the first page.
+func (c *Config) ListSuppressedDestinations(filter SuppressedDestinationFilter, pageSize int, nextToken string) (ListSuppressedDestinationsResult, error) {
+	return c.ListSuppressedDestinationsContext(context.Background(), filter, pageSize, nextToken)
+}
+
+// ListSuppressedDestinationsContext is like ListSuppressedDestinations but uses ctx for the
+// request and any retries.
+func (c *Config) ListSuppressedDestinationsContext(ctx context.Context, filter SuppressedDestinationFilter, pageSize int, nextToken string) (ListSuppressedDestinationsResult, error) {
+	query := make(url.Values)
+	for _, r := range filter.Reasons {
+		query.Add("Reason", r)
+	}
+	if !filter.StartDate.IsZero() {
+		query.Add("StartDate", filter.StartDate.UTC().Format(time.RFC3339))
+	}
+	if !filter.EndDate.IsZero() {
+		query.Add("EndDate", filter.EndDate.UTC().Format(time.RFC3339))
+	}
+	if pageSize > 0 {
+		query.Add("PageSize", strconv.Itoa(pageSize))
+	}
+	if nextToken != "" {
+		query.Add("NextToken", nextToken)
+	}
+
+	res := ListSuppressedDestinationsResult{}
+	err := c.callV2(ctx, "ListSuppressedDestinations", "GET", "/suppression/addresses", query, nil, &res)
+	return res, err
+}
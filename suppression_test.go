@@ -0,0 +1,120 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testV2Server returns a Config whose SESv2 requests are handled by h.
+func testV2Server(t *testing.T, h http.HandlerFunc) *Config {
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return &Config{Endpoint: srv.URL, Region: "eu-west-1", AccessKeyID: "AKID", SecretAccessKey: "SECRET"}
+}
+
+func TestPutSuppressedDestination(t *testing.T) {
+	c := testV2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method != "PUT" || r.URL.Path != "/v2/email/suppression/addresses" {
+			t.Errorf("got %s %s", r.Method, r.URL.Path)
+		}
+		if got, want := string(body), `{"EmailAddress":"a@example.com","Reason":"BOUNCE"}`; got != want {
+			t.Errorf("got body %s, want %s", got, want)
+		}
+		if auth := r.Header.Get("Authorization"); !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(auth, "/eu-west-1/ses/aws4_request") {
+			t.Errorf("got Authorization %q", auth)
+		}
+		w.Write([]byte(`{}`))
+	})
+	if err := c.PutSuppressedDestination("a@example.com", SuppressionReasonBounce); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetSuppressedDestination(t *testing.T) {
+	c := testV2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/email/suppression/addresses/missing@example.com" {
+			w.Header().Set("X-Amzn-ErrorType", "NotFoundException:http://internal.amazon.com/coral/com.amazonaws.sesv2/")
+			w.Header().Set("X-Amzn-RequestId", "req-1")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Email address missing@example.com does not exist on your suppression list."}`))
+			return
+		}
+		if got, want := r.URL.EscapedPath(), "/v2/email/suppression/addresses/a%2Bb%40example.com"; got != want {
+			t.Errorf("got path %s, want %s", got, want)
+		}
+		w.Write([]byte(`{"SuppressedDestination":{"EmailAddress":"a+b@example.com","Reason":"COMPLAINT","LastUpdateTime":1.5778368E9,"Attributes":{"MessageId":"m1","FeedbackId":"f1"}}}`))
+	})
+
+	d, err := c.GetSuppressedDestination("a+b@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.EmailAddress != "a+b@example.com" || d.Reason != SuppressionReasonComplaint || d.Attributes.MessageID != "m1" {
+		t.Errorf("got %+v", d)
+	}
+	if want := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC); !d.LastUpdateTime.Equal(want) {
+		t.Errorf("got LastUpdateTime %s, want %s", d.LastUpdateTime, want)
+	}
+
+	_, err = c.GetSuppressedDestination("missing@example.com")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("got %v, want *APIError", err)
+	}
+	if apiErr.StatusCode != 404 || apiErr.Code != "NotFoundException" || apiErr.RequestID != "req-1" || !strings.HasPrefix(apiErr.Message, "Email address missing") {
+		t.Errorf("got %+v", apiErr)
+	}
+}
+
+func TestListSuppressedDestinations(t *testing.T) {
+	c := testV2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if got := q["Reason"]; len(got) != 1 || got[0] != "BOUNCE" {
+			t.Errorf("got Reason %v", got)
+		}
+		if q.Get("StartDate") != "2020-01-01T00:00:00Z" || q.Get("PageSize") != "10" || q.Get("NextToken") != "tok" {
+			t.Errorf("got query %v", q)
+		}
+		w.Write([]byte(`{"SuppressedDestinationSummaries":[{"EmailAddress":"a@example.com","Reason":"BOUNCE","LastUpdateTime":1577836800}],"NextToken":"tok2"}`))
+	})
+	res, err := c.ListSuppressedDestinations(SuppressedDestinationFilter{
+		Reasons:   []string{SuppressionReasonBounce},
+		StartDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, 10, "tok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.SuppressedDestinationSummaries) != 1 || res.SuppressedDestinationSummaries[0].EmailAddress != "a@example.com" || res.NextToken != "tok2" {
+		t.Errorf("got %+v", res)
+	}
+}
+
+func TestDeleteSuppressedDestination(t *testing.T) {
+	c := testV2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" || r.URL.Path != "/v2/email/suppression/addresses/a@example.com" {
+			t.Errorf("got %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{}`))
+	})
+	if err := c.DeleteSuppressedDestination("a@example.com"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSuppressionContext(t *testing.T) {
+	c := testV2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request made with a canceled context")
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.ListSuppressedDestinationsContext(ctx, SuppressedDestinationFilter{}, 0, ""); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,152 @@
+package ses
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// SendEmailInput is a message to send with SendEmailV2, in the shape of the SESv2 SendEmail
+// request. Exactly one of Content.Simple, Content.Raw and Content.Template must be set.
+type SendEmailInput struct {
+	FromEmailAddress string       `json:",omitempty"`
+	Destination      *Destination `json:",omitempty"`
+	ReplyToAddresses []string     `json:",omitempty"`
+	Content          EmailContent
+
+	// FeedbackForwardingEmailAddress receives bounces and complaints, if feedback forwarding
+	// is enabled for the sender.
+	FeedbackForwardingEmailAddress string `json:",omitempty"`
+}
+
+// Destination holds the recipients of a message. A raw message may omit it, in which case it
+// is sent to the recipients in its headers.
+type Destination struct {
+	ToAddresses  []string `json:",omitempty"`
+	CcAddresses  []string `json:",omitempty"`
+	BccAddresses []string `json:",omitempty"`
+}
+
+type EmailContent struct {
+	Simple   *SimpleEmail   `json:",omitempty"`
+	Raw      *RawEmail      `json:",omitempty"`
+	Template *TemplateEmail `json:",omitempty"`
+}
+
+// SimpleEmail is a message that SES formats from a subject and text and/or HTML bodies.
+type SimpleEmail struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+func (m *SimpleEmail) MarshalJSON() ([]byte, error) {
+	type content struct {
+		Data    string
+		Charset string
+	}
+	v := struct {
+		Subject content
+		Body    struct {
+			Text *content `json:",omitempty"`
+			HTML *content `json:"Html,omitempty"`
+		}
+	}{Subject: content{m.Subject, "UTF-8"}}
+	if m.Text != "" {
+		v.Body.Text = &content{m.Text, "UTF-8"}
+	}
+	if m.HTML != "" {
+		v.Body.HTML = &content{m.HTML, "UTF-8"}
+	}
+	return json.Marshal(v)
+}
+
+// RawEmail is a complete MIME message, at most MaxRawMessageSize bytes.
+type RawEmail struct {
+	Data []byte
+}
+
+// TemplateEmail is a message rendered by SES from a stored template.
+type TemplateEmail struct {
+	TemplateName string `json:",omitempty"`
+	TemplateArn  string `json:",omitempty"`
+
+	// TemplateData is a JSON object of the values to substitute into the template.
+	TemplateData string `json:",omitempty"`
+}
+
+// SendEmailV2 sends in with the SESv2 SendEmail API and returns the SES message ID. The
+// configuration set, tags and sending authorization given by opts are applied to the message
+// (WithSourceArn authorizes the sender and WithReturnPathArn the feedback forwarding address),
+// and the send is rate limited, retried and checked by the DuplicateGuard like the other send
+// calls.
+func (c *Config) SendEmailV2(ctx context.Context, in SendEmailInput, opts ...SendOption) (string, error) {
+	if raw := in.Content.Raw; raw != nil && len(raw.Data) > MaxRawMessageSize {
+		return "", messageTooLarge(int64(len(raw.Data)))
+	}
+	if err := c.validateV2(&in); err != nil {
+		return "", err
+	}
+
+	var o sendOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if m := in.Content.Simple; m != nil {
+		if err := c.lintSubject(m.Subject, &o); err != nil {
+			return "", err
+		}
+	}
+	if err := c.checkGate(ctx, &o); err != nil {
+		return "", err
+	}
+	if c.DryRun {
+		return c.dryRun(DryRunMessage{Action: "SendEmailV2", Input: &in}), nil
+	}
+	if id, ok, err := c.sentBefore(ctx, &o); ok || err != nil {
+		return id, err
+	}
+	req := struct {
+		SendEmailInput
+		ConfigurationSetName string       `json:",omitempty"`
+		EmailTags            []MessageTag `json:",omitempty"`
+
+		FromEmailAddressIdentityArn               string `json:",omitempty"`
+		FeedbackForwardingEmailAddressIdentityArn string `json:",omitempty"`
+	}{in, o.configurationSet, o.tags, o.sourceArn, o.returnPathArn}
+
+	var dupKey []byte
+	if c.DuplicateGuard != nil {
+		b, err := json.Marshal(req)
+		if err != nil {
+			c.releaseKey(ctx, &o)
+			return "", err
+		}
+		dupKey = append([]byte("v2:"), b...)
+	}
+	id, err := c.guarded(dupKey, func() (string, error) {
+		if err := c.waitForRate(ctx); err != nil {
+			return "", err
+		}
+		var res struct{ MessageId string }
+		err := c.callV2(ctx, "SendEmail", "POST", "/outbound-emails", nil, req, &res)
+		return res.MessageId, err
+	})
+	if err != nil {
+		c.releaseKey(ctx, &o)
+		return "", err
+	}
+	c.recordSent(ctx, &o, id)
+	return id, nil
+}
+
+// validateV2 checks in, unless c.SkipValidation is set.
+func (c *Config) validateV2(in *SendEmailInput) error {
+	var to []string
+	if d := in.Destination; d != nil {
+		to = append(append(append(to, d.ToAddresses...), d.CcAddresses...), d.BccAddresses...)
+	}
+	if m := in.Content.Simple; m != nil {
+		return c.validateMessage(in.FromEmailAddress, to, m.Subject, m.Text, m.HTML)
+	}
+	return c.validateEnvelope(in.FromEmailAddress, to)
+}
@@ -0,0 +1,46 @@
+package ses
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestSendEmailV2(t *testing.T) {
+	c := testV2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method != "POST" || r.URL.Path != "/v2/email/outbound-emails" {
+			t.Errorf("got %s %s", r.Method, r.URL.Path)
+		}
+		want := `{"FromEmailAddress":"a@example.com","Destination":{"ToAddresses":["b@example.com"]},"Content":{"Simple":{"Subject":{"Data":"Hi","Charset":"UTF-8"},"Body":{"Html":{"Data":"\u003cp\u003eHello\u003c/p\u003e","Charset":"UTF-8"}}}},"ConfigurationSetName":"cs","EmailTags":[{"Name":"campaign","Value":"welcome"}]}`
+		if string(body) != want {
+			t.Errorf("got body\n%s\nwant\n%s", body, want)
+		}
+		w.Write([]byte(`{"MessageId":"0100-abc"}`))
+	})
+	id, err := c.SendEmailV2(context.Background(), SendEmailInput{
+		FromEmailAddress: "a@example.com",
+		Destination:      &Destination{ToAddresses: []string{"b@example.com"}},
+		Content:          EmailContent{Simple: &SimpleEmail{Subject: "Hi", HTML: "<p>Hello</p>"}},
+	}, WithConfigurationSet("cs"), WithTag("campaign", "welcome"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "0100-abc" {
+		t.Errorf("got message ID %q", id)
+	}
+}
+
+func TestSendEmailV2Raw(t *testing.T) {
+	c := testV2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if want := `{"Content":{"Raw":{"Data":"cmF3"}}}`; string(body) != want {
+			t.Errorf("got body %s, want %s", body, want)
+		}
+		w.Write([]byte(`{"MessageId":"0100-def"}`))
+	})
+	if _, err := c.SendEmailV2(context.Background(), SendEmailInput{Content: EmailContent{Raw: &RawEmail{Data: []byte("raw")}}}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,116 @@
+package ses
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// ValidationError is returned by send calls for a message that SES would reject, before any
+// request is made. Validation can be disabled with Config.SkipValidation.
+type ValidationError struct {
+	// Field is the invalid parameter, such as "from", "to" or "subject".
+	Field string
+	Value string
+
+	// Reason describes what is wrong with Value.
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("ses: invalid %s %q: %s", e.Field, e.Value, e.Reason)
+}
+
+// Is reports whether target is ErrValidation.
+func (e *ValidationError) Is(target error) bool { return target == ErrValidation }
+
+// ValidateAddress checks addr as send calls check their addresses, returning a
+// *ValidationError if SES would reject it.
+func ValidateAddress(addr string) error {
+	return validateAddress("address", addr)
+}
+
+// validateAddress checks that addr is a valid address (optionally with a display name, as in
+// "Name <user@example.com>"), within the length limits of RFC 5321.
+func validateAddress(field, addr string) error {
+	if strings.ContainsAny(addr, "\r\n") {
+		return &ValidationError{field, addr, "contains a line break"}
+	}
+	a, err := mail.ParseAddress(addr)
+	if err != nil {
+		return &ValidationError{field, addr, "not a valid email address"}
+	}
+	at := strings.LastIndex(a.Address, "@")
+	local, domain := a.Address[:at], a.Address[at+1:]
+	switch {
+	case len(a.Address) > 254:
+		return &ValidationError{field, addr, "address longer than 254 characters"}
+	case len(local) > 64:
+		return &ValidationError{field, addr, "local part longer than 64 characters"}
+	case !validDomain(domain):
+		return &ValidationError{field, addr, "not a valid domain"}
+	}
+	return nil
+}
+
+// validDomain reports whether domain is a fully qualified domain name made of valid labels.
+func validDomain(domain string) bool {
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, l := range labels {
+		if len(l) == 0 || len(l) > 63 || l[0] == '-' || l[len(l)-1] == '-' {
+			return false
+		}
+		for _, c := range l {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c > 0x7f) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// validateEnvelope checks the sender (if non-empty) and recipients of a message, unless
+// c.SkipValidation is set.
+func (c *Config) validateEnvelope(from string, to []string) error {
+	if c.SkipValidation {
+		return nil
+	}
+	if from != "" {
+		if err := validateAddress("from", from); err != nil {
+			return err
+		}
+	}
+	for _, addr := range to {
+		if err := validateAddress("to", addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateMessage checks a message that SES formats from a subject and bodies, unless
+// c.SkipValidation is set.
+func (c *Config) validateMessage(from string, to []string, subject, text, html string) error {
+	if c.SkipValidation {
+		return nil
+	}
+	if from == "" {
+		return &ValidationError{"from", from, "empty"}
+	}
+	if len(to) == 0 {
+		return &ValidationError{"to", "", "no recipients"}
+	}
+	if err := c.validateEnvelope(from, to); err != nil {
+		return err
+	}
+	if strings.ContainsAny(subject, "\r\n") {
+		return &ValidationError{"subject", subject, "contains a line break"}
+	}
+	if strings.TrimSpace(text) == "" && strings.TrimSpace(html) == "" {
+		return &ValidationError{"body", "", "empty"}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidation(t *testing.T) {
+	c, form := testServer(t, `<SendEmailResponse/>`)
+	for _, test := range []struct {
+		from, to, subject, body string
+		field                   string
+	}{
+		{"a@example.com", "b@example.com", "s", "b", ""},
+		{"Alice <a@example.com>", "b@example.com", "s", "b", ""},
+		{"", "b@example.com", "s", "b", "from"},
+		{"a@example.com", "not an address", "s", "b", "to"},
+		{"a@example.com", "b@localhost", "s", "b", "to"},
+		{"a@example.com", "b@-example.com", "s", "b", "to"},
+		{"a@example.com", strings.Repeat("x", 65) + "@example.com", "s", "b", "to"},
+		{"a@example.com\r\nBcc: victim@example.com", "b@example.com", "s", "b", "from"},
+		{"a@example.com", "b@example.com", "Hi\r\nBcc: victim@example.com", "b", "subject"},
+		{"a@example.com", "b@example.com", "s", " \n", "body"},
+	} {
+		*form = nil
+		_, err := c.SendEmail(test.from, test.to, test.subject, test.body)
+		var verr *ValidationError
+		if test.field == "" {
+			if err != nil {
+				t.Errorf("%q -> %q: %v", test.from, test.to, err)
+			}
+			continue
+		}
+		if !errors.As(err, &verr) || verr.Field != test.field {
+			t.Errorf("%q -> %q: got error %v, want ValidationError for %s", test.from, test.to, err, test.field)
+		}
+		if *form != nil {
+			t.Errorf("%q -> %q: request was made", test.from, test.to)
+		}
+	}
+}
+
+func TestValidationEnvelope(t *testing.T) {
+	c, _ := testServer(t, `<SendRawEmailResponse/>`)
+	_, err := c.SendRawEmailEnvelope(context.Background(), "a@example.com", []string{"b@example.com", "bad"}, []byte("raw"))
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Value != "bad" {
+		t.Errorf("got %v", err)
+	}
+
+	c.SkipValidation = true
+	if _, err := c.SendRawEmailEnvelope(context.Background(), "a@example.com", []string{"bad"}, []byte("raw")); err != nil {
+		t.Errorf("with SkipValidation: %v", err)
+	}
+}
+
+func TestValidateAddress(t *testing.T) {
+	if err := ValidateAddress("Ann <a@example.com>"); err != nil {
+		t.Error(err)
+	}
+	var verr *ValidationError
+	if err := ValidateAddress("a@localhost"); !errors.As(err, &verr) || verr.Field != "address" {
+		t.Errorf("got %v, want address ValidationError", err)
+	}
+}